@@ -5,16 +5,31 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
-	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
 
+	"github.com/ngikut-project-sprint/GoGoManager/internal/cache"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/health"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/idempotency"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/metrics"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/ratelimit"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/routes"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/server"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/tracing"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
 func main() {
@@ -23,74 +38,260 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Initialize database
-	db := initSQLDatabase(cfg.Database)
-	defer db.Close()
+	logger, err := logging.New(cfg.Log, os.Stdout)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, os.Stdout)
+	if err != nil {
+		logger.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		// Flush the spans of the last requests
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("Failed to flush traces", "error", err)
+		}
+	}()
 
-	// Setup router and handlers
-	mux := routes.NewRouter(cfg, db)
-	log.Fatal(http.ListenAndServe(":8080", mux))
+	// Initialize database, waiting for Postgres to be ready
+	queries, db, closeDB, err := initDatabase(context.Background(), cfg.Database)
+	if err != nil {
+		logger.Error("Failed to connect to the database", "error", err)
+		os.Exit(1)
+	}
+	defer closeDB()
 
-	// Start the web server in a goroutine
-	server := &http.Server{
-		Addr:    ":8080",
-		Handler: mux,
+	if err := metrics.RegisterDatabase(prometheus.DefaultRegisterer, db); err != nil {
+		logger.Error("Failed to register database metrics", "error", err)
+		os.Exit(1)
 	}
 
-	// Channel to listen for OS signals (e.g., Ctrl+C or termination)
-	stopChan := make(chan os.Signal, 1)
-	signal.Notify(stopChan, syscall.SIGINT, syscall.SIGTERM)
+	// Readiness checks the database and its migrations, and once the server
+	// exists, whether it is shutting down and its background workers
+	checker := health.NewChecker()
+	checker.Add("database", health.Database(db))
+	checker.Add("migrations", database.CheckMigrations(db))
 
-	// Run the server in a goroutine
-	go func() {
-		log.Println("Server is running on port 8080")
-		err := server.ListenAndServe()
-		if err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed: %v", err)
-		}
-	}()
+	limitStore, err := initRateLimitStore(cfg.RateLimit)
+	if err != nil {
+		logger.Error("Failed to set up rate limiting", "error", err)
+		os.Exit(1)
+	}
+
+	cacheStore, err := initCacheStore(cfg.Cache)
+	if err != nil {
+		logger.Error("Failed to set up caching", "error", err)
+		os.Exit(1)
+	}
 
-	// Wait for an OS signal to terminate
-	<-stopChan
-	log.Println("Shutting down the server...")
+	idempotencyStore, err := initIdempotencyStore(cfg.Idempotency)
+	if err != nil {
+		logger.Error("Failed to set up idempotency keys", "error", err)
+		os.Exit(1)
+	}
 
-	// Graceful shutdown: wait for active connections to finish
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	// Setup router and handlers, every request gets an id, a logger, the
+	// security and CORS headers, a span and is measured by route. Every
+	// statement is bounded by the query timeout and the request deadline.
+	mux := routes.NewRouter(cfg, queries, checker, routes.NewRateLimits(cfg.RateLimit, limitStore), routes.NewCaches(cfg.Cache, cacheStore),
+		routes.NewIdempotency(cfg.Idempotency, idempotencyStore))
+	handler := routes.NewHandler(mux, cfg, logger)
 
-	// Shut down the server and close DB connection
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("Server shutdown failed:", err)
+	// Stop on an OS signal (e.g., Ctrl+C or termination)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Serve until stopped, then drain in-flight requests and background
+	// workers within the shutdown timeout
+	srv := server.New(cfg.Server, handler)
+	checker.Add("server", srv.CheckServing)
+	checker.Add("workers", srv.CheckWorkers)
+	if memory, ok := limitStore.(*ratelimit.MemoryStore); ok {
+		srv.Go("rate limit sweeper", memory.Sweep)
+	}
+	if memory, ok := idempotencyStore.(*idempotency.MemoryStore); ok {
+		srv.Go("idempotency sweeper", memory.Sweep)
+	}
+	if replicated, ok := queries.(*database.Replicated); ok {
+		srv.Go("replica monitor", replicated.Monitor(cfg.Database.ReplicaCheckInterval))
+	}
+	logger.Info("Server is running", "address", cfg.Server.Address)
+	if err := srv.Run(ctx); err != nil {
+		logger.Error("Server stopped with error", "error", err)
+		return
 	}
 
 	// Database connection will be closed when main function exits
-	log.Println("Server gracefully stopped")
+	logger.Info("Server gracefully stopped")
+}
+
+// initDatabase connects to the database with the configured driver,
+// waiting for it to be ready. The repositories run their statements with the
+// returned database.DB, on the read replicas for the list queries when there
+// are some. The health checks, migrations and metrics use the *sql.DB of the
+// primary. The returned function closes them all.
+func initDatabase(ctx context.Context, dbCfg config.DatabaseConfig) (database.DB, *sql.DB, func(), error) {
+	primary, db, closeDB, err := openDatabase(ctx, dbCfg, dbCfg.DSN())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = database.Retry(ctx, database.NewBackoff(dbCfg.ConnectTimeout), db.PingContext)
+	if err != nil {
+		closeDB()
+		return nil, nil, nil, fmt.Errorf("error connecting to the database: %w", err)
+	}
+
+	if len(dbCfg.ReplicaURLs) == 0 {
+		return primary, db, closeDB, nil
+	}
+
+	// Replicas aren't waited for, reads stay on the primary until they are
+	// found healthy
+	closers := []func(){closeDB}
+	closeAll := func() {
+		for _, close := range closers {
+			close()
+		}
+	}
+	replicas := make([]database.ReplicaDB, 0, len(dbCfg.ReplicaURLs))
+	for _, dsn := range dbCfg.ReplicaURLs {
+		replica, _, closeReplica, err := openDatabase(ctx, dbCfg, dsn)
+		if err != nil {
+			closeAll()
+			return nil, nil, nil, fmt.Errorf("error opening read replica: %w", err)
+		}
+		closers = append(closers, closeReplica)
+		replicas = append(replicas, replica)
+	}
+
+	replicated := database.NewReplicated(primary, replicas, database.ReplicaOptions{
+		StickyFor: dbCfg.ReplicaStickyFor,
+		Session:   managerSession,
+	})
+	return replicated, db, closeAll, nil
+}
+
+// managerSession is the session of the read replicas, the reads of a manager
+// stay on the primary for a while after they wrote
+func managerSession(ctx context.Context) string {
+	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		return ""
+	}
+	return strconv.Itoa(claims.ID)
 }
 
-func initSQLDatabase(dbCfg config.DatabaseConfig) *sql.DB {
-	connStr := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		dbCfg.Username,
-		dbCfg.Password,
-		dbCfg.Host,
-		dbCfg.Port,
-		dbCfg.Database,
-		dbCfg.SslMode,
-	)
-
-	db, err := sql.Open("postgres", connStr)
+// openDatabase opens the database of dsn with the configured driver, without
+// connecting to it yet
+func openDatabase(ctx context.Context, dbCfg config.DatabaseConfig, dsn string) (database.ReplicaDB, *sql.DB, func(), error) {
+	switch dbCfg.Driver {
+	case "pq":
+		db, err := openSQLDatabase(dbCfg, dsn)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return &database.SqlDBAdapter{DB: db, QueryTimeout: dbCfg.QueryTimeout}, db, func() { db.Close() }, nil
+	case "pgx":
+		pool, err := openPgxPool(ctx, dbCfg, dsn)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		db := stdlib.OpenDBFromPool(pool)
+		closeDB := func() {
+			db.Close()
+			pool.Close()
+		}
+		return &database.PgxAdapter{Pool: pool, QueryTimeout: dbCfg.QueryTimeout}, db, closeDB, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("invalid database driver %q, expected pq or pgx", dbCfg.Driver)
+	}
+}
+
+func openSQLDatabase(dbCfg config.DatabaseConfig, dsn string) (*sql.DB, error) {
+	// Every statement is traced, see tracing.OpenDB
+	db, err := tracing.OpenDB("postgres", dsn)
 	if err != nil {
-		log.Fatalf("Error opening connection: %v", err)
+		return nil, fmt.Errorf("error opening connection: %w", err)
 	}
 
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(2 * time.Hour)
+	db.SetMaxOpenConns(dbCfg.MaxOpenConns)
+	db.SetMaxIdleConns(dbCfg.MaxIdleConns)
+	db.SetConnMaxLifetime(dbCfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(dbCfg.ConnMaxIdleTime)
+	return db, nil
+}
+
+func openPgxPool(ctx context.Context, dbCfg config.DatabaseConfig, dsn string) (*pgxpool.Pool, error) {
+	poolCfg, err := database.PgxPoolConfig(dbCfg, dsn)
+	if err != nil {
+		return nil, err
+	}
+	// Every statement is traced, see tracing.PgxTracer
+	poolCfg.ConnConfig.Tracer = tracing.PgxTracer{}
 
-	err = db.Ping()
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
-		log.Fatalf("Error connecting to the database: %v", err)
+		return nil, fmt.Errorf("error opening connection: %w", err)
+	}
+	return pool, nil
+}
+
+// initRateLimitStore returns the store of the rate limits, nil when rate
+// limiting is disabled
+func initRateLimitStore(cfg config.RateLimitConfig) (ratelimit.Store, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	switch cfg.Store {
+	case "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddress, Password: cfg.RedisPassword})
+		return ratelimit.NewRedisStore(client, "gogomanager:ratelimit:"), nil
+	default:
+		return nil, fmt.Errorf("invalid rate limit store %q, expected memory or redis", cfg.Store)
+	}
+}
+
+// initCacheStore returns the store of the caches, nil when caching is
+// disabled
+func initCacheStore(cfg config.CacheConfig) (cache.Store, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	switch cfg.Store {
+	case "memory":
+		return cache.NewMemoryStore(cfg.Size), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddress, Password: cfg.RedisPassword})
+		return cache.NewRedisStore(client, "gogomanager:cache:"), nil
+	default:
+		return nil, fmt.Errorf("invalid cache store %q, expected memory or redis", cfg.Store)
+	}
+}
+
+// initIdempotencyStore returns the store of the idempotency keys, nil when
+// they are disabled
+func initIdempotencyStore(cfg config.IdempotencyConfig) (idempotency.Store, error) {
+	if !cfg.Enabled {
+		return nil, nil
 	}
 
-	return db
+	switch cfg.Store {
+	case "memory":
+		return idempotency.NewMemoryStore(), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddress, Password: cfg.RedisPassword})
+		return idempotency.NewRedisStore(client, "gogomanager:idempotency:"), nil
+	default:
+		return nil, fmt.Errorf("invalid idempotency store %q, expected memory or redis", cfg.Store)
+	}
 }
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/metrics"
+)
+
+// ErrMiss is returned by Store.Get for a key it doesn't hold
+var ErrMiss = errors.New("cache miss")
+
+// Store keeps encoded values, by key
+type Store interface {
+	// Get returns the value of key, ErrMiss when missing or expired
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set keeps value for key during ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete drops the values of keys, missing ones are ignored
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Cache keeps values of type T in a Store, encoded as JSON under keys
+// prefixed by its name. The lookups are counted by name, see
+// metrics.CacheRequests. The store being unavailable only makes every
+// lookup a miss. Without a store it caches nothing.
+type Cache[T any] struct {
+	store Store
+	name  string
+	ttl   time.Duration
+}
+
+func New[T any](store Store, name string, ttl time.Duration) *Cache[T] {
+	return &Cache[T]{store: store, name: name, ttl: ttl}
+}
+
+func (c *Cache[T]) key(key string) string {
+	return c.name + ":" + key
+}
+
+// Get returns the value of key, loading and keeping it on a miss. Errors of
+// load are returned as is and not cached.
+func (c *Cache[T]) Get(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if c.store == nil {
+		return load(ctx)
+	}
+
+	logger := logging.FromContext(ctx)
+	data, err := c.store.Get(ctx, c.key(key))
+	switch {
+	case err == nil:
+		var value T
+		if err := json.Unmarshal(data, &value); err != nil {
+			logger.Warn("Failed to decode cached value", "cache", c.name, "error", err)
+			break
+		}
+		metrics.RecordCache(c.name, true)
+		return value, nil
+	case !errors.Is(err, ErrMiss):
+		logger.Warn("Failed to read cache", "cache", c.name, "error", err)
+	}
+	metrics.RecordCache(c.name, false)
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	data, err = json.Marshal(value)
+	if err == nil {
+		err = c.store.Set(ctx, c.key(key), data, c.ttl)
+	}
+	if err != nil {
+		logger.Warn("Failed to write cache", "cache", c.name, "error", err)
+	}
+	return value, nil
+}
+
+// Invalidate drops the values of keys, once they are updated or deleted
+func (c *Cache[T]) Invalidate(ctx context.Context, keys ...string) {
+	if c.store == nil || len(keys) == 0 {
+		return
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.key(key)
+	}
+	if err := c.store.Delete(ctx, prefixed...); err != nil {
+		logging.FromContext(ctx).Warn("Failed to invalidate cache", "cache", c.name, "error", err)
+	}
+}
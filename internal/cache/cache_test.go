@@ -0,0 +1,166 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/cache"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/metrics"
+)
+
+func newRedisStore(t *testing.T) (*cache.RedisStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return cache.NewRedisStore(client, "test:"), server
+}
+
+func TestStores_SetGetDelete(t *testing.T) {
+	redisStore, _ := newRedisStore(t)
+	stores := map[string]cache.Store{
+		"memory": cache.NewMemoryStore(10),
+		"redis":  redisStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, err := store.Get(ctx, "department:1")
+			assert.ErrorIs(t, err, cache.ErrMiss)
+
+			require.NoError(t, store.Set(ctx, "department:1", []byte("one"), time.Minute))
+			require.NoError(t, store.Set(ctx, "department:2", []byte("two"), time.Minute))
+			value, err := store.Get(ctx, "department:1")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("one"), value)
+
+			require.NoError(t, store.Delete(ctx, "department:1", "department:3"))
+			_, err = store.Get(ctx, "department:1")
+			assert.ErrorIs(t, err, cache.ErrMiss)
+			value, err = store.Get(ctx, "department:2")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("two"), value)
+		})
+	}
+}
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := cache.NewMemoryStore(2)
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "a", []byte("a"), time.Minute))
+	require.NoError(t, store.Set(ctx, "b", []byte("b"), time.Minute))
+	// Reading a makes b the least recently used
+	_, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, store.Set(ctx, "c", []byte("c"), time.Minute))
+
+	assert.Equal(t, 2, store.Len())
+	_, err = store.Get(ctx, "b")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+	_, err = store.Get(ctx, "a")
+	assert.NoError(t, err)
+	_, err = store.Get(ctx, "c")
+	assert.NoError(t, err)
+}
+
+func TestMemoryStore_Expires(t *testing.T) {
+	store := cache.NewMemoryStore(10)
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "a", []byte("a"), 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := store.Get(ctx, "a")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestRedisStore_Expires(t *testing.T) {
+	store, server := newRedisStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "a", []byte("a"), time.Second))
+	assert.True(t, server.Exists("test:a"))
+	server.FastForward(2 * time.Second)
+
+	_, err := store.Get(ctx, "a")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+}
+
+type profile struct {
+	ID   int
+	Name string
+}
+
+func TestCache_LoadsOnMissAndCountsLookups(t *testing.T) {
+	profiles := cache.New[profile](cache.NewMemoryStore(10), "test_profile", time.Minute)
+	ctx := context.Background()
+	hits := metrics.CacheRequests.WithLabelValues("test_profile", "hit")
+	misses := metrics.CacheRequests.WithLabelValues("test_profile", "miss")
+
+	loads := 0
+	load := func(ctx context.Context) (profile, error) {
+		loads++
+		return profile{ID: 1, Name: "Ana"}, nil
+	}
+	for range 3 {
+		value, err := profiles.Get(ctx, "1", load)
+		require.NoError(t, err)
+		assert.Equal(t, profile{ID: 1, Name: "Ana"}, value)
+	}
+	assert.Equal(t, 1, loads)
+
+	profiles.Invalidate(ctx, "1")
+	_, err := profiles.Get(ctx, "1", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(hits))
+	assert.Equal(t, float64(2), testutil.ToFloat64(misses))
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	profiles := cache.New[profile](cache.NewMemoryStore(10), "test_error", time.Minute)
+	errNotFound := errors.New("not found")
+
+	loads := 0
+	load := func(ctx context.Context) (profile, error) {
+		loads++
+		return profile{}, errNotFound
+	}
+	for range 2 {
+		_, err := profiles.Get(context.Background(), "1", load)
+		assert.ErrorIs(t, err, errNotFound)
+	}
+	assert.Equal(t, 2, loads)
+}
+
+func TestCache_LoadsWhenStoreUnavailable(t *testing.T) {
+	store, server := newRedisStore(t)
+	server.Close()
+	profiles := cache.New[profile](store, "test_unavailable", time.Minute)
+
+	value, err := profiles.Get(context.Background(), "1", func(ctx context.Context) (profile, error) {
+		return profile{ID: 1}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, value.ID)
+
+	// Without a store nothing is cached
+	profiles = cache.New[profile](nil, "test_none", time.Minute)
+	value, err = profiles.Get(context.Background(), "1", func(ctx context.Context) (profile, error) {
+		return profile{ID: 2}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, value.ID)
+	profiles.Invalidate(context.Background(), "1")
+}
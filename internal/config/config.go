@@ -1,10 +1,16 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
 
+// DatabaseConfig configures the connection to Postgres. Driver is pq, for
+// lib/pq through database/sql, or pgx, for a pgx connection pool caching the
+// prepared statements of every connection.
 type DatabaseConfig struct {
 	Username string `env:"DB_USER"`
 	Password string `env:"DB_PASSWORD"`
@@ -12,15 +18,164 @@ type DatabaseConfig struct {
 	Port     uint16 `env:"DB_PORT"`
 	Database string `env:"DB_NAME"`
 	SslMode  string `env:"DB_SSLMODE"`
+	Driver   string `env:"DB_DRIVER" env-default:"pq"`
+	// QueryTimeout bounds every statement, on top of the deadline of the
+	// request running it. Zero disables it.
+	QueryTimeout    time.Duration `env:"DB_QUERY_TIMEOUT" env-default:"5s"`
+	MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" env-default:"10"`
+	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" env-default:"2h"`
+	ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" env-default:"30m"`
+	// MaxIdleConns caps the idle connections of pq
+	MaxIdleConns int `env:"DB_MAX_IDLE_CONNS" env-default:"5"`
+	// MinConns is how many connections pgx keeps open even when idle
+	MinConns int `env:"DB_MIN_CONNS" env-default:"0"`
+	// StatementCacheCapacity is how many prepared statements pgx caches per
+	// connection, zero describes the statements on every run instead
+	StatementCacheCapacity int `env:"DB_STATEMENT_CACHE_CAPACITY" env-default:"512"`
+	// ConnectTimeout is how long the API retries connecting at startup,
+	// while Postgres isn't ready yet
+	ConnectTimeout time.Duration `env:"DB_CONNECT_TIMEOUT" env-default:"1m"`
+	// ReplicaURLs are the URLs of read replicas, the list queries run on
+	// them while they are healthy
+	ReplicaURLs []string `env:"DB_REPLICA_URLS" env-separator:","`
+	// ReplicaStickyFor is how long the reads of a manager stay on the
+	// primary after they wrote, so they read their writes back
+	ReplicaStickyFor time.Duration `env:"DB_REPLICA_STICKY_FOR" env-default:"5s"`
+	// ReplicaCheckInterval is how often the replicas are pinged
+	ReplicaCheckInterval time.Duration `env:"DB_REPLICA_CHECK_INTERVAL" env-default:"5s"`
+}
+
+// DSN returns the URL of the database
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		c.Username,
+		c.Password,
+		c.Host,
+		c.Port,
+		c.Database,
+		c.SslMode,
+	)
 }
 
 type JWTConfig struct {
 	Secret string `env:"JWT_SECRET"`
 }
 
+// ServerConfig configures the HTTP listener. Durations are written like
+// 15s or 1m.
+type ServerConfig struct {
+	Address           string        `env:"SERVER_ADDRESS" env-default:":8080"`
+	ReadTimeout       time.Duration `env:"SERVER_READ_TIMEOUT" env-default:"15s"`
+	ReadHeaderTimeout time.Duration `env:"SERVER_READ_HEADER_TIMEOUT" env-default:"5s"`
+	WriteTimeout      time.Duration `env:"SERVER_WRITE_TIMEOUT" env-default:"30s"`
+	IdleTimeout       time.Duration `env:"SERVER_IDLE_TIMEOUT" env-default:"60s"`
+	MaxHeaderBytes    int           `env:"SERVER_MAX_HEADER_BYTES" env-default:"1048576"`
+	MaxBodyBytes      int64         `env:"SERVER_MAX_BODY_BYTES" env-default:"1048576"`
+	// ShutdownDelay is how long the server keeps serving, with its readiness
+	// failing, once asked to stop, so load balancers take it out first
+	ShutdownDelay time.Duration `env:"SERVER_SHUTDOWN_DELAY" env-default:"5s"`
+	// ShutdownTimeout is how long in-flight requests and background workers
+	// get to finish once the server is asked to stop
+	ShutdownTimeout time.Duration `env:"SERVER_SHUTDOWN_TIMEOUT" env-default:"20s"`
+}
+
+// LogConfig configures the structured logger. Level is debug, info, warn
+// or error and Format json or text.
+type LogConfig struct {
+	Level  string `env:"LOG_LEVEL" env-default:"info"`
+	Format string `env:"LOG_FORMAT" env-default:"json"`
+}
+
+// TracingConfig configures the OpenTelemetry traces. Exporter is none, stdout
+// or otlp, the latter sending them over HTTP to OTLPEndpoint, like
+// localhost:4318 for a local collector.
+type TracingConfig struct {
+	Exporter     string  `env:"TRACING_EXPORTER" env-default:"none"`
+	OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" env-default:"localhost:4318"`
+	OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" env-default:"true"`
+	ServiceName  string  `env:"TRACING_SERVICE_NAME" env-default:"gogomanager"`
+	SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+// RateLimitConfig configures the token buckets limiting the requests, by
+// client IP on /v1/auth and by manager on the protected routes. Store is
+// memory, each instance limiting on its own, or redis, shared by every
+// instance. Rates are the tokens refilled per second, bursts the size of
+// the buckets.
+type RateLimitConfig struct {
+	Enabled           bool    `env:"RATE_LIMIT_ENABLED" env-default:"true"`
+	Store             string  `env:"RATE_LIMIT_STORE" env-default:"memory"`
+	RedisAddress      string  `env:"RATE_LIMIT_REDIS_ADDRESS" env-default:"localhost:6379"`
+	RedisPassword     string  `env:"RATE_LIMIT_REDIS_PASSWORD"`
+	TrustForwardedFor bool    `env:"RATE_LIMIT_TRUST_FORWARDED_FOR" env-default:"false"`
+	AuthRate          float64 `env:"RATE_LIMIT_AUTH_RATE" env-default:"0.2"`
+	AuthBurst         int     `env:"RATE_LIMIT_AUTH_BURST" env-default:"10"`
+	APIRate           float64 `env:"RATE_LIMIT_API_RATE" env-default:"10"`
+	APIBurst          int     `env:"RATE_LIMIT_API_BURST" env-default:"50"`
+}
+
+// CacheConfig configures the cache of the manager profiles and department
+// owners. Store is memory, an LRU of Size entries in each instance, or redis,
+// shared by every instance. With several instances prefer redis, an update
+// only invalidates the memory cache of its own instance. TTL bounds how long
+// an entry is served.
+type CacheConfig struct {
+	Enabled       bool          `env:"CACHE_ENABLED" env-default:"true"`
+	Store         string        `env:"CACHE_STORE" env-default:"memory"`
+	Size          int           `env:"CACHE_SIZE" env-default:"10000"`
+	TTL           time.Duration `env:"CACHE_TTL" env-default:"1m"`
+	RedisAddress  string        `env:"CACHE_REDIS_ADDRESS" env-default:"localhost:6379"`
+	RedisPassword string        `env:"CACHE_REDIS_PASSWORD"`
+}
+
+// IdempotencyConfig configures the Idempotency-Key header of the POST
+// routes. The first response of every key is kept for TTL, and replayed to
+// the retries of the same manager sending the same request. LockTimeout
+// bounds how long a request being served holds its key, keep it above the
+// longest request or retries sent meanwhile run again. Store is memory,
+// each instance replaying the requests it served, or redis, shared by every
+// instance.
+type IdempotencyConfig struct {
+	Enabled       bool          `env:"IDEMPOTENCY_ENABLED" env-default:"true"`
+	Store         string        `env:"IDEMPOTENCY_STORE" env-default:"memory"`
+	TTL           time.Duration `env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	LockTimeout   time.Duration `env:"IDEMPOTENCY_LOCK_TIMEOUT" env-default:"1m"`
+	RedisAddress  string        `env:"IDEMPOTENCY_REDIS_ADDRESS" env-default:"localhost:6379"`
+	RedisPassword string        `env:"IDEMPOTENCY_REDIS_PASSWORD"`
+}
+
+// CORSConfig configures the cross-origin requests browsers may send. Lists
+// are comma separated, no allowed origin disables CORS and * allows any.
+// MaxAge is how long browsers cache a preflight response.
+type CORSConfig struct {
+	AllowedOrigins   []string      `env:"CORS_ALLOWED_ORIGINS" env-separator:","`
+	AllowedMethods   []string      `env:"CORS_ALLOWED_METHODS" env-separator:"," env-default:"GET,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders   []string      `env:"CORS_ALLOWED_HEADERS" env-separator:"," env-default:"Authorization,Content-Type,X-Request-ID,If-Match,If-None-Match,Idempotency-Key"`
+	ExposedHeaders   []string      `env:"CORS_EXPOSED_HEADERS" env-separator:"," env-default:"X-Request-ID,Retry-After,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,ETag,Last-Modified,Idempotent-Replayed"`
+	AllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" env-default:"false"`
+	MaxAge           time.Duration `env:"CORS_MAX_AGE" env-default:"10m"`
+}
+
+// FileConfig configures the files uploaded to POST /v1/file, written to Dir
+// and downloaded from /files/. BaseURL prefixes the URIs returned for them,
+// like https://api.example.com, without it they are relative.
+type FileConfig struct {
+	Dir     string `env:"FILE_DIR" env-default:"uploads"`
+	BaseURL string `env:"FILE_BASE_URL"`
+}
+
 type Config struct {
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server      ServerConfig
+	Log         LogConfig
+	Tracing     TracingConfig
+	RateLimit   RateLimitConfig
+	Cache       CacheConfig
+	Idempotency IdempotencyConfig
+	CORS        CORSConfig
+	Files       FileConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
 }
 
 func Get() (*Config, error) {
@@ -4,3 +4,18 @@ type contextKey string
 
 const ConfigKey contextKey = "app-config"
 const JWTKey contextKey = "jwt-claims"
+
+const RequestIDKey contextKey = "request-id"
+
+// RequestIDHeader carries the request id, set by the client or generated
+const RequestIDHeader = "X-Request-ID"
+
+// LoggerKey holds the logger of the request, see logging.FromContext
+const LoggerKey contextKey = "logger"
+
+// TxKey holds the transaction of a unit of work, see database.DB.InTx
+const TxKey contextKey = "db-transaction"
+
+// ReadOnlyKey marks statements that may run on a read replica, see
+// database.ReadOnly
+const ReadOnlyKey contextKey = "db-read-only"
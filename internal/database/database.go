@@ -1,6 +1,18 @@
 package database
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+)
 
 type Row interface {
 	Scan(dest ...interface{}) error
@@ -13,24 +25,175 @@ type Rows interface {
 	Scan(dest ...interface{}) error
 }
 
+// Transactor runs units of work in a transaction, see DB.InTx
+type Transactor interface {
+	// InTx runs fn in a transaction, committed when fn returns nil and
+	// rolled back otherwise. Every statement run with the context given to
+	// fn, by any repository, is part of the transaction. Calls nested in fn
+	// join its transaction.
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// DB runs the statements of the repositories. The context variants run in
+// the transaction of the context, if any.
 type DB interface {
+	Transactor
+
 	QueryRow(query string, args ...interface{}) Row
 	Query(query string, args ...interface{}) (Rows, error)
 	Exec(query string, args ...interface{}) (sql.Result, error)
+
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }
 
+// SqlDBAdapter runs the statements on lib/pq. Like PgxAdapter it reads and
+// writes arrays as plain slices, e.g. []int64 for an int2[] column.
 type SqlDBAdapter struct {
 	*sql.DB
+	// QueryTimeout bounds every statement, zero leaves them to the deadline
+	// of their context
+	QueryTimeout time.Duration
 }
 
+// QueryRow runs without a context, only bounded by the QueryTimeout
 func (db *SqlDBAdapter) QueryRow(query string, args ...interface{}) Row {
-	return db.DB.QueryRow(query, args...)
+	return db.QueryRowContext(context.Background(), query, args...)
 }
 
 func (db *SqlDBAdapter) Query(query string, args ...interface{}) (Rows, error) {
-	return db.DB.Query(query, args...)
+	return db.QueryContext(context.Background(), query, args...)
 }
 
 func (db *SqlDBAdapter) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return db.DB.Exec(query, args...)
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// executor runs statements on the database or in a transaction
+type executor interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// executor returns the transaction of ctx, or the database outside of one
+func (db *SqlDBAdapter) executor(ctx context.Context) executor {
+	if tx, ok := ctx.Value(constants.TxKey).(*sql.Tx); ok {
+		return tx
+	}
+	return db.DB
+}
+
+// withTimeout bounds a statement by timeout, if any. The statement must
+// release the returned cancel once done with its result.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (db *SqlDBAdapter) QueryRowContext(ctx context.Context, query string, args ...interface{}) Row {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	return &row{Row: db.executor(ctx).QueryRowContext(ctx, query, pqArrays(args)...), cancel: cancel}
+}
+
+func (db *SqlDBAdapter) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	result, err := db.executor(ctx).QueryContext(ctx, query, pqArrays(args)...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &rows{Rows: result, cancel: cancel}, nil
+}
+
+func (db *SqlDBAdapter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	return db.executor(ctx).ExecContext(ctx, query, pqArrays(args)...)
+}
+
+// row releases the timeout of its statement once scanned
+type row struct {
+	*sql.Row
+	cancel context.CancelFunc
+}
+
+func (r *row) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	return r.Row.Scan(pqArrays(dest)...)
+}
+
+// rows releases the timeout of its statement once closed
+type rows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+func (r *rows) Scan(dest ...interface{}) error {
+	return r.Rows.Scan(pqArrays(dest)...)
+}
+
+func (r *rows) Close() error {
+	defer r.cancel()
+	return r.Rows.Close()
+}
+
+// pqArrays wraps the slices among the arguments or scan destinations of a
+// statement in pq.Array, the only way lib/pq reads and writes arrays
+func pqArrays(values []interface{}) []interface{} {
+	var wrapped []interface{}
+	for i, value := range values {
+		if !isArray(value) {
+			continue
+		}
+		if wrapped == nil {
+			wrapped = slices.Clone(values)
+		}
+		wrapped[i] = pq.Array(value)
+	}
+
+	if wrapped == nil {
+		return values
+	}
+	return wrapped
+}
+
+// isArray tells whether value is a slice, or points to one, that isn't
+// bytes nor handles its own conversion
+func isArray(value interface{}) bool {
+	switch value.(type) {
+	case nil, driver.Valuer, sql.Scanner:
+		return false
+	}
+
+	t := reflect.TypeOf(value)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+func (db *SqlDBAdapter) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(constants.TxKey).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := fn(context.WithValue(ctx, constants.TxKey, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,182 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+)
+
+// txDriver records the transactions and the statements run in and out of
+// them
+type txDriver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (d *txDriver) record(event string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, event)
+}
+
+func (d *txDriver) Open(string) (driver.Conn, error) { return &txConn{driver: d}, nil }
+
+type txConn struct {
+	driver *txDriver
+	inTx   bool
+}
+
+func (c *txConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *txConn) Close() error                        { return nil }
+
+func (c *txConn) Begin() (driver.Tx, error) {
+	c.driver.record("begin")
+	c.inTx = true
+	return c, nil
+}
+
+func (c *txConn) Commit() error {
+	c.driver.record("commit")
+	c.inTx = false
+	return nil
+}
+
+func (c *txConn) Rollback() error {
+	c.driver.record("rollback")
+	c.inTx = false
+	return nil
+}
+
+// ExecContext runs until the context is done for the "SLOW" statement, and
+// records the arguments of the "ARGS" statement
+func (c *txConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query == "SLOW" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if query == "ARGS" {
+		for _, arg := range args {
+			query += fmt.Sprintf(" %v", arg.Value)
+		}
+	}
+	if c.inTx {
+		query = "tx: " + query
+	}
+	c.driver.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+// QueryContext returns a row holding an int2[] the way lib/pq reads it
+func (c *txConn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	return &arrayRows{}, nil
+}
+
+type arrayRows struct {
+	done bool
+}
+
+func (r *arrayRows) Columns() []string { return []string{"work_days"} }
+func (r *arrayRows) Close() error      { return nil }
+
+func (r *arrayRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = []byte("{1,5}")
+	return nil
+}
+
+func newTxDB(t *testing.T, name string) (*database.SqlDBAdapter, *txDriver) {
+	d := &txDriver{}
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return &database.SqlDBAdapter{DB: db}, d
+}
+
+func TestSqlDBAdapter_InTx_Commits(t *testing.T) {
+	db, d := newTxDB(t, "tx-commit")
+	ctx := context.Background()
+
+	err := db.InTx(ctx, func(ctx context.Context) error {
+		if _, err := db.ExecContext(ctx, "UPDATE employees"); err != nil {
+			return err
+		}
+		// Nested units of work join the transaction
+		return db.InTx(ctx, func(ctx context.Context) error {
+			_, err := db.ExecContext(ctx, "UPDATE departments")
+			return err
+		})
+	})
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, "UPDATE managers")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"begin", "tx: UPDATE employees", "tx: UPDATE departments", "commit", "UPDATE managers"}, d.events)
+}
+
+func TestSqlDBAdapter_InTx_RollsBackOnError(t *testing.T) {
+	db, d := newTxDB(t, "tx-rollback")
+	errFailed := errors.New("failed")
+
+	err := db.InTx(context.Background(), func(ctx context.Context) error {
+		if _, err := db.ExecContext(ctx, "UPDATE employees"); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+
+	assert.Equal(t, []string{"begin", "tx: UPDATE employees", "rollback"}, d.events)
+}
+
+func TestSqlDBAdapter_QueryTimeout(t *testing.T) {
+	db, _ := newTxDB(t, "tx-timeout")
+	db.QueryTimeout = 10 * time.Millisecond
+
+	_, err := db.ExecContext(context.Background(), "SLOW")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Cancelling the context, like a client disconnecting, stops it sooner
+	db.QueryTimeout = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = db.ExecContext(ctx, "SLOW")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSqlDBAdapter_Arrays(t *testing.T) {
+	db, d := newTxDB(t, "tx-arrays")
+	ctx := context.Background()
+
+	// Repositories pass and scan plain slices, like on pgx
+	_, err := db.ExecContext(ctx, "ARGS", []int64{1, 5}, []byte("raw"), "text")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ARGS {1,5} [114 97 119] text"}, d.events)
+
+	var workDays []int64
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT work_days").Scan(&workDays))
+	assert.Equal(t, []int64{1, 5}, workDays)
+
+	rows, err := db.QueryContext(ctx, "SELECT work_days")
+	require.NoError(t, err)
+	defer rows.Close()
+	require.True(t, rows.Next())
+	workDays = nil
+	require.NoError(t, rows.Scan(&workDays))
+	assert.Equal(t, []int64{1, 5}, workDays)
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// versionLength is the length of the numeric prefix of migration files,
+// like 0008 in 0008_schema_migrations.sql
+const versionLength = 4
+
+// ErrPendingMigrations fails the readiness of an API whose database misses
+// the newest migration
+var ErrPendingMigrations = errors.New("database migrations are pending")
+
+// Migrations returns the migration files in the order they are applied
+func Migrations() []string {
+	files, _ := fs.Glob(migrations, "migrations/*.sql")
+	sort.Strings(files)
+	return files
+}
+
+// LatestMigration returns the version of the newest migration, like 0008
+func LatestMigration() string {
+	files := Migrations()
+	if len(files) == 0 {
+		return ""
+	}
+	return MigrationVersion(files[len(files)-1])
+}
+
+// MigrationVersion returns the version of a migration file
+func MigrationVersion(file string) string {
+	name := file[len("migrations/"):]
+	if len(name) < versionLength {
+		return name
+	}
+	return name[:versionLength]
+}
+
+// CheckMigrations returns a readiness check failing with
+// ErrPendingMigrations until the newest migration is recorded in the
+// schema_migrations table of db
+func CheckMigrations(db *sql.DB) func(ctx context.Context) error {
+	latest := LatestMigration()
+	return func(ctx context.Context) error {
+		var applied bool
+		err := db.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", latest,
+		).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("read applied migrations: %w", err)
+		}
+		if !applied {
+			return fmt.Errorf("%w, %s is not applied", ErrPendingMigrations, latest)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,21 @@
+-- Manager-defined custom field schemas (1 manager -> N custom fields)
+CREATE TABLE employee_custom_fields (
+  id SERIAL NOT NULL,
+  manager_id INT NOT NULL,
+  key VARCHAR(33) NOT NULL,
+  label VARCHAR(52) NOT NULL,
+  type VARCHAR(16) NOT NULL,
+  required BOOLEAN NOT NULL DEFAULT FALSE,
+  allowed_values JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(id),
+  FOREIGN KEY(manager_id) REFERENCES managers(id),
+  CONSTRAINT unique_custom_field_key UNIQUE (manager_id, key),
+  CONSTRAINT valid_custom_field_type CHECK (type IN ('string', 'number', 'boolean', 'date', 'enum'))
+);
+
+-- Custom field values of every employee, keyed by employee_custom_fields.key
+ALTER TABLE employees ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_employees_custom_fields ON employees USING GIN (custom_fields);
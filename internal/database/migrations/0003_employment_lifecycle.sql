@@ -0,0 +1,29 @@
+-- Enum type for employment status
+CREATE TYPE EMPLOYMENT_STATUS AS ENUM ('probation', 'active', 'on_leave', 'terminated');
+
+-- Employment lifecycle of every employee, existing employees are active
+-- since the day they were created
+ALTER TABLE employees
+  ADD COLUMN employment_status EMPLOYMENT_STATUS NOT NULL DEFAULT 'active',
+  ADD COLUMN hire_date DATE,
+  ADD COLUMN termination_date DATE,
+  ADD COLUMN termination_reason VARCHAR(255);
+
+UPDATE employees SET hire_date = created_at::DATE WHERE hire_date IS NULL;
+
+CREATE INDEX idx_employees_employment_status ON employees (employment_status);
+
+-- Status transitions (1 employee -> N status changes)
+CREATE TABLE employee_status_history (
+  id SERIAL NOT NULL,
+  employee_id INT NOT NULL,
+  from_status EMPLOYMENT_STATUS NOT NULL,
+  to_status EMPLOYMENT_STATUS NOT NULL,
+  reason VARCHAR(255),
+  effective_date DATE NOT NULL,
+  changed_by INT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(id),
+  FOREIGN KEY(employee_id) REFERENCES employees(id),
+  FOREIGN KEY(changed_by) REFERENCES managers(id)
+);
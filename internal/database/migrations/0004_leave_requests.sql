@@ -0,0 +1,37 @@
+-- Enum types for leave requests
+CREATE TYPE LEAVE_TYPE AS ENUM ('annual', 'sick', 'unpaid');
+CREATE TYPE LEAVE_STATUS AS ENUM ('pending', 'approved', 'rejected', 'cancelled');
+
+-- Leave requests (1 employee -> N leave requests)
+CREATE TABLE leave_requests (
+  id SERIAL NOT NULL,
+  employee_id INT NOT NULL,
+  leave_type LEAVE_TYPE NOT NULL,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  days INT NOT NULL,
+  reason VARCHAR(255),
+  status LEAVE_STATUS NOT NULL DEFAULT 'pending',
+  decided_by INT,
+  decided_at TIMESTAMP,
+  decision_note VARCHAR(255),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(id),
+  FOREIGN KEY(employee_id) REFERENCES employees(id),
+  FOREIGN KEY(decided_by) REFERENCES managers(id),
+  CONSTRAINT valid_leave_range CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_leave_requests_employee_range ON leave_requests (employee_id, start_date, end_date);
+
+-- Yearly leave allowance per employee and leave type, accrued on first use
+CREATE TABLE leave_balances (
+  employee_id INT NOT NULL,
+  year INT NOT NULL,
+  leave_type LEAVE_TYPE NOT NULL,
+  allowance INT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(employee_id, year, leave_type),
+  FOREIGN KEY(employee_id) REFERENCES employees(id)
+);
@@ -0,0 +1,34 @@
+-- Working schedule per department, departments without one use the
+-- application default (09:00-17:00, Monday to Friday, UTC)
+CREATE TABLE department_schedules (
+  department_id INT NOT NULL,
+  start_time TIME NOT NULL,
+  end_time TIME NOT NULL,
+  grace_minutes INT NOT NULL DEFAULT 0,
+  work_days SMALLINT[] NOT NULL,
+  timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(department_id),
+  FOREIGN KEY(department_id) REFERENCES departments(department_id) ON DELETE CASCADE,
+  CONSTRAINT valid_schedule_range CHECK (end_time > start_time)
+);
+
+-- Check-in/check-out records (1 employee -> N records), check_out is NULL
+-- while the employee is checked in
+CREATE TABLE attendance_records (
+  id SERIAL NOT NULL,
+  employee_id INT NOT NULL,
+  check_in TIMESTAMPTZ NOT NULL,
+  check_out TIMESTAMPTZ,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(id),
+  FOREIGN KEY(employee_id) REFERENCES employees(id),
+  CONSTRAINT valid_attendance_range CHECK (check_out IS NULL OR check_out >= check_in)
+);
+
+CREATE INDEX idx_attendance_records_employee_check_in ON attendance_records (employee_id, check_in);
+
+-- At most one open record per employee
+CREATE UNIQUE INDEX unique_open_attendance ON attendance_records (employee_id) WHERE check_out IS NULL;
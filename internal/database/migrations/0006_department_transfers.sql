@@ -0,0 +1,22 @@
+-- Enum type for department transfers
+CREATE TYPE TRANSFER_STATUS AS ENUM ('pending', 'accepted', 'rejected', 'cancelled');
+
+-- Department hand-overs between managers, proposed by the current owner and
+-- accepted by the receiving manager
+CREATE TABLE department_transfers (
+  id SERIAL NOT NULL,
+  department_id INT NOT NULL,
+  from_manager_id INT NOT NULL,
+  to_manager_id INT NOT NULL,
+  status TRANSFER_STATUS NOT NULL DEFAULT 'pending',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  decided_at TIMESTAMP,
+  PRIMARY KEY(id),
+  FOREIGN KEY(department_id) REFERENCES departments(department_id) ON DELETE CASCADE,
+  FOREIGN KEY(from_manager_id) REFERENCES managers(id),
+  FOREIGN KEY(to_manager_id) REFERENCES managers(id),
+  CONSTRAINT different_managers CHECK (from_manager_id <> to_manager_id)
+);
+
+-- At most one pending transfer per department
+CREATE UNIQUE INDEX unique_pending_transfer ON department_transfers (department_id) WHERE status = 'pending';
@@ -0,0 +1,6 @@
+-- Identity numbers are unique among the employees that are not deleted, so a
+-- deleted employee's number can be reused
+CREATE UNIQUE INDEX unique_identity_number ON employees(identity_number) WHERE deleted_at IS NULL;
+
+-- Image URIs have no length limit in the employee contract
+ALTER TABLE employees ALTER COLUMN employee_image_uri TYPE TEXT;
@@ -0,0 +1,13 @@
+-- Versions of the applied migrations, read by the readiness check. Every
+-- migration from this one on records its version last.
+CREATE TABLE schema_migrations (
+  version VARCHAR(4) NOT NULL,
+  applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(version)
+);
+
+-- The migrations before this one were applied in order to reach it
+INSERT INTO schema_migrations (version) VALUES
+  ('0002'), ('0003'), ('0004'), ('0005'), ('0006'), ('0007');
+
+INSERT INTO schema_migrations (version) VALUES ('0008');
@@ -0,0 +1,36 @@
+package database_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+)
+
+func TestLatestMigration(t *testing.T) {
+	files := database.Migrations()
+	require.NotEmpty(t, files)
+
+	assert.Equal(t, "0008", database.MigrationVersion("migrations/0008_schema_migrations.sql"))
+	assert.Equal(t, database.MigrationVersion(files[len(files)-1]), database.LatestMigration())
+}
+
+// Migrations from 0008 on record their version, which the readiness check
+// looks for
+func TestMigrations_RecordTheirVersion(t *testing.T) {
+	for _, file := range database.Migrations() {
+		version := database.MigrationVersion(file)
+		if version < "0008" {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), fmt.Sprintf("INSERT INTO schema_migrations (version) VALUES ('%s');", version),
+			"migration %s does not record its version", file)
+	}
+}
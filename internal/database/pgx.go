@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+)
+
+// PgxAdapter runs the statements of the repositories on a pgx pool. It
+// behaves like SqlDBAdapter, a row that isn't found fails with sql.ErrNoRows.
+type PgxAdapter struct {
+	Pool *pgxpool.Pool
+	// QueryTimeout bounds every statement, zero leaves them to the deadline
+	// of their context
+	QueryTimeout time.Duration
+}
+
+// PgxPoolConfig returns the configuration of a pgx pool of the database at
+// dsn, tuned by cfg. With a statement cache every connection prepares a
+// statement on its first run and reuses it afterwards.
+func PgxPoolConfig(cfg config.DatabaseConfig, dsn string) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing database configuration: %w", err)
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(cfg.MaxOpenConns)
+	}
+	poolCfg.MinConns = int32(min(cfg.MinConns, int(poolCfg.MaxConns)))
+	if cfg.ConnMaxLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.ConnMaxLifetime
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.ConnMaxIdleTime
+	}
+
+	if cfg.StatementCacheCapacity > 0 {
+		poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+		poolCfg.ConnConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+	} else {
+		poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeDescribeExec
+	}
+
+	return poolCfg, nil
+}
+
+func (db *PgxAdapter) QueryRow(query string, args ...interface{}) Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+func (db *PgxAdapter) Query(query string, args ...interface{}) (Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+func (db *PgxAdapter) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// pgxExecutor runs statements on the pool or in a transaction
+type pgxExecutor interface {
+	QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// executor returns the transaction of ctx, or the pool outside of one
+func (db *PgxAdapter) executor(ctx context.Context) pgxExecutor {
+	if tx, ok := ctx.Value(constants.TxKey).(pgx.Tx); ok {
+		return tx
+	}
+	return db.Pool
+}
+
+func (db *PgxAdapter) QueryRowContext(ctx context.Context, query string, args ...interface{}) Row {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	return &pgxRow{Row: db.executor(ctx).QueryRow(ctx, query, args...), cancel: cancel}
+}
+
+func (db *PgxAdapter) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	result, err := db.executor(ctx).Query(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &pgxRows{Rows: result, cancel: cancel}, nil
+}
+
+func (db *PgxAdapter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tag, err := db.executor(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgxResult(tag), nil
+}
+
+func (db *PgxAdapter) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(constants.TxKey).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Roll back even when ctx is cancelled, or the connection is closed
+	defer tx.Rollback(context.WithoutCancel(ctx)) //nolint:errcheck
+
+	if err := fn(context.WithValue(ctx, constants.TxKey, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (db *PgxAdapter) PingContext(ctx context.Context) error {
+	return db.Pool.Ping(ctx)
+}
+
+// pgxRow releases the timeout of its statement once scanned
+type pgxRow struct {
+	pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *pgxRow) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	err := r.Row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+// pgxRows releases the timeout of its statement once closed
+type pgxRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *pgxRows) Close() error {
+	defer r.cancel()
+	r.Rows.Close()
+	return nil
+}
+
+// pgxResult reports the rows affected by a statement
+type pgxResult pgconn.CommandTag
+
+func (r pgxResult) LastInsertId() (int64, error) {
+	return 0, errors.New("LastInsertId is not supported by Postgres, use RETURNING")
+}
+
+func (r pgxResult) RowsAffected() (int64, error) {
+	return pgconn.CommandTag(r).RowsAffected(), nil
+}
@@ -0,0 +1,46 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+)
+
+func TestPgxPoolConfig(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Username:               "gogo",
+		Password:               "secret",
+		Host:                   "db",
+		Port:                   5432,
+		Database:               "gogomanager",
+		SslMode:                "disable",
+		MaxOpenConns:           20,
+		MinConns:               2,
+		ConnMaxLifetime:        time.Hour,
+		ConnMaxIdleTime:        5 * time.Minute,
+		StatementCacheCapacity: 128,
+	}
+
+	poolCfg, err := database.PgxPoolConfig(cfg, cfg.DSN())
+	require.NoError(t, err)
+	assert.Equal(t, "db", poolCfg.ConnConfig.Host)
+	assert.Equal(t, "gogo", poolCfg.ConnConfig.User)
+	assert.Equal(t, int32(20), poolCfg.MaxConns)
+	assert.Equal(t, int32(2), poolCfg.MinConns)
+	assert.Equal(t, time.Hour, poolCfg.MaxConnLifetime)
+	assert.Equal(t, 5*time.Minute, poolCfg.MaxConnIdleTime)
+	assert.Equal(t, pgx.QueryExecModeCacheStatement, poolCfg.ConnConfig.DefaultQueryExecMode)
+	assert.Equal(t, 128, poolCfg.ConnConfig.StatementCacheCapacity)
+
+	// Without a cache every run describes its statement
+	cfg.StatementCacheCapacity = 0
+	poolCfg, err = database.PgxPoolConfig(cfg, cfg.DSN())
+	require.NoError(t, err)
+	assert.Equal(t, pgx.QueryExecModeDescribeExec, poolCfg.ConnConfig.DefaultQueryExecMode)
+}
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+)
+
+// ReplicaDB is a database that can be pinged, like SqlDBAdapter and
+// PgxAdapter
+type ReplicaDB interface {
+	DB
+	PingContext(ctx context.Context) error
+}
+
+// ReadOnly marks the statements run with ctx as reads that may run on a
+// read replica, see Replicated
+func ReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, constants.ReadOnlyKey, true)
+}
+
+// ReplicaOptions configures how Replicated routes the statements
+type ReplicaOptions struct {
+	// StickyFor is how long the reads of a session stay on the primary
+	// after it wrote
+	StickyFor time.Duration
+	// Session returns the session a statement runs for, like the manager
+	// of the request. Reads without a session are never pinned.
+	Session func(ctx context.Context) string
+}
+
+// Replicated runs the statements marked ReadOnly on its replicas, round
+// robin, and every other statement on the primary. Transactions always run
+// on the primary and, so sessions read their writes back, so do the reads of
+// a session for StickyFor after it wrote. Sessions are tracked in process.
+// Replicas are only used once Check found them healthy, while none is
+// the reads fall back to the primary.
+type Replicated struct {
+	primary  DB
+	replicas []*replica
+	options  ReplicaOptions
+	next     atomic.Uint64
+
+	mu     sync.Mutex
+	writes map[string]time.Time
+}
+
+type replica struct {
+	db      ReplicaDB
+	healthy atomic.Bool
+}
+
+func NewReplicated(primary DB, replicas []ReplicaDB, options ReplicaOptions) *Replicated {
+	r := &Replicated{primary: primary, options: options, writes: make(map[string]time.Time)}
+	for _, db := range replicas {
+		r.replicas = append(r.replicas, &replica{db: db})
+	}
+	return r
+}
+
+// reader returns the database a read runs on
+func (r *Replicated) reader(ctx context.Context) DB {
+	if readOnly, _ := ctx.Value(constants.ReadOnlyKey).(bool); !readOnly {
+		return r.primary
+	}
+	if ctx.Value(constants.TxKey) != nil || r.sticky(ctx) {
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		replica := r.replicas[(int(start)+i)%len(r.replicas)]
+		if replica.healthy.Load() {
+			return replica.db
+		}
+	}
+	return r.primary
+}
+
+// sticky reports whether the session of ctx wrote within StickyFor
+func (r *Replicated) sticky(ctx context.Context) bool {
+	session := r.session(ctx)
+	if session == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wrote, ok := r.writes[session]
+	return ok && time.Since(wrote) < r.options.StickyFor
+}
+
+// wrote pins the reads of the session of ctx to the primary
+func (r *Replicated) wrote(ctx context.Context) {
+	session := r.session(ctx)
+	if session == "" || r.options.StickyFor <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writes[session] = time.Now()
+}
+
+func (r *Replicated) session(ctx context.Context) string {
+	if r.options.Session == nil {
+		return ""
+	}
+	return r.options.Session(ctx)
+}
+
+// isWrite reports whether a statement modifies data, like an INSERT with a
+// RETURNING clause run with QueryRowContext
+func isWrite(query string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	switch strings.ToUpper(strings.TrimSpace(keyword)) {
+	case "INSERT", "UPDATE", "DELETE", "MERGE":
+		return true
+	}
+	return false
+}
+
+func (r *Replicated) QueryRow(query string, args ...interface{}) Row {
+	return r.primary.QueryRow(query, args...)
+}
+
+func (r *Replicated) Query(query string, args ...interface{}) (Rows, error) {
+	return r.primary.Query(query, args...)
+}
+
+func (r *Replicated) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.Exec(query, args...)
+}
+
+func (r *Replicated) QueryRowContext(ctx context.Context, query string, args ...interface{}) Row {
+	if isWrite(query) {
+		r.wrote(ctx)
+	}
+	return r.reader(ctx).QueryRowContext(ctx, query, args...)
+}
+
+func (r *Replicated) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	if isWrite(query) {
+		r.wrote(ctx)
+	}
+	return r.reader(ctx).QueryContext(ctx, query, args...)
+}
+
+func (r *Replicated) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	r.wrote(ctx)
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+func (r *Replicated) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.wrote(ctx)
+	return r.primary.InTx(ctx, fn)
+}
+
+// Check pings every replica, the ones that don't answer are skipped until
+// they answer again
+func (r *Replicated) Check(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	for i, replica := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		err := replica.db.PingContext(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if replica.healthy.Swap(healthy) != healthy {
+			if healthy {
+				logger.Info("Read replica is healthy", "replica", i)
+			} else {
+				logger.Warn("Read replica is unhealthy, reading from the primary", "replica", i, "error", err)
+			}
+		}
+	}
+
+	// Forget the sessions that are no longer pinned
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for session, wrote := range r.writes {
+		if time.Since(wrote) >= r.options.StickyFor {
+			delete(r.writes, session)
+		}
+	}
+}
+
+// Monitor checks the replicas every interval until ctx is done, meant to
+// run as a background worker
+func (r *Replicated) Monitor(interval time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			r.Check(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+)
+
+// namedDB records the statements run on it under its name
+type namedDB struct {
+	name    string
+	calls   *[]string
+	pingErr error
+}
+
+func (db *namedDB) record(query string) { *db.calls = append(*db.calls, db.name+": "+query) }
+
+func (db *namedDB) QueryRow(query string, _ ...interface{}) database.Row {
+	return db.QueryRowContext(context.Background(), query)
+}
+
+func (db *namedDB) Query(query string, _ ...interface{}) (database.Rows, error) {
+	return db.QueryContext(context.Background(), query)
+}
+
+func (db *namedDB) Exec(query string, _ ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query)
+}
+
+func (db *namedDB) QueryRowContext(_ context.Context, query string, _ ...interface{}) database.Row {
+	db.record(query)
+	return nil
+}
+
+func (db *namedDB) QueryContext(_ context.Context, query string, _ ...interface{}) (database.Rows, error) {
+	db.record(query)
+	return nil, nil
+}
+
+func (db *namedDB) ExecContext(_ context.Context, query string, _ ...interface{}) (sql.Result, error) {
+	db.record(query)
+	return nil, nil
+}
+
+func (db *namedDB) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	db.record("begin")
+	return fn(context.WithValue(ctx, constants.TxKey, db))
+}
+
+func (db *namedDB) PingContext(context.Context) error { return db.pingErr }
+
+type sessionKey struct{}
+
+func newReplicated(replicas int) (*database.Replicated, []*namedDB, *[]string) {
+	calls := &[]string{}
+	primary := &namedDB{name: "primary", calls: calls}
+	var dbs []*namedDB
+	var replicaDBs []database.ReplicaDB
+	for i := range replicas {
+		db := &namedDB{name: "replica" + string(rune('1'+i)), calls: calls}
+		dbs = append(dbs, db)
+		replicaDBs = append(replicaDBs, db)
+	}
+	replicated := database.NewReplicated(primary, replicaDBs, database.ReplicaOptions{
+		StickyFor: time.Minute,
+		Session: func(ctx context.Context) string {
+			session, _ := ctx.Value(sessionKey{}).(string)
+			return session
+		},
+	})
+	return replicated, dbs, calls
+}
+
+func TestReplicated_ReadsFromHealthyReplicas(t *testing.T) {
+	db, _, calls := newReplicated(2)
+	ctx := context.Background()
+
+	// Replicas aren't used before they are checked
+	db.QueryRowContext(database.ReadOnly(ctx), "SELECT 1")
+	db.Check(ctx)
+	db.QueryRowContext(database.ReadOnly(ctx), "SELECT 2")
+	_, err := db.QueryContext(database.ReadOnly(ctx), "SELECT 3")
+	require.NoError(t, err)
+	// Reads that aren't marked read only stay on the primary
+	db.QueryRowContext(ctx, "SELECT 4")
+
+	assert.Equal(t, []string{"primary: SELECT 1", "replica1: SELECT 2", "replica2: SELECT 3", "primary: SELECT 4"}, *calls)
+}
+
+func TestReplicated_FallsBackToThePrimary(t *testing.T) {
+	db, replicas, calls := newReplicated(2)
+	ctx := database.ReadOnly(context.Background())
+
+	replicas[0].pingErr = errors.New("connection refused")
+	db.Check(ctx)
+	db.QueryRowContext(ctx, "SELECT 1")
+	db.QueryRowContext(ctx, "SELECT 2")
+
+	replicas[1].pingErr = errors.New("connection refused")
+	db.Check(ctx)
+	db.QueryRowContext(ctx, "SELECT 3")
+
+	assert.Equal(t, []string{"replica2: SELECT 1", "replica2: SELECT 2", "primary: SELECT 3"}, *calls)
+}
+
+func TestReplicated_SessionsReadTheirWrites(t *testing.T) {
+	db, _, calls := newReplicated(1)
+	db.Check(context.Background())
+	manager := context.WithValue(context.Background(), sessionKey{}, "1")
+	other := context.WithValue(context.Background(), sessionKey{}, "2")
+
+	db.QueryRowContext(manager, "INSERT INTO employees")
+	db.QueryRowContext(database.ReadOnly(manager), "SELECT 1")
+	db.QueryRowContext(database.ReadOnly(other), "SELECT 2")
+
+	err := db.InTx(other, func(ctx context.Context) error {
+		// Reads in a transaction stay on the primary
+		db.QueryRowContext(database.ReadOnly(ctx), "SELECT 3")
+		_, err := db.ExecContext(ctx, "UPDATE employees")
+		return err
+	})
+	require.NoError(t, err)
+	db.QueryRowContext(database.ReadOnly(other), "SELECT 4")
+
+	assert.Equal(t, []string{
+		"primary: INSERT INTO employees",
+		"primary: SELECT 1",
+		"replica1: SELECT 2",
+		"primary: begin",
+		"primary: SELECT 3",
+		"primary: UPDATE employees",
+		"primary: SELECT 4",
+	}, *calls)
+}
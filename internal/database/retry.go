@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+)
+
+// Backoff spaces the attempts of Retry, doubling the wait from Initial up to
+// Max until Timeout has passed
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Timeout time.Duration
+}
+
+// NewBackoff returns the backoff of the startup connection, retrying for
+// timeout
+func NewBackoff(timeout time.Duration) Backoff {
+	return Backoff{Initial: 500 * time.Millisecond, Max: 10 * time.Second, Timeout: timeout}
+}
+
+// Retry calls connect until it succeeds, like when Postgres is still
+// starting, logging every failed attempt. It returns the last error once
+// the timeout of backoff has passed or ctx is done.
+func Retry(ctx context.Context, backoff Backoff, connect func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, backoff.Timeout)
+	defer cancel()
+
+	wait := backoff.Initial
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			return nil
+		}
+
+		logging.FromContext(ctx).Warn("Failed to connect to the database, retrying",
+			"attempt", attempt, "retry_in", wait.String(), "error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		case <-timer.C:
+		}
+		wait = min(2*wait, backoff.Max)
+	}
+}
@@ -0,0 +1,37 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+)
+
+func TestRetry_UntilConnected(t *testing.T) {
+	backoff := database.Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Timeout: time.Second}
+
+	attempts := 0
+	err := database.Retry(context.Background(), backoff, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	backoff := database.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Timeout: 20 * time.Millisecond}
+	errRefused := errors.New("connection refused")
+
+	err := database.Retry(context.Background(), backoff, func(ctx context.Context) error {
+		return errRefused
+	})
+	assert.ErrorIs(t, err, errRefused)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+type AttendanceHandler struct {
+	service services.AttendanceService
+}
+
+func NewAttendanceHandler(service services.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{
+		service: service,
+	}
+}
+
+func (h *AttendanceHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	h.record(w, r, h.service.CheckIn, "checked in")
+}
+
+func (h *AttendanceHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
+	h.record(w, r, h.service.CheckOut, "checked out")
+}
+
+type attendanceAction func(ctx context.Context, managerID int, req models.AttendanceRequest) (*models.AttendanceRecord, error)
+
+func (h *AttendanceHandler) record(w http.ResponseWriter, r *http.Request, action attendanceAction, verb string) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.AttendanceRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, req) {
+		return
+	}
+
+	record, err := action(r.Context(), claims.ID, req)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    record,
+		Message: fmt.Sprintf("Employee %s %s", record.IdentityNumber, verb),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Daily returns the attendance of the manager's employees on one day,
+// today by default.
+func (h *AttendanceHandler) Daily(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	day := models.Today()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := models.ParseDate(dateStr)
+		if err != nil {
+			utils.BadRequest(w, err.Error())
+			return
+		}
+		day = parsed
+	}
+
+	departmentID, ok := parseDepartmentID(w, r)
+	if !ok {
+		return
+	}
+
+	attendance, err := h.service.Daily(r.Context(), claims.ID, day, departmentID)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    attendance,
+		Message: fmt.Sprintf("Attendance of %d employees on %s", len(attendance), day),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AttendanceHandler) Report(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	departmentID, ok := parseDepartmentID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.Report(r.Context(), claims.ID, r.URL.Query().Get("month"), departmentID)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    report,
+		Message: fmt.Sprintf("Attendance report for %s", report.Month),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AttendanceHandler) GetSchedule(w http.ResponseWriter, r *http.Request, departmentID int) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	schedule, err := h.service.Schedule(r.Context(), claims.ID, departmentID)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    schedule,
+		Message: fmt.Sprintf("Schedule of department %d", departmentID),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AttendanceHandler) SaveSchedule(w http.ResponseWriter, r *http.Request, departmentID int) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var schedule models.WorkSchedule
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&schedule); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, schedule) {
+		return
+	}
+
+	schedule.DepartmentID = departmentID
+	saved, err := h.service.SaveSchedule(r.Context(), claims.ID, schedule)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    saved,
+		Message: fmt.Sprintf("Schedule of department %d updated successfully", departmentID),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseDepartmentID reads the optional departmentId query parameter, writing
+// a 400 response and returning false when it isn't a number.
+func parseDepartmentID(w http.ResponseWriter, r *http.Request) (*int, bool) {
+	deptID := r.URL.Query().Get("departmentId")
+	if deptID == "" {
+		return nil, true
+	}
+
+	id, err := strconv.Atoi(deptID)
+	if err != nil {
+		utils.BadRequest(w, "departmentId must be a number")
+		return nil, false
+	}
+
+	return &id, true
+}
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/metrics"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
@@ -41,40 +45,37 @@ func (h *AuthHandler) Auth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !validateRequest(w, credential) {
+		return
+	}
+
+	// Count the attempt once its action is known, it succeeds when a token is issued
+	authenticated := false
+	defer func() {
+		metrics.RecordAuth(string(credential.Action), authenticated)
+	}()
+
 	cfg, ok := r.Context().Value(constants.ConfigKey).(*config.Config)
 	if !ok {
-		log.Println("Configuration not found")
+		logging.FromContext(r.Context()).Error("Configuration not found")
 		utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
 	switch credential.Action {
 	case utils.Register:
-		manager_id, sqlErr := h.managerService.Create(credential.Email, credential.Password)
+		manager_id, sqlErr := h.managerService.Create(r.Context(), credential.Email, credential.Password)
 		if sqlErr != nil {
-			switch sqlErr.Type {
-			case utils.SQLUniqueViolated:
-				utils.SendErrorResponse(w, "Email already registered", http.StatusConflict)
-				return
-			case utils.InvalidEmailFormat:
-				utils.SendErrorResponse(w, "Invalid email format", http.StatusBadRequest)
-				return
-			case utils.InvalidPasswordLength:
-				utils.SendErrorResponse(w, "Invalid password length (min length: 8, max length: 32)", http.StatusBadRequest)
-				return
-			default:
-				log.Println("Failed to create manager:", err)
-				utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+			utils.WriteError(w, sqlErr)
+			return
 		}
 
 		token, err := h.getJWT(cfg.JWT.Secret, manager_id, credential.Email)
 		if err != nil {
-			log.Println("Failed to generate JWT:", err)
-			utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
+			utils.WriteError(w, fmt.Errorf("generate JWT: %w", err))
 			return
 		}
+		authenticated = true
 
 		response := utils.AuthResponse{
 			Email: credential.Email,
@@ -85,30 +86,35 @@ func (h *AuthHandler) Auth(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusCreated)
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
-			log.Printf("Failed to send response to %s: %v", credential.Email, err)
+			logging.FromContext(r.Context()).Error("Failed to send response", "email", credential.Email, "error", err)
 			utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
 
 	case utils.Login:
-		manager, sqlErr := h.managerService.GetByEmail(credential.Email)
+		manager, sqlErr := h.managerService.GetByEmail(r.Context(), credential.Email)
 		if sqlErr != nil {
-			utils.SendErrorResponse(w, "User not found", http.StatusNotFound)
+			// An unknown email is answered like a wrong password, so the
+			// registered emails can't be told apart
+			if errors.Is(sqlErr, models.ErrManagerNotFound) {
+				sqlErr = services.ErrInvalidCredentials
+			}
+			utils.WriteError(w, sqlErr)
 			return
 		}
 
 		error := h.pwdComparator([]byte(manager.Password), []byte(credential.Password))
 		if error != nil {
-			utils.SendErrorResponse(w, "Invalid credential", http.StatusUnauthorized)
+			utils.WriteError(w, services.ErrInvalidCredentials)
 			return
 		}
 
 		token, err := h.getJWT(cfg.JWT.Secret, manager.ID, manager.Email)
 		if err != nil {
-			log.Printf("Failed to generate JWT for user %d: %v", manager.ID, err)
-			utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
+			utils.WriteError(w, fmt.Errorf("generate JWT for manager %d: %w", manager.ID, err))
 			return
 		}
+		authenticated = true
 
 		response := utils.AuthResponse{
 			Email: manager.Email,
@@ -119,7 +125,7 @@ func (h *AuthHandler) Auth(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
-			log.Printf("Failed to send response to %d: %v", manager.ID, err)
+			logging.FromContext(r.Context()).Error("Failed to send response", "manager_id", manager.ID, "error", err)
 			utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
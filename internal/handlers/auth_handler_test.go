@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"database/sql"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/handlers"
@@ -58,7 +60,7 @@ func TestAuthHandler_RegisterManager_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("Create", email, password).Return(manager_id, nil)
+	mockService.On("Create", mock.Anything, email, password).Return(manager_id, nil)
 	mockJWTGen.On("GenerateJWT", cfg.JWT.Secret, manager_id, email).Return(token, nil)
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
@@ -184,7 +186,7 @@ func TestAuthHandler_RegisterManager_EmailAlreadyRegistered(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("Create", email, password).Return(0, error)
+	mockService.On("Create", mock.Anything, email, password).Return(0, error)
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
 
@@ -226,7 +228,7 @@ func TestAuthHandler_RegisterManager_InvalidEmailFormat(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("Create", email, password).Return(0, error)
+	mockService.On("Create", mock.Anything, email, password).Return(0, error)
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
 
@@ -268,7 +270,7 @@ func TestAuthHandler_RegisterManager_InvalidPasswordLeght(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("Create", email, password).Return(0, error)
+	mockService.On("Create", mock.Anything, email, password).Return(0, error)
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
 
@@ -310,7 +312,7 @@ func TestAuthHandler_RegisterManager_DatabaseError(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("Create", email, password).Return(0, error)
+	mockService.On("Create", mock.Anything, email, password).Return(0, error)
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
 
@@ -346,7 +348,7 @@ func TestAuthHandler_RegisterManager_JWTGenerateError(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("Create", email, password).Return(manager_id, nil)
+	mockService.On("Create", mock.Anything, email, password).Return(manager_id, nil)
 	mockJWTGen.On("GenerateJWT", cfg.JWT.Secret, manager_id, email).Return("", errors.New("Failed to generate JWT"))
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
@@ -397,7 +399,7 @@ func TestAuthHandler_LoginManager_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("GetByEmail", email).Return(manager, nil)
+	mockService.On("GetByEmail", mock.Anything, email).Return(manager, nil)
 	mockBCrypt.On("CompareHashAndPassword", []byte(manager.Password), []byte(password)).Return(nil)
 	mockJWTGen.On("GenerateJWT", cfg.JWT.Secret, manager_id, email).Return(token, nil)
 
@@ -513,21 +515,57 @@ func TestAuthHandler_LoginManager_UserNotFound(t *testing.T) {
 		},
 	}
 
-	e := errors.New("User not found")
+	error := models.ErrManagerNotFound.Wrap(sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
+	res := httptest.NewRecorder()
+
+	mockService.On("GetByEmail", mock.Anything, email).Return(nil, error)
+
+	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	mockService.AssertExpectations(t)
+	mockJWTGen.AssertExpectations(t)
+	mockBCrypt.AssertExpectations(t)
+}
+
+func TestAuthHandler_LoginManager_QueryFailed(t *testing.T) {
+	mockService := new(mocksServices.ManagerService)
+	mockJWTGen := &mocksUtils.JWTHandler{}
+	mockBCrypt := &mocksUtils.Encryption{}
+	handler := handlers.NewAuthHandler(mockService, mockJWTGen.GenerateJWT, mockBCrypt.CompareHashAndPassword)
+
+	email := "random@name.com"
+
+	mockBody := &MockRequestBody{
+		Data:  `{"email": "random@name.com", "password": "cobalagi", "action": "login"}`,
+		Error: nil,
+	}
+
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{},
+		JWT: config.JWTConfig{
+			Secret: "i-am-amazing-huntsman",
+		},
+	}
+
+	e := errors.New("connection refused")
 	error := &utils.GoGoError{
 		Type:    utils.SQLError,
-		Message: "Email not found",
+		Message: "Error querying manager by email",
 		Err:     e,
 	}
 
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("GetByEmail", email).Return(nil, error)
+	mockService.On("GetByEmail", mock.Anything, email).Return(nil, error)
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
 
-	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
 
 	mockService.AssertExpectations(t)
 	mockJWTGen.AssertExpectations(t)
@@ -573,7 +611,7 @@ func TestAuthHandler_LoginManager_Unauthorized(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("GetByEmail", email).Return(manager, nil)
+	mockService.On("GetByEmail", mock.Anything, email).Return(manager, nil)
 	mockBCrypt.On("CompareHashAndPassword", []byte(manager.Password), []byte(password)).Return(e)
 
 	middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)).ServeHTTP(res, req)
@@ -625,7 +663,7 @@ func TestAuthHandler_LoginManager_FailedGenerateJWT(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/auth", mockBody)
 	res := httptest.NewRecorder()
 
-	mockService.On("GetByEmail", email).Return(manager, nil)
+	mockService.On("GetByEmail", mock.Anything, email).Return(manager, nil)
 	mockBCrypt.On("CompareHashAndPassword", []byte(manager.Password), []byte(password)).Return(nil)
 	mockJWTGen.On("GenerateJWT", cfg.JWT.Secret, manager_id, email).Return("", e)
 
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// resourceETag returns the strong ETag of a resource last updated at
+// updatedAt. It holds updatedAt to the microsecond Postgres keeps, so the
+// If-Match of an update decodes back to the version it expects, see ifMatch.
+func resourceETag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+// version identifies a resource of a list as it was last updated
+type version struct {
+	id        interface{}
+	updatedAt time.Time
+}
+
+// collectionValidators returns the weak ETag of a list, changing whenever
+// one of its resources is added, removed or updated, and when the most
+// recent one was updated, zero for an empty list
+func collectionValidators(versions []version) (etag string, lastModified time.Time) {
+	hash := sha256.New()
+	for _, v := range versions {
+		fmt.Fprintf(hash, "%v:%d;", v.id, v.updatedAt.UnixMicro())
+		if v.updatedAt.After(lastModified) {
+			lastModified = v.updatedAt
+		}
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, lastModified
+}
+
+// writeValidators sets the ETag and, when known, the Last-Modified headers
+// of a response
+func writeValidators(w http.ResponseWriter, etag string, lastModified time.Time) {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified sets the validators of a GET response and answers 304 Not
+// Modified when the If-None-Match of the request holds etag already.
+// Responses depend on the manager, so only their client keeps them and it
+// revalidates them every time.
+func notModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	writeValidators(w, etag, lastModified)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	for _, header := range r.Header.Values("If-None-Match") {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			// If-None-Match compares weakly, ignoring the W/ prefix
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				w.WriteHeader(http.StatusNotModified)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ifMatch returns the version an update expects from its If-Match header,
+// nil without one or for *. Only a single strong ETag of ours can match,
+// anything else answers 412 Precondition Failed and returns false.
+func ifMatch(w http.ResponseWriter, r *http.Request) (*time.Time, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		tag, ok = strings.CutSuffix(tag, `"`)
+	}
+	micros, err := strconv.ParseInt(tag, 36, 64)
+	if !ok || err != nil {
+		utils.WriteError(w, utils.ErrPreconditionFailed)
+		return nil, false
+	}
+
+	updatedAt := time.UnixMicro(micros)
+	return &updatedAt, true
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+type CustomFieldHandler struct {
+	service services.CustomFieldService
+}
+
+func NewCustomFieldHandler(service services.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		service: service,
+	}
+}
+
+func (h *CustomFieldHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	fields, err := h.service.List(r.Context(), claims.ID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error listing custom fields", "error", err)
+		utils.SendErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if fields == nil {
+		fields = []models.CustomField{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    fields,
+		Message: fmt.Sprintf("Successfully retrieved %d custom fields", len(fields)),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CustomFieldHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateCustomFieldRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, req) {
+		return
+	}
+
+	field, err := h.service.Create(r.Context(), claims.ID, req)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    field,
+		Message: fmt.Sprintf("Custom field %s created successfully", field.Key),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *CustomFieldHandler) Delete(w http.ResponseWriter, r *http.Request, key string) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.Delete(r.Context(), claims.ID, key)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Message: fmt.Sprintf("Custom field %s deleted successfully", key),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -4,17 +4,13 @@ import (
     "encoding/json"
     "net/http"
     "strconv"
-    "log"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+    "github.com/ngikut-project-sprint/GoGoManager/internal/models"
     "github.com/ngikut-project-sprint/GoGoManager/internal/services"
     "github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
-var req struct {
-    Name string `json:"name"`
-}
-
 type DepartmentHandler struct {
     service services.DepartmentService
 }
@@ -23,41 +19,6 @@ func NewDepartmentHandler(service services.DepartmentService) *DepartmentHandler
     return &DepartmentHandler{service: service}
 }
 
-func (h *DepartmentHandler) HandleDepartment(w http.ResponseWriter, r *http.Request) {
-    log.Printf("HandleDepartment called with method: %s and path: %s\n", r.Method, r.URL.Path)
-
-    switch r.Method {
-    case http.MethodGet:
-        h.ListDepartments(w, r)
-    case http.MethodPost:
-        h.CreateDepartment(w, r)
-    default:
-        utils.MethodNotAllowed(w, r.Method)
-    }
-}
-
-func (h *DepartmentHandler) HandleDepartmentWithID(w http.ResponseWriter, r *http.Request) {
-    idStr := r.URL.Query().Get("id")
-    if idStr == "" {
-        utils.BadRequest(w, "Department ID is required")
-        return    }
-    
-    departmentID, err := strconv.Atoi(idStr)
-    if err != nil {
-        utils.BadRequest(w, "Department ID is required")
-        return   
-     }
-
-    switch r.Method {
-    case http.MethodPatch:
-        h.UpdateDepartment(w, r, departmentID)  // Pass departmentID here
-    case http.MethodDelete:
-        h.DeleteDepartment(w, r, departmentID)
-    default:
-        utils.MethodNotAllowed(w, r.Method)
-    }
-}
-
 func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Request) {
 
     w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -65,7 +26,7 @@ func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Requ
     // Get manager ID from context
     claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
 	if !ok {
-		http.Error(w, "User not aunthenticated", http.StatusUnauthorized)
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
 		return
 	} 
     if !ok {
@@ -75,6 +36,7 @@ func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Requ
         return
     }
 
+    var req models.CreateDepartmentRequest
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         utils.SendErrorResponse(w, 
             "Invalid request body",
@@ -82,23 +44,18 @@ func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Requ
         return
     }
 
-    // Validate name
-    if len(req.Name) < 4 || len(req.Name) > 33 {
-        utils.SendErrorResponse(w, 
-            "Name must be between 4 and 33 characters",
-            http.StatusBadRequest)
+    if !validateRequest(w, req) {
         return
     }
 
-    dept, err := h.service.CreateDepartment(req.Name, claims.ID)
+    dept, err := h.service.CreateDepartment(r.Context(), req.Name, claims.ID)
     if err != nil {
-        utils.SendErrorResponse(w, 
-            "Failed to create department",
-            http.StatusInternalServerError)
+        utils.WriteError(w, err)
         return
     }
 
     // Success response
+    writeValidators(w, resourceETag(dept.UpdatedAt), dept.UpdatedAt)
     w.WriteHeader(http.StatusCreated)
 
     if err := json.NewEncoder(w).Encode(dept); err != nil {
@@ -109,6 +66,12 @@ func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Requ
 
 
 func (h *DepartmentHandler) ListDepartments(w http.ResponseWriter, r *http.Request) {
+    claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+    if !ok {
+        utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+        return
+    }
+
     // Parse query parameters
     query := r.URL.Query()
     limit, _ := strconv.Atoi(query.Get("limit"))
@@ -123,9 +86,18 @@ func (h *DepartmentHandler) ListDepartments(w http.ResponseWriter, r *http.Reque
         offset = 0
     }
 
-    departments, err := h.service.GetDepartments(limit, offset, name)
+    departments, err := h.service.GetDepartments(r.Context(), limit, offset, name, claims.ID)
     if err != nil {
-        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        utils.WriteError(w, err)
+        return
+    }
+
+    versions := make([]version, len(departments))
+    for i, dept := range departments {
+        versions[i] = version{id: dept.DepartmentId, updatedAt: dept.UpdatedAt}
+    }
+    etag, lastModified := collectionValidators(versions)
+    if notModified(w, r, etag, lastModified) {
         return
     }
 
@@ -135,34 +107,39 @@ func (h *DepartmentHandler) ListDepartments(w http.ResponseWriter, r *http.Reque
     }
 }
 
-func (h *DepartmentHandler) UpdateDepartment(w http.ResponseWriter, r *http.Request, departmentID int) { // Add departmentID parameter
+func (h *DepartmentHandler) UpdateDepartment(w http.ResponseWriter, r *http.Request, departmentID int) {
     w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
     // Get manager ID from token
-    userID, ok := r.Context().Value("user_id").(int)
+    claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
     if !ok {
-        utils.SendErrorResponse(w, "User ID not found in token", http.StatusUnauthorized)
+        utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
         return
     }
 
+    var req models.UpdateDepartmentRequest
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
         return
     }
 
-    // Validate name
-    if len(req.Name) < 4 || len(req.Name) > 33 {
-        utils.SendErrorResponse(w, "Name must be between 4 and 33 characters", http.StatusBadRequest)
+    if !validateRequest(w, req) {
+        return
+    }
+
+    // Update only the version the client read, when it sent one
+    if req.IfUpdatedAt, ok = ifMatch(w, r); !ok {
         return
     }
 
     // Update department
-    dept, err := h.service.UpdateDepartment(departmentID, req.Name, userID)
+    dept, err := h.service.UpdateDepartment(r.Context(), departmentID, req, claims.ID)
     if err != nil {
-        utils.SendErrorResponse(w, "Failed to update department", http.StatusInternalServerError)
+        utils.WriteError(w, err)
         return
     }
 
+    writeValidators(w, resourceETag(dept.UpdatedAt), dept.UpdatedAt)
     utils.WriteJSON(w, http.StatusOK, dept)
 }
 
@@ -170,16 +147,16 @@ func (h *DepartmentHandler) DeleteDepartment(w http.ResponseWriter, r *http.Requ
     w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
     // Get manager ID from token
-    userID, ok := r.Context().Value("user_id").(int)
+    claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
     if !ok {
-        utils.SendErrorResponse(w, "User ID not found in token", http.StatusUnauthorized)
+        utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
         return
     }
 
     // Delete department
-    err := h.service.DeleteDepartment(departmentID, userID)
-    if err != nil {       
-        utils.SendErrorResponse(w, "Failed to delete department", http.StatusUnauthorized)
+    err := h.service.DeleteDepartment(r.Context(), departmentID, claims.ID)
+    if err != nil {
+        utils.WriteError(w, err)
         return
     }
 
@@ -188,3 +165,31 @@ func (h *DepartmentHandler) DeleteDepartment(w http.ResponseWriter, r *http.Requ
         "message": "Department deleted successfully",
     })
 }
+
+// MergeDepartments moves every employee of the source departments into the
+// target department and deletes the sources.
+func (h *DepartmentHandler) MergeDepartments(w http.ResponseWriter, r *http.Request) {
+    claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+    if !ok {
+        utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+        return
+    }
+
+    var mergeReq models.MergeDepartmentsRequest
+    if err := json.NewDecoder(r.Body).Decode(&mergeReq); err != nil {
+        utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    if !validateRequest(w, mergeReq) {
+        return
+    }
+
+    result, err := h.service.MergeDepartments(r.Context(), mergeReq.SourceDepartmentIds, mergeReq.TargetDepartmentId, claims.ID)
+    if err != nil {
+        utils.WriteError(w, err)
+        return
+    }
+
+    utils.WriteJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+type DepartmentTransferHandler struct {
+	service services.DepartmentTransferService
+}
+
+func NewDepartmentTransferHandler(service services.DepartmentTransferService) *DepartmentTransferHandler {
+	return &DepartmentTransferHandler{
+		service: service,
+	}
+}
+
+func (h *DepartmentTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateDepartmentTransferRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, req) {
+		return
+	}
+
+	transfer, err := h.service.Create(r.Context(), claims.ID, req)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, transfer)
+}
+
+// List returns the transfers the manager proposed or received
+func (h *DepartmentTransferHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	transfers, err := h.service.List(r.Context(), claims.ID)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	if transfers == nil {
+		transfers = []models.DepartmentTransfer{}
+	}
+
+	utils.WriteJSON(w, http.StatusOK, transfers)
+}
+
+// Decide accepts, rejects or cancels a pending transfer
+func (h *DepartmentTransferHandler) Decide(w http.ResponseWriter, r *http.Request, id int, action string) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var (
+		transfer *models.DepartmentTransfer
+		err      error
+	)
+	switch action {
+	case "accept":
+		transfer, err = h.service.Accept(r.Context(), claims.ID, id)
+	case "reject":
+		transfer, err = h.service.Reject(r.Context(), claims.ID, id)
+	case "cancel":
+		transfer, err = h.service.Cancel(r.Context(), claims.ID, id)
+	default:
+		utils.NotFound(w, "Unknown department transfer action "+action)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, transfer)
+}
@@ -1,13 +1,14 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
@@ -18,11 +19,31 @@ type EmployeeHandler struct {
 }
 
 type EmployeeResponse struct {
-	IdentityNumber   string `json:"identityNumber"`
-	Name             string `json:"name"`
-	EmployeeImageUri string `json:"employeeImageUri"`
-	Gender           string `json:"gender"`
-	DepartmentId     int    `json:"departmentId"`
+	IdentityNumber    string                   `json:"identityNumber"`
+	Name              string                   `json:"name"`
+	EmployeeImageUri  string                   `json:"employeeImageUri"`
+	Gender            string                   `json:"gender"`
+	DepartmentId      int                      `json:"departmentId"`
+	CustomFields      models.CustomFieldValues `json:"customFields,omitempty"`
+	Status            models.EmploymentStatus  `json:"status"`
+	HireDate          *models.Date             `json:"hireDate,omitempty"`
+	TerminationDate   *models.Date             `json:"terminationDate,omitempty"`
+	TerminationReason *string                  `json:"terminationReason,omitempty"`
+}
+
+func newEmployeeResponse(employee models.Employee) EmployeeResponse {
+	return EmployeeResponse{
+		IdentityNumber:    employee.IdentityNumber,
+		Name:              employee.Name,
+		EmployeeImageUri:  employee.EmployeeImageURI,
+		Gender:            string(employee.Gender),
+		DepartmentId:      employee.DepartmentID,
+		CustomFields:      employee.CustomFields,
+		Status:            employee.Status,
+		HireDate:          employee.HireDate,
+		TerminationDate:   employee.TerminationDate,
+		TerminationReason: employee.TerminationReason,
+	}
 }
 
 func NewEmployeeHandler(service services.EmployeeService) *EmployeeHandler {
@@ -31,11 +52,19 @@ func NewEmployeeHandler(service services.EmployeeService) *EmployeeHandler {
 	}
 }
 
-func (h *EmployeeHandler) List(w http.ResponseWriter, r *http.Request) {
-	filter := models.FilterOptions{
+// customFieldQueryPrefix marks query parameters that filter on custom fields,
+// e.g. ?field.costCenter=CC-01
+const customFieldQueryPrefix = "field."
+
+// parseFilterOptions reads the list filters of the query. Invalid limit and
+// offset values fall back to the defaults, invalid gender and departmentId
+// values can't match any employee and make matchable false.
+func parseFilterOptions(r *http.Request) (filter models.FilterOptions, matchable bool) {
+	filter = models.FilterOptions{
 		Limit:  5, // default
 		Offset: 0, // default
 	}
+	matchable = true
 
 	// Parse query parameters
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -58,30 +87,62 @@ func (h *EmployeeHandler) List(w http.ResponseWriter, r *http.Request) {
 		if gender == "male" || gender == "female" {
 			g := models.Gender(gender)
 			filter.Gender = &g
+		} else {
+			matchable = false
 		}
 	}
 
 	if deptID := r.URL.Query().Get("departmentId"); deptID != "" {
 		if id, err := strconv.Atoi(deptID); err == nil {
 			filter.DepartmentID = &id
+		} else {
+			matchable = false
 		}
 	}
 
-	employees, err := h.service.List(r.Context(), filter)
-	if err != nil {
-		utils.SendErrorResponse(w, "Internal server error", http.StatusInternalServerError)
-		return
+	if status := r.URL.Query().Get("status"); status != "" {
+		for _, value := range strings.Split(status, ",") {
+			filter.Statuses = append(filter.Statuses, models.EmploymentStatus(strings.TrimSpace(value)))
+		}
+	}
+
+	for param, values := range r.URL.Query() {
+		key, ok := strings.CutPrefix(param, customFieldQueryPrefix)
+		if !ok || key == "" || len(values) == 0 {
+			continue
+		}
+		if filter.CustomFields == nil {
+			filter.CustomFields = make(models.CustomFieldValues)
+		}
+		filter.CustomFields[key] = values[0]
+	}
+
+	return filter, matchable
+}
+
+func (h *EmployeeHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter, matchable := parseFilterOptions(r)
+
+	var employees []models.Employee
+	if matchable {
+		var err error
+		employees, err = h.service.List(r.Context(), filter)
+		if err != nil {
+			utils.WriteError(w, err)
+			return
+		}
 	}
 
 	response := make([]EmployeeResponse, len(employees))
+	versions := make([]version, len(employees))
 	for i, emp := range employees {
-		response[i] = EmployeeResponse{
-			IdentityNumber:   emp.IdentityNumber,
-			Name:             emp.Name,
-			EmployeeImageUri: emp.EmployeeImageURI,
-			Gender:           string(emp.Gender),
-			DepartmentId:     emp.DepartmentID,
-		}
+		response[i] = newEmployeeResponse(emp)
+		versions[i] = version{id: emp.ID, updatedAt: emp.UpdatedAt}
+	}
+
+	etag, lastModified := collectionValidators(versions)
+	if notModified(w, r, etag, lastModified) {
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -90,7 +151,7 @@ func (h *EmployeeHandler) List(w http.ResponseWriter, r *http.Request) {
 		Data:    response,
 		Message: fmt.Sprintf("Successfully retrieved %d employees", len(response)),
 	}); err != nil {
-		log.Printf("Error encoding response: %v", err)
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
 		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
@@ -104,34 +165,28 @@ func (h *EmployeeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
+	if !validateRequest(w, req) {
+		return
+	}
+
 	// Create new employee
 	employee, err := h.service.Create(r.Context(), req)
 	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "unique_identity_number"):
-			utils.SendErrorResponse(w, fmt.Sprintf("Identity number %s is already registered", req.IdentityNumber), http.StatusConflict)
-		default:
-			utils.SendErrorResponse(w, "Failed to create employee", http.StatusInternalServerError)
-		}
+		utils.WriteError(w, err)
 		return
 	}
 
 	// Prepare response
-	response := EmployeeResponse{
-		IdentityNumber:   employee.IdentityNumber,
-		Name:             employee.Name,
-		EmployeeImageUri: employee.EmployeeImageURI,
-		Gender:           string(employee.Gender),
-		DepartmentId:     employee.DepartmentID,
-	}
+	response := newEmployeeResponse(*employee)
 
+	writeValidators(w, resourceETag(employee.UpdatedAt), employee.UpdatedAt)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(utils.Response{
 		Data:    response,
 		Message: fmt.Sprintf("Employee with ID %s created successfully", response.IdentityNumber),
 	}); err != nil {
-		log.Printf("Error encoding response: %v", err)
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
 		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
@@ -146,29 +201,34 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request, identit
 	}
 	defer r.Body.Close()
 
+	if !validateRequest(w, req) {
+		return
+	}
+
+	// Update only the version the client read, when it sent one
+	var ok bool
+	if req.IfUpdatedAt, ok = ifMatch(w, r); !ok {
+		return
+	}
+
 	// Update employee
 	employee, err := h.service.Update(r.Context(), identityNumber, req)
 	if err != nil {
-		utils.SendErrorResponse(w, "Failed to update employee", http.StatusInternalServerError)
+		utils.WriteError(w, err)
 		return
 	}
 
 	// Prepare response
-	response := EmployeeResponse{
-		IdentityNumber:   employee.IdentityNumber,
-		Name:             employee.Name,
-		EmployeeImageUri: employee.EmployeeImageURI,
-		Gender:           string(employee.Gender),
-		DepartmentId:     employee.DepartmentID,
-	}
+	response := newEmployeeResponse(*employee)
 
+	writeValidators(w, resourceETag(employee.UpdatedAt), employee.UpdatedAt)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(utils.Response{
 		Data:    response,
 		Message: fmt.Sprintf("Employee with ID %s updated successfully", response.IdentityNumber),
 	}); err != nil {
-		log.Printf("Error encoding response: %v", err)
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
 		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
@@ -177,14 +237,7 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request, identit
 func (h *EmployeeHandler) Delete(w http.ResponseWriter, r *http.Request, identityNumber string) {
 	err := h.service.Delete(r.Context(), identityNumber)
 	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "missing JWT claims"):
-			utils.SendErrorResponse(w, "expired / invalid / missing request token", http.StatusUnauthorized)
-		case err.Error() == "employee not found":
-			utils.SendErrorResponse(w, fmt.Sprintf("identityNumber %s is not found", identityNumber), http.StatusNotFound)
-		default:
-			utils.SendErrorResponse(w, "Server Error", http.StatusInternalServerError)
-		}
+		utils.WriteError(w, err)
 		return
 	}
 
@@ -193,7 +246,151 @@ func (h *EmployeeHandler) Delete(w http.ResponseWriter, r *http.Request, identit
 	if err := json.NewEncoder(w).Encode(utils.Response{
 		Message: fmt.Sprintf("Employee with ID %s deleted successfully", identityNumber),
 	}); err != nil {
-		log.Printf("Error encoding response: %v", err)
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *EmployeeHandler) BulkMove(w http.ResponseWriter, r *http.Request) {
+	var req models.BulkMoveRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, req) {
+		return
+	}
+
+	result, err := h.service.BulkMove(r.Context(), req)
+	h.sendBulkResult(w, r, result, err, fmt.Sprintf("moved to department %d", req.DepartmentID))
+}
+
+func (h *EmployeeHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	var req models.BulkEmployeeRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, req) {
+		return
+	}
+
+	result, err := h.service.BulkDelete(r.Context(), req)
+	h.sendBulkResult(w, r, result, err, "deleted")
+}
+
+// sendBulkResult responds with the per-employee results of a bulk operation,
+// with 422 when it was rolled back because some employees weren't found.
+func (h *EmployeeHandler) sendBulkResult(w http.ResponseWriter, r *http.Request, result *models.BulkResult, err error, verb string) {
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	statusCode := http.StatusOK
+	message := fmt.Sprintf("%d employees %s successfully", result.Succeeded, verb)
+	if !result.Applied {
+		statusCode = http.StatusUnprocessableEntity
+		message = fmt.Sprintf("%d employees not found, no employee was %s", result.Failed, verb)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    result,
+		Message: message,
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Export responds with every employee matching the list filters as a CSV file,
+// ignoring limit and offset. Like List, filters no employee can match export
+// none.
+func (h *EmployeeHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filter, matchable := parseFilterOptions(r)
+	exported := &filter
+	if !matchable {
+		exported = nil
+	}
+
+	var buf bytes.Buffer
+	if err := h.service.Export(r.Context(), exported, &buf); err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="employees.csv"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		logging.FromContext(r.Context()).Error("Error writing export", "error", err)
+	}
+}
+
+func (h *EmployeeHandler) ChangeStatus(w http.ResponseWriter, r *http.Request, identityNumber string) {
+	var req models.ChangeStatusRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, req) {
+		return
+	}
+
+	employee, err := h.service.ChangeStatus(r.Context(), identityNumber, req)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	response := newEmployeeResponse(*employee)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    response,
+		Message: fmt.Sprintf("Employee with ID %s is now %s", response.IdentityNumber, response.Status),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *EmployeeHandler) StatusHistory(w http.ResponseWriter, r *http.Request, identityNumber string) {
+	events, err := h.service.StatusHistory(r.Context(), identityNumber)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	if events == nil {
+		events = []models.EmploymentEvent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    events,
+		Message: fmt.Sprintf("Successfully retrieved %d status changes", len(events)),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
 		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+type FileHandler struct {
+	service services.FileService
+}
+
+func NewFileHandler(service services.FileService) *FileHandler {
+	return &FileHandler{
+		service: service,
+	}
+}
+
+// Upload stores the file sent in the file field of a multipart form
+func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.SendErrorResponse(w, "A file is required in the file field of a multipart form", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if header.Size == 0 {
+		utils.SendErrorResponse(w, "File must not be empty", http.StatusBadRequest)
+		return
+	}
+	if header.Size > models.MaxFileBytes {
+		utils.SendErrorResponse(w, fmt.Sprintf("File must be at most %dKiB", models.MaxFileBytes>>10), http.StatusBadRequest)
+		return
+	}
+
+	uploaded, err := h.service.Upload(r.Context(), header.Filename, file)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(uploaded); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to send response", "file_id", uploaded.FileID, "error", err)
+		utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,88 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/handlers"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+// fakeFileService records the files uploaded to it
+type fakeFileService struct {
+	uploads map[string][]byte
+}
+
+func (s *fakeFileService) Upload(ctx context.Context, filename string, content io.Reader) (*models.File, error) {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if s.uploads == nil {
+		s.uploads = make(map[string][]byte)
+	}
+	s.uploads[filename] = b
+	return &models.File{FileID: "1", URI: "/files/1"}, nil
+}
+
+// uploadRequest builds a multipart request sending content in field
+func uploadRequest(t *testing.T, field string, filename string, content []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, filename)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/file", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestFileHandler_Upload(t *testing.T) {
+	service := &fakeFileService{}
+	handler := handlers.NewFileHandler(service)
+
+	res := httptest.NewRecorder()
+	handler.Upload(res, uploadRequest(t, "file", "photo.jpg", []byte("jpeg")))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var file models.File
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &file))
+	assert.Equal(t, models.File{FileID: "1", URI: "/files/1"}, file)
+	assert.Equal(t, []byte("jpeg"), service.uploads["photo.jpg"])
+}
+
+func TestFileHandler_Upload_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"not multipart", httptest.NewRequest(http.MethodPost, "/v1/file", strings.NewReader(`{}`))},
+		{"other field", uploadRequest(t, "image", "photo.jpg", []byte("jpeg"))},
+		{"empty", uploadRequest(t, "file", "photo.jpg", nil)},
+		{"too large", uploadRequest(t, "file", "photo.jpg", make([]byte, models.MaxFileBytes+1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeFileService{}
+			handler := handlers.NewFileHandler(service)
+
+			res := httptest.NewRecorder()
+			handler.Upload(res, tt.req)
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Empty(t, service.uploads)
+		})
+	}
+}
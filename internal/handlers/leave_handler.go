@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+type LeaveHandler struct {
+	service services.LeaveService
+}
+
+func NewLeaveHandler(service services.LeaveService) *LeaveHandler {
+	return &LeaveHandler{
+		service: service,
+	}
+}
+
+func (h *LeaveHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateLeaveRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if !validateRequest(w, req) {
+		return
+	}
+
+	leave, err := h.service.Create(r.Context(), claims.ID, req)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    leave,
+		Message: fmt.Sprintf("Leave request for %s created successfully", leave.IdentityNumber),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *LeaveHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	filter := models.LeaveFilterOptions{
+		Limit:  5, // default
+		Offset: 0, // default
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filter.Offset = offset
+		}
+	}
+
+	if identityNumber := r.URL.Query().Get("identityNumber"); identityNumber != "" {
+		filter.IdentityNumber = &identityNumber
+	}
+
+	if status := models.LeaveStatus(r.URL.Query().Get("status")); status.Valid() {
+		filter.Status = &status
+	}
+
+	leaves, err := h.service.List(r.Context(), claims.ID, filter)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	if leaves == nil {
+		leaves = []models.LeaveRequest{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    leaves,
+		Message: fmt.Sprintf("Successfully retrieved %d leave requests", len(leaves)),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Decide applies action (approve, reject or cancel) to the leave request
+func (h *LeaveHandler) Decide(w http.ResponseWriter, r *http.Request, id int, action string) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	// The decision note is optional, so is the body
+	var req models.LeaveDecisionRequest
+	if r.ContentLength != 0 {
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		if !validateRequest(w, req) {
+			return
+		}
+	}
+
+	var (
+		leave *models.LeaveRequest
+		err   error
+	)
+	switch action {
+	case "approve":
+		leave, err = h.service.Approve(r.Context(), claims.ID, id, req)
+	case "reject":
+		leave, err = h.service.Reject(r.Context(), claims.ID, id, req)
+	case "cancel":
+		leave, err = h.service.Cancel(r.Context(), claims.ID, id, req)
+	default:
+		utils.NotFound(w, fmt.Sprintf("Unknown leave action %s", action))
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    leave,
+		Message: fmt.Sprintf("Leave request %d is now %s", leave.ID, leave.Status),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *LeaveHandler) Balances(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	identityNumber := r.URL.Query().Get("identityNumber")
+	if identityNumber == "" {
+		utils.BadRequest(w, "identityNumber is required")
+		return
+	}
+
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil || parsed < 1900 || parsed > 9999 {
+			utils.BadRequest(w, "year must be a valid year")
+			return
+		}
+		year = parsed
+	}
+
+	balances, err := h.service.Balances(r.Context(), claims.ID, identityNumber, year)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    balances,
+		Message: fmt.Sprintf("Leave balances of %s for %d", identityNumber, year),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Calendar lists who is out between the from and to dates (inclusive),
+// defaulting to the current week.
+func (h *LeaveHandler) Calendar(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	from := models.Today()
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		parsed, err := models.ParseDate(fromStr)
+		if err != nil {
+			utils.BadRequest(w, err.Error())
+			return
+		}
+		from = parsed
+	}
+
+	to := models.NewDate(from.AddDate(0, 0, 6))
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		parsed, err := models.ParseDate(toStr)
+		if err != nil {
+			utils.BadRequest(w, err.Error())
+			return
+		}
+		to = parsed
+	}
+
+	var departmentID *int
+	if deptID := r.URL.Query().Get("departmentId"); deptID != "" {
+		if id, err := strconv.Atoi(deptID); err == nil {
+			departmentID = &id
+		}
+	}
+
+	entries, err := h.service.Calendar(r.Context(), claims.ID, from, to, departmentID)
+	if err != nil {
+		utils.WriteError(w, err)
+		return
+	}
+
+	if entries == nil {
+		entries = []models.LeaveCalendarEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(utils.Response{
+		Data:    entries,
+		Message: fmt.Sprintf("%d absences between %s and %s", len(entries), from, to),
+	}); err != nil {
+		logging.FromContext(r.Context()).Error("Error encoding response", "error", err)
+		utils.SendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
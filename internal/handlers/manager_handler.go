@@ -2,10 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
@@ -20,19 +20,23 @@ func NewManagerHandler(managerService services.ManagerService) *ManagerHandler {
 
 func (h *ManagerHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		utils.SendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
 	if !ok {
-		http.Error(w, "User not aunthenticated", http.StatusUnauthorized)
+		utils.WriteError(w, utils.ErrMissingClaims)
 		return
 	}
 
-	manager, err := h.managerService.GetByID(claims.ID)
+	manager, err := h.managerService.GetByID(r.Context(), claims.ID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		utils.WriteError(w, err)
+		return
+	}
+
+	if notModified(w, r, resourceETag(manager.UpdatedAt), manager.UpdatedAt) {
 		return
 	}
 
@@ -42,7 +46,7 @@ func (h *ManagerHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Println("Failed to write response:", err)
+		logging.FromContext(r.Context()).Error("Failed to write response", "error", err)
 		utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -51,78 +55,58 @@ func (h *ManagerHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 func (h *ManagerHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	var input utils.ManagerRequest
 	if r.Method != http.MethodPatch {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		utils.SendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&input); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		utils.SendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !validateRequest(w, input) {
 		return
 	}
 
 	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
 	if !ok {
-		http.Error(w, "User not aunthenticated", http.StatusUnauthorized)
+		utils.WriteError(w, utils.ErrMissingClaims)
 		return
 	}
 
 	//assign manager id
 	input.ID = claims.ID
 
+	// Update only the version the client read, when it sent one
+	if input.IfUpdatedAt, ok = ifMatch(w, r); !ok {
+		return
+	}
+
 	//update manager
-	updateErr := h.managerService.Update(&input)
+	updateErr := h.managerService.Update(r.Context(), &input)
 	if updateErr != nil {
-		switch updateErr.Type {
-		case utils.SQLUniqueViolated:
-			utils.SendErrorResponse(w, "Email already registered", http.StatusConflict)
-			return
-		case utils.InvalidEmailFormat:
-			utils.SendErrorResponse(w, "Invalid email format", http.StatusBadRequest)
-			return
-		case utils.InvalidPasswordLength:
-			utils.SendErrorResponse(w, "Invalid password length (min length: 8, max length: 32)", http.StatusBadRequest)
-			return
-		case utils.InvalidNameLength:
-			utils.SendErrorResponse(w, "Invalid name length (min length: 4, max length: 52)", http.StatusBadRequest)
-			return
-		default:
-			log.Println("Failed to create manager:", updateErr)
-			utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
+		utils.WriteError(w, updateErr)
+		return
 	}
 
 	//get updated manager
-	result, queryErr := h.managerService.GetByID(claims.ID)
+	result, queryErr := h.managerService.GetByID(r.Context(), claims.ID)
 	if queryErr != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		utils.WriteError(w, queryErr)
 		return
 	}
 
 	response := result.ToManagerResponse()
+	writeValidators(w, resourceETag(result.UpdatedAt), result.UpdatedAt)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Println("Failed to write response:", err)
+		logging.FromContext(r.Context()).Error("Failed to write response", "error", err)
 		utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 }
-
-func (h *ManagerHandler) Manager(w http.ResponseWriter, r *http.Request) {
-	http.HandlerFunc(
-		func(w http.ResponseWriter, r *http.Request) {
-			switch r.Method {
-			case http.MethodGet:
-				h.GetUser(w, r) // Handle GET requests
-			case http.MethodPatch:
-				h.UpdateUser(w, r) // Handle PATCH requests
-			default:
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		}).ServeHTTP(w, r)
-}
@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/handlers"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+	mocksServices "github.com/ngikut-project-sprint/GoGoManager/mocks/services"
+)
+
+func withManager(req *http.Request, id int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), constants.JWTKey, &utils.Claims{ID: id}))
+}
+
+func TestManagerHandler_GetUser_NotModified(t *testing.T) {
+	mockService := new(mocksServices.ManagerService)
+	handler := handlers.NewManagerHandler(mockService)
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	mockService.On("GetByID", mock.Anything, 1).Return(&models.Manager{ID: 1, Email: "a@b.com", UpdatedAt: updatedAt}, nil)
+
+	res := httptest.NewRecorder()
+	handler.GetUser(res, withManager(httptest.NewRequest(http.MethodGet, "/v1/user", nil), 1))
+	assert.Equal(t, http.StatusOK, res.Code)
+	etag := res.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "Thu, 02 Jan 2025 03:04:05 GMT", res.Header().Get("Last-Modified"))
+
+	req := withManager(httptest.NewRequest(http.MethodGet, "/v1/user", nil), 1)
+	req.Header.Set("If-None-Match", `"stale", `+etag)
+	res = httptest.NewRecorder()
+	handler.GetUser(res, req)
+	assert.Equal(t, http.StatusNotModified, res.Code)
+	assert.Empty(t, res.Body.String())
+
+	req = withManager(httptest.NewRequest(http.MethodGet, "/v1/user", nil), 1)
+	req.Header.Set("If-None-Match", `"stale"`)
+	res = httptest.NewRecorder()
+	handler.GetUser(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestManagerHandler_GetUser_Errors(t *testing.T) {
+	mockService := new(mocksServices.ManagerService)
+	handler := handlers.NewManagerHandler(mockService)
+	mockService.On("GetByID", mock.Anything, 1).Return(nil, models.ErrManagerNotFound.Wrap(sql.ErrNoRows))
+	mockService.On("GetByID", mock.Anything, 2).Return(nil, utils.WrapError(sql.ErrConnDone, utils.SQLError, "Error querying manager by id"))
+
+	res := httptest.NewRecorder()
+	handler.GetUser(res, withManager(httptest.NewRequest(http.MethodGet, "/v1/user", nil), 1))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Contains(t, res.Body.String(), "MANAGER_NOT_FOUND")
+
+	// A failing database isn't reported as a missing manager
+	res = httptest.NewRecorder()
+	handler.GetUser(res, withManager(httptest.NewRequest(http.MethodGet, "/v1/user", nil), 2))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+}
+
+func TestManagerHandler_UpdateUser_IfMatch(t *testing.T) {
+	mockService := new(mocksServices.ManagerService)
+	handler := handlers.NewManagerHandler(mockService)
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	mockService.On("GetByID", mock.Anything, 1).Return(&models.Manager{ID: 1, Email: "a@b.com", UpdatedAt: updatedAt}, nil)
+
+	res := httptest.NewRecorder()
+	handler.GetUser(res, withManager(httptest.NewRequest(http.MethodGet, "/v1/user", nil), 1))
+	etag := res.Header().Get("ETag")
+
+	// The ETag is sent back as the version the update expects
+	mockService.On("Update", mock.Anything, mock.MatchedBy(func(req *utils.ManagerRequest) bool {
+		return req.IfUpdatedAt != nil && req.IfUpdatedAt.Equal(updatedAt)
+	})).Return(nil).Once()
+	req := withManager(httptest.NewRequest(http.MethodPatch, "/v1/user", strings.NewReader(`{"name": "Alice"}`)), 1)
+	req.Header.Set("If-Match", etag)
+	res = httptest.NewRecorder()
+	handler.UpdateUser(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, etag, res.Header().Get("ETag"))
+
+	// Another update went through meanwhile
+	mockService.On("Update", mock.Anything, mock.Anything).Return(utils.ErrPreconditionFailed).Once()
+	req = withManager(httptest.NewRequest(http.MethodPatch, "/v1/user", strings.NewReader(`{"name": "Alice"}`)), 1)
+	req.Header.Set("If-Match", etag)
+	res = httptest.NewRecorder()
+	handler.UpdateUser(res, req)
+	assert.Equal(t, http.StatusPreconditionFailed, res.Code)
+	assert.Contains(t, res.Body.String(), "PRECONDITION_FAILED")
+
+	// ETags that aren't ours never match
+	req = withManager(httptest.NewRequest(http.MethodPatch, "/v1/user", strings.NewReader(`{"name": "Alice"}`)), 1)
+	req.Header.Set("If-Match", `W/"abc"`)
+	res = httptest.NewRecorder()
+	handler.UpdateUser(res, req)
+	assert.Equal(t, http.StatusPreconditionFailed, res.Code)
+
+	mockService.AssertExpectations(t)
+}
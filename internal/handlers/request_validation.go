@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/validators"
+)
+
+// validateRequest checks req against its `validate` tags, writing a 400
+// response listing every failing field and returning false when it's invalid.
+func validateRequest(w http.ResponseWriter, req interface{}) bool {
+	violations := validators.ValidateStruct(req)
+	if len(violations) == 0 {
+		return true
+	}
+
+	details := make([]utils.FieldError, 0, len(violations))
+	for _, violation := range violations {
+		details = append(details, utils.FieldError{
+			Field:   violation.Field,
+			Reason:  violation.Rule,
+			Message: violation.Message,
+		})
+	}
+
+	utils.WriteError(w, utils.ErrValidationFailed.WithDetails(details...))
+	return false
+}
@@ -2,22 +2,22 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
 func ExampleSecureHander(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		utils.SendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
 	if !ok {
-		http.Error(w, "User not aunthenticated", http.StatusUnauthorized)
+		utils.SendErrorResponse(w, "User not aunthenticated", http.StatusUnauthorized)
 		return
 	}
 
@@ -27,8 +27,8 @@ func ExampleSecureHander(w http.ResponseWriter, r *http.Request) {
 	}
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
-		log.Println("Failed to marshal response:", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Failed to marshal response", "error", err)
+		utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
@@ -36,8 +36,8 @@ func ExampleSecureHander(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 
 	if _, err := w.Write(jsonResponse); err != nil {
-		log.Println("Failed to write response:", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Failed to write response", "error", err)
+		utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 }
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+)
+
+// checkTimeout bounds the checks of one readiness probe
+const checkTimeout = 2 * time.Second
+
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+)
+
+// Check reports whether a dependency of the API works
+type Check func(ctx context.Context) error
+
+// Report is the result of a readiness probe, with the status of every check
+// by name
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Ready tells whether every check passed
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs the readiness checks of the API. Checks are added while the
+// API starts, before it serves.
+type Checker struct {
+	names  []string
+	checks []Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Add registers check under name, which labels it in the reports
+func (c *Checker) Add(name string, check Check) {
+	c.names = append(c.names, name)
+	c.checks = append(c.checks, check)
+}
+
+// Check runs the checks concurrently. The errors of the failing checks are
+// logged, the report only tells they fail.
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	errs := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]string, len(c.checks))}
+	for i, err := range errs {
+		if err != nil {
+			logging.FromContext(ctx).Warn("Readiness check failed", "check", c.names[i], "error", err)
+			report.Status = StatusFailing
+			report.Checks[c.names[i]] = StatusFailing
+			continue
+		}
+		report.Checks[c.names[i]] = StatusOK
+	}
+	return report
+}
+
+// Database checks that db accepts connections
+func Database(db *sql.DB) Check {
+	return db.PingContext
+}
@@ -0,0 +1,48 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrNotReserved is returned when completing a key no longer reserved by the
+// caller, its reservation expired and another request took the key over
+var ErrNotReserved = errors.New("idempotency key is not reserved by this request")
+
+// Response is the response of the first request sent with a key, replayed
+// to its retries
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// Record is kept for every key in use. Fingerprint identifies the request
+// that first used the key and Token its reservation. Response is nil while
+// the request is still served.
+type Record struct {
+	Fingerprint string    `json:"fingerprint"`
+	Token       string    `json:"token"`
+	Response    *Response `json:"response,omitempty"`
+}
+
+// Pending tells whether the request that reserved the key is still served
+func (r Record) Pending() bool {
+	return r.Response == nil
+}
+
+// Store keeps the records, by key
+type Store interface {
+	// Reserve keeps the pending record for key during ttl. When key already
+	// has a record it is returned instead, and nothing changes.
+	Reserve(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, error)
+	// Complete replaces the record of key by record, kept for ttl, as long
+	// as the key is still reserved by record.Token. Otherwise it fails with
+	// ErrNotReserved and nothing changes.
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release drops the record of key if it is still reserved by token, so
+	// the request can be sent again
+	Release(ctx context.Context, key string, token string) error
+}
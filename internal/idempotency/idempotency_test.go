@@ -0,0 +1,125 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/idempotency"
+)
+
+func newRedisStore(t *testing.T) (*idempotency.RedisStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return idempotency.NewRedisStore(client, "test:"), server
+}
+
+func pending(fingerprint string, token string) idempotency.Record {
+	return idempotency.Record{Fingerprint: fingerprint, Token: token}
+}
+
+func TestStores_ReserveCompleteRelease(t *testing.T) {
+	redisStore, _ := newRedisStore(t)
+	stores := map[string]idempotency.Store{
+		"memory": idempotency.NewMemoryStore(),
+		"redis":  redisStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			record, err := store.Reserve(ctx, "manager:1:abc", pending("first", "t1"), time.Minute)
+			require.NoError(t, err)
+			assert.Nil(t, record)
+
+			// The key is taken until the first request completes
+			record, err = store.Reserve(ctx, "manager:1:abc", pending("second", "t2"), time.Minute)
+			require.NoError(t, err)
+			require.NotNil(t, record)
+			assert.Equal(t, "first", record.Fingerprint)
+			assert.True(t, record.Pending())
+
+			// Only the reservation holding the key completes or releases it
+			response := &idempotency.Response{
+				Status: http.StatusCreated,
+				Header: http.Header{"Content-Type": {"application/json"}},
+				Body:   []byte(`{"id":1}`),
+			}
+			err = store.Complete(ctx, "manager:1:abc", idempotency.Record{Fingerprint: "second", Token: "t2", Response: response}, time.Hour)
+			assert.ErrorIs(t, err, idempotency.ErrNotReserved)
+			require.NoError(t, store.Release(ctx, "manager:1:abc", "t2"))
+
+			require.NoError(t, store.Complete(ctx, "manager:1:abc", idempotency.Record{Fingerprint: "first", Token: "t1", Response: response}, time.Hour))
+			record, err = store.Reserve(ctx, "manager:1:abc", pending("first", "t3"), time.Minute)
+			require.NoError(t, err)
+			require.NotNil(t, record)
+			assert.False(t, record.Pending())
+			assert.Equal(t, response, record.Response)
+
+			// Keys are independent
+			record, err = store.Reserve(ctx, "manager:2:abc", pending("first", "t4"), time.Minute)
+			require.NoError(t, err)
+			assert.Nil(t, record)
+
+			// Released keys can be reserved again
+			require.NoError(t, store.Release(ctx, "manager:2:abc", "t4"))
+			record, err = store.Reserve(ctx, "manager:2:abc", pending("other", "t5"), time.Minute)
+			require.NoError(t, err)
+			assert.Nil(t, record)
+
+			// Keys that aren't reserved can't be completed
+			err = store.Complete(ctx, "manager:3:abc", idempotency.Record{Fingerprint: "first", Token: "t6", Response: response}, time.Hour)
+			assert.ErrorIs(t, err, idempotency.ErrNotReserved)
+		})
+	}
+}
+
+func TestMemoryStore_Expires(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	ctx := context.Background()
+
+	_, err := store.Reserve(ctx, "manager:1:abc", pending("first", "t1"), time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	record, err := store.Reserve(ctx, "manager:1:abc", pending("second", "t2"), time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	// The expired reservation no longer holds the key
+	err = store.Complete(ctx, "manager:1:abc", idempotency.Record{Fingerprint: "first", Token: "t1", Response: &idempotency.Response{}}, time.Hour)
+	assert.ErrorIs(t, err, idempotency.ErrNotReserved)
+}
+
+func TestRedisStore_Expires(t *testing.T) {
+	store, server := newRedisStore(t)
+	ctx := context.Background()
+
+	_, err := store.Reserve(ctx, "manager:1:abc", pending("first", "t1"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, server.Exists("test:manager:1:abc"))
+
+	server.FastForward(time.Minute)
+	record, err := store.Reserve(ctx, "manager:1:abc", pending("second", "t2"), time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	// The expired reservation leaves the new one alone
+	require.NoError(t, store.Release(ctx, "manager:1:abc", "t1"))
+	assert.True(t, server.Exists("test:manager:1:abc"))
+}
+
+func TestRedisStore_Unavailable(t *testing.T) {
+	store, server := newRedisStore(t)
+	server.Close()
+
+	_, err := store.Reserve(context.Background(), "manager:1:abc", pending("first", "t1"), time.Minute)
+	assert.Error(t, err)
+}
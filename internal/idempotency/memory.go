@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore.Sweep drops the expired records
+const sweepInterval = time.Minute
+
+type entry struct {
+	record  Record
+	expires time.Time
+}
+
+// MemoryStore keeps the records in memory, each instance of the API
+// replaying only the requests it served
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]entry)}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		existing := e.record
+		return &existing, nil
+	}
+
+	s.entries[key] = entry{record: record, expires: now.Add(ttl)}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.reservedBy(key, record.Token) {
+		return ErrNotReserved
+	}
+	s.entries[key] = entry{record: record, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key string, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reservedBy(key, token) {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// reservedBy tells whether key is reserved by token, the lock must be held
+func (s *MemoryStore) reservedBy(key string, token string) bool {
+	e, ok := s.entries[key]
+	return ok && time.Now().Before(e.expires) && e.record.Token == token
+}
+
+// Sweep drops the expired records every minute until ctx is done. It runs
+// as a background worker.
+func (s *MemoryStore) Sweep(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+func (s *MemoryStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
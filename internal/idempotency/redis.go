@@ -0,0 +1,100 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// reserveScript keeps ARGV[2], reserved by ARGV[1], under KEYS[1] for ARGV[3]
+// milliseconds unless the key exists. It returns the existing record, if any.
+var reserveScript = redis.NewScript(`
+local existing = redis.call('HGET', KEYS[1], 'record')
+if existing then
+  return existing
+end
+redis.call('HSET', KEYS[1], 'token', ARGV[1], 'record', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return false
+`)
+
+// completeScript replaces the record of KEYS[1] by ARGV[2], kept for ARGV[3]
+// milliseconds, if the key is reserved by ARGV[1]. It returns 1 if replaced.
+var completeScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'token') ~= ARGV[1] then
+  return 0
+end
+redis.call('HSET', KEYS[1], 'record', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// releaseScript drops KEYS[1] if it is reserved by ARGV[1]
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'token') == ARGV[1] then
+  redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisStore keeps the records in Redis, or a server speaking its protocol,
+// shared by every instance of the API. Every key is a hash of the token of
+// its reservation and the record as JSON, so the scripts compare tokens
+// without decoding records.
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisStore keeps the records in client under keys starting with prefix
+func NewRedisStore(client redis.Scripter, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Reserve(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("reserve %s: %w", key, err)
+	}
+
+	existing, err := reserveScript.Run(ctx, s.client, []string{s.prefix + key}, record.Token, value, ttl.Milliseconds()).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reserve %s: %w", key, err)
+	}
+
+	var found Record
+	if err := json.Unmarshal([]byte(existing), &found); err != nil {
+		return nil, fmt.Errorf("reserve %s: %w", key, err)
+	}
+	return &found, nil
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("complete %s: %w", key, err)
+	}
+
+	replaced, err := completeScript.Run(ctx, s.client, []string{s.prefix + key}, record.Token, value, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("complete %s: %w", key, err)
+	}
+	if replaced != 1 {
+		return ErrNotReserved
+	}
+	return nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string, token string) error {
+	if err := releaseScript.Run(ctx, s.client, []string{s.prefix + key}, token).Err(); err != nil {
+		return fmt.Errorf("release %s: %w", key, err)
+	}
+	return nil
+}
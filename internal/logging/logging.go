@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+)
+
+// New creates the logger of the application writing to w
+func New(cfg config.LogConfig, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+		}
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q, expected json or text", cfg.Format)
+	}
+}
+
+// scope holds the logger of a request. It is shared by every context derived
+// from the request, so attributes added deep in the stack, like the manager
+// id, also end up on the access log line.
+type scope struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+}
+
+// WithLogger returns a context carrying logger, see FromContext
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, constants.LoggerKey, &scope{logger: logger})
+}
+
+// FromContext returns the logger of the request, or the default logger
+// outside of requests
+func FromContext(ctx context.Context) *slog.Logger {
+	if s, ok := ctx.Value(constants.LoggerKey).(*scope); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.logger
+	}
+	return slog.Default()
+}
+
+// Annotate adds attributes, as key value pairs, to every following log line
+// of the request
+func Annotate(ctx context.Context, args ...any) {
+	if s, ok := ctx.Value(constants.LoggerKey).(*scope); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.logger = s.logger.With(args...)
+	}
+}
@@ -0,0 +1,48 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(config.LogConfig{Level: "warn", Format: "text"}, &buf)
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "msg=shown")
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := logging.New(config.LogConfig{Level: "loud"}, &bytes.Buffer{})
+	assert.Error(t, err)
+
+	_, err = logging.New(config.LogConfig{Format: "xml"}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, slog.Default(), logging.FromContext(context.Background()))
+
+	var buf bytes.Buffer
+	ctx := logging.WithLogger(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	// Annotations are seen through contexts derived before and after them
+	derived := context.WithValue(ctx, struct{}{}, "value")
+	logging.Annotate(ctx, "manager_id", 7)
+	logging.FromContext(derived).Info("annotated")
+
+	assert.Contains(t, buf.String(), "manager_id=7")
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// scrapeTimeout bounds the queries run while collecting the business gauges
+const scrapeTimeout = 5 * time.Second
+
+// RegisterDatabase registers the connection pool statistics of db and the
+// business gauges read from it on registerer
+func RegisterDatabase(registerer prometheus.Registerer, db *sql.DB) error {
+	if err := registerer.Register(collectors.NewDBStatsCollector(db, "postgres")); err != nil {
+		return err
+	}
+	return registerer.Register(NewBusinessCollector(db))
+}
+
+// BusinessCollector reports the number of employees and departments of each
+// manager, read from the database on every scrape
+type BusinessCollector struct {
+	db          *sql.DB
+	employees   *prometheus.Desc
+	departments *prometheus.Desc
+}
+
+func NewBusinessCollector(db *sql.DB) *BusinessCollector {
+	return &BusinessCollector{
+		db: db,
+		employees: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "employees"),
+			"Employees not deleted, by manager.",
+			[]string{"manager_id"}, nil,
+		),
+		departments: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "departments"),
+			"Departments not deleted, by manager.",
+			[]string{"manager_id"}, nil,
+		),
+	}
+}
+
+func (c *BusinessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.employees
+	ch <- c.departments
+}
+
+func (c *BusinessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT d.manager_id, COUNT(DISTINCT d.department_id), COUNT(e.id)
+		FROM departments d
+		LEFT JOIN employees e ON e.department_id = d.department_id AND e.deleted_at IS NULL
+		WHERE d.deleted_at IS NULL
+		GROUP BY d.manager_id`)
+	if err != nil {
+		slog.Error("Failed to collect business metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.employees, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var managerID int
+		var departments, employees float64
+		if err := rows.Scan(&managerID, &departments, &employees); err != nil {
+			ch <- prometheus.NewInvalidMetric(c.employees, err)
+			return
+		}
+
+		id := strconv.Itoa(managerID)
+		ch <- prometheus.MustNewConstMetric(c.departments, prometheus.GaugeValue, departments, id)
+		ch <- prometheus.MustNewConstMetric(c.employees, prometheus.GaugeValue, employees, id)
+	}
+	if err := rows.Err(); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.employees, err)
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// namespace prefixes every metric of the application
+const namespace = "gogomanager"
+
+// UnmatchedRoute labels requests no route matches, keeping the route label
+// bounded
+const UnmatchedRoute = "unmatched"
+
+var (
+	// HTTPRequests counts the served requests by route pattern, method and
+	// status
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration observes the latency of the served requests
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the HTTP requests, by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// AuthAttempts counts registrations and logins by action (create or
+	// login) and result (success or failure)
+	AuthAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_attempts_total",
+		Help:      "Registrations and logins, by action and result.",
+	}, []string{"action", "result"})
+
+	// CacheRequests counts the lookups of the caches by cache and result (hit
+	// or miss), the hit rate being the hits over all lookups of a cache
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups, by cache and result.",
+	}, []string{"cache", "result"})
+)
+
+// RecordCache counts a lookup of cache
+func RecordCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// RecordAuth counts an auth attempt of action
+func RecordAuth(action string, success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	AuthAttempts.WithLabelValues(action, result).Inc()
+}
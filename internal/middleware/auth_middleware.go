@@ -3,7 +3,6 @@ package middleware
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
@@ -34,7 +34,7 @@ func AuthMiddleware(parseJWT utils.ParseJWT, next http.Handler) http.Handler {
 		// Get jwt secret from config
 		cfg, ok := r.Context().Value(constants.ConfigKey).(*config.Config)
 		if !ok {
-			log.Println("Configuration not found")
+			logging.FromContext(r.Context()).Error("configuration not found")
 			utils.SendErrorResponse(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -66,6 +66,7 @@ func AuthMiddleware(parseJWT utils.ParseJWT, next http.Handler) http.Handler {
 		}
 
 		ctx := context.WithValue(r.Context(), constants.JWTKey, claims)
+		logging.Annotate(ctx, "manager_id", claims.ID)
 
 		// Create a custom type for context keys (typically at package level)
 		type contextKey string
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// CORSMiddleware lets browsers call the API from the allowed origins. It
+// answers the preflight requests itself, 204 when the origin, method and
+// headers are allowed and 403 otherwise. Without allowed origins it does
+// nothing.
+func CORSMiddleware(cfg config.CORSConfig, next http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return next
+	}
+
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	allowedHeaders := make([]string, 0, len(cfg.AllowedHeaders))
+	for _, header := range cfg.AllowedHeaders {
+		allowedHeaders = append(allowedHeaders, http.CanonicalHeaderKey(strings.TrimSpace(header)))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !anyOrigin && !slices.Contains(cfg.AllowedOrigins, origin) {
+			if preflight {
+				utils.SendErrorResponse(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Credentials can't be sent to the * origin, echo the origin instead
+		if anyOrigin && !cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(cfg.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !slices.Contains(cfg.AllowedMethods, r.Header.Get("Access-Control-Request-Method")) {
+			utils.SendErrorResponse(w, "Method not allowed by CORS", http.StatusForbidden)
+			return
+		}
+		for _, requested := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			requested = strings.TrimSpace(requested)
+			if requested != "" && !slices.Contains(allowedHeaders, http.CanonicalHeaderKey(requested)) {
+				utils.SendErrorResponse(w, "Header "+requested+" not allowed by CORS", http.StatusForbidden)
+				return
+			}
+		}
+
+		header.Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+		if len(allowedHeaders) > 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+		}
+		if cfg.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
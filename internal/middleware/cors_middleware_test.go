@@ -0,0 +1,97 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	handler := middleware.CORSMiddleware(config.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPatch},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method string, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/employee", nil)
+		req.Header.Set("Origin", origin)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	t.Run("preflight", func(t *testing.T) {
+		res := serve(http.MethodOptions, "https://app.example.com", map[string]string{
+			"Access-Control-Request-Method":  http.MethodPatch,
+			"Access-Control-Request-Headers": "authorization, content-type",
+		})
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, "https://app.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, PATCH", res.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, Content-Type", res.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", res.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("preflight of a method not allowed", func(t *testing.T) {
+		res := serve(http.MethodOptions, "https://app.example.com", map[string]string{
+			"Access-Control-Request-Method": http.MethodDelete,
+		})
+		assert.Equal(t, http.StatusForbidden, res.Code)
+	})
+
+	t.Run("preflight of a header not allowed", func(t *testing.T) {
+		res := serve(http.MethodOptions, "https://app.example.com", map[string]string{
+			"Access-Control-Request-Method":  http.MethodGet,
+			"Access-Control-Request-Headers": "X-Debug",
+		})
+		assert.Equal(t, http.StatusForbidden, res.Code)
+	})
+
+	t.Run("preflight from an origin not allowed", func(t *testing.T) {
+		res := serve(http.MethodOptions, "https://evil.example.com", map[string]string{
+			"Access-Control-Request-Method": http.MethodGet,
+		})
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("request", func(t *testing.T) {
+		res := serve(http.MethodGet, "https://app.example.com", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "https://app.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "X-Request-ID", res.Header().Get("Access-Control-Expose-Headers"))
+		assert.Contains(t, res.Header().Values("Vary"), "Origin")
+	})
+
+	t.Run("request from an origin not allowed", func(t *testing.T) {
+		res := serve(http.MethodGet, "https://evil.example.com", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestCORSMiddleware_AnyOrigin(t *testing.T) {
+	handler := middleware.CORSMiddleware(config.CORSConfig{AllowedOrigins: []string{"*"}},
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/employee", nil)
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+}
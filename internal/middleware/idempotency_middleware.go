@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/idempotency"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyMiddleware makes retrying a POST request safe. The response of
+// the first request carrying an Idempotency-Key is kept for ttl, by key and
+// manager, so it must run inside AuthMiddleware. Retries with the same
+// method, path and body get it replayed with Idempotent-Replayed: true,
+// without running the handler again. Reusing a key for another request is
+// answered 422, retrying while the first request is still served 409.
+//
+// The first request holds its key for lockTTL at most, so a crashed instance
+// doesn't lock it for the whole ttl. A retry sent once lockTTL is over runs
+// again, and only its response is kept. Server errors aren't kept, the
+// request can be retried. Other methods, requests without a key and every request
+// when the store fails go through as is.
+func IdempotencyMiddleware(store idempotency.Store, ttl time.Duration, lockTTL time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || idempotencyKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			utils.SendErrorResponse(w, "Idempotency-Key must not be longer than 255 characters", http.StatusBadRequest)
+			return
+		}
+
+		// The body is read up front to fingerprint the request, then handed
+		// on to the handler
+		body, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			tooLarge(w)
+			return
+		}
+		if err != nil {
+			utils.SendErrorResponse(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+		key := ByManager(r) + ":" + idempotencyKey
+		fingerprint := requestFingerprint(r, body)
+
+		token, err := reservationToken()
+		if err != nil {
+			logger.Error("Failed to reserve idempotency key", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		record, err := store.Reserve(ctx, key, idempotency.Record{Fingerprint: fingerprint, Token: token}, lockTTL)
+		if err != nil {
+			logger.Error("Failed to reserve idempotency key", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if record != nil {
+			switch {
+			case record.Fingerprint != fingerprint:
+				utils.SendErrorResponse(w, "Idempotency-Key was already used for another request", http.StatusUnprocessableEntity)
+			case record.Pending():
+				utils.SendErrorResponse(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+			default:
+				replay(w, record.Response)
+			}
+			return
+		}
+
+		// The key is released unless the response is kept, also when the
+		// handler panics, so the request can be retried. Once the
+		// reservation expired, the key may be reserved by a retry, whose
+		// record is left alone.
+		kept := false
+		defer func() {
+			if kept {
+				return
+			}
+			if err := store.Release(context.WithoutCancel(ctx), key, token); err != nil {
+				logger.Error("Failed to release idempotency key", "error", err)
+			}
+		}()
+
+		recorder := &idempotencyRecorder{ResponseWriter: w, before: w.Header().Clone(), status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		if recorder.status >= http.StatusInternalServerError {
+			return
+		}
+
+		response := &idempotency.Response{Status: recorder.status, Header: recorder.header, Body: recorder.body.Bytes()}
+		err = store.Complete(context.WithoutCancel(ctx), key, idempotency.Record{Fingerprint: fingerprint, Token: token, Response: response}, ttl)
+		if errors.Is(err, idempotency.ErrNotReserved) {
+			logger.Warn("Idempotency key reservation expired before the response, raise the lock timeout", "lock_timeout", lockTTL)
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to keep idempotent response", "error", err)
+			return
+		}
+		kept = true
+	})
+}
+
+// reservationToken returns a random token telling apart the reservations of
+// a key
+func reservationToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("generate reservation token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// requestFingerprint tells apart the requests sent with the same key
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replay writes a kept response. Headers set by the outer middleware, e.g.
+// X-Request-ID, are those of the retry.
+func replay(w http.ResponseWriter, response *idempotency.Response) {
+	header := w.Header()
+	for name, values := range response.Header {
+		header[name] = values
+	}
+	header.Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
+
+// idempotencyRecorder records the response of the handler, with the headers
+// it set itself
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	before      http.Header
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.header = http.Header{}
+		for name, values := range r.ResponseWriter.Header() {
+			if _, ok := r.before[name]; !ok {
+				r.header[name] = values
+			}
+		}
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// RecordError passes the error on to the access log, see utils.ErrorRecorder
+func (r *idempotencyRecorder) RecordError(err error) {
+	if recorder, ok := r.ResponseWriter.(utils.ErrorRecorder); ok {
+		recorder.RecordError(err)
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,179 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/idempotency"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+func idempotentRequest(method string, managerID int, key string, body string) *http.Request {
+	req := httptest.NewRequest(method, "/v1/employee", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), constants.JWTKey, &utils.Claims{ID: managerID}))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	return req
+}
+
+func TestIdempotencyMiddleware_ReplaysRetries(t *testing.T) {
+	created := 0
+	handler := middleware.IdempotencyMiddleware(idempotency.NewMemoryStore(), time.Hour, time.Minute,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			created++
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Location", "/v1/employee/"+strconv.Itoa(created))
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}))
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		res.Header().Set("X-Request-ID", "retry")
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	res := serve(idempotentRequest(http.MethodPost, 1, "abc", `{"name":"Alice"}`))
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, `{"name":"Alice"}`, res.Body.String())
+	assert.Empty(t, res.Header().Get("Idempotent-Replayed"))
+
+	// A retry after a timeout gets the first response, without creating twice
+	res = serve(idempotentRequest(http.MethodPost, 1, "abc", `{"name":"Alice"}`))
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, `{"name":"Alice"}`, res.Body.String())
+	assert.Equal(t, "/v1/employee/1", res.Header().Get("Location"))
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	assert.Equal(t, "retry", res.Header().Get("X-Request-ID"))
+	assert.Equal(t, "true", res.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, created)
+
+	// The key can't be reused for another payload
+	res = serve(idempotentRequest(http.MethodPost, 1, "abc", `{"name":"Bob"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	assert.Equal(t, 1, created)
+
+	// Keys are per manager
+	res = serve(idempotentRequest(http.MethodPost, 2, "abc", `{"name":"Alice"}`))
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, 2, created)
+
+	// Requests without a key, and other methods, always run
+	serve(idempotentRequest(http.MethodPost, 1, "", `{"name":"Alice"}`))
+	serve(idempotentRequest(http.MethodPatch, 1, "abc", `{"name":"Alice"}`))
+	assert.Equal(t, 4, created)
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	var handler http.Handler
+	handler = middleware.IdempotencyMiddleware(store, time.Hour, time.Minute,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The client retries while the first request is still served
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+			assert.Equal(t, http.StatusConflict, res.Code)
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+	assert.Equal(t, http.StatusCreated, res.Code)
+}
+
+func TestIdempotencyMiddleware_ServerErrorsAreRetried(t *testing.T) {
+	calls := 0
+	handler := middleware.IdempotencyMiddleware(idempotency.NewMemoryStore(), time.Hour, time.Minute,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				utils.WriteError(w, errors.New("connection reset"))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_SlowHandler(t *testing.T) {
+	for name, firstStatus := range map[string]int{"created": http.StatusCreated, "server error": http.StatusInternalServerError} {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			var handler http.Handler
+			handler = middleware.IdempotencyMiddleware(idempotency.NewMemoryStore(), time.Hour, 10*time.Millisecond,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					calls++
+					call := calls
+					if call == 1 {
+						// The first request outlives its reservation, and the
+						// client retries meanwhile
+						time.Sleep(20 * time.Millisecond)
+						res := httptest.NewRecorder()
+						handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+						assert.Equal(t, http.StatusCreated, res.Code)
+						assert.Equal(t, "2", res.Body.String())
+						w.WriteHeader(firstStatus)
+						w.Write([]byte(strconv.Itoa(call)))
+						return
+					}
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(strconv.Itoa(call)))
+				}))
+
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+			assert.Equal(t, firstStatus, res.Code)
+
+			// The response of the retry is kept, the first request neither
+			// overwrites nor releases it
+			res = httptest.NewRecorder()
+			handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+			assert.Equal(t, http.StatusCreated, res.Code)
+			assert.Equal(t, "2", res.Body.String())
+			assert.Equal(t, "true", res.Header().Get("Idempotent-Replayed"))
+			assert.Equal(t, 2, calls)
+		})
+	}
+}
+
+// failingIdempotencyStore fails every reservation
+type failingIdempotencyStore struct {
+	idempotency.Store
+}
+
+func (failingIdempotencyStore) Reserve(context.Context, string, idempotency.Record, time.Duration) (*idempotency.Record, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func TestIdempotencyMiddleware_LetsThroughWhenStoreFails(t *testing.T) {
+	handler := middleware.IdempotencyMiddleware(failingIdempotencyStore{}, time.Hour, time.Minute,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, idempotentRequest(http.MethodPost, 1, "abc", `{}`))
+	assert.Equal(t, http.StatusCreated, res.Code)
+}
@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// logLines decodes the JSON log lines written to buf
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestLoggerMiddleware_CorrelatesLogLines(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := middleware.RequestIDMiddleware(middleware.LoggerMiddleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.Annotate(r.Context(), "manager_id", 7)
+		logging.FromContext(r.Context()).Info("Handling request")
+		w.WriteHeader(http.StatusCreated)
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/employee", nil)
+	req.Header.Set(constants.RequestIDHeader, "request-1")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Equal(t, "request-1", line["request_id"])
+		assert.Equal(t, float64(7), line["manager_id"])
+	}
+
+	access := lines[1]
+	assert.Equal(t, "request served", access["msg"])
+	assert.Equal(t, "INFO", access["level"])
+	assert.Equal(t, http.MethodPost, access["method"])
+	assert.Equal(t, "/v1/employee", access["path"])
+	assert.Equal(t, float64(http.StatusCreated), access["status"])
+	assert.Contains(t, access, "latency_ms")
+}
+
+func TestLoggerMiddleware_LogsInternalErrors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := middleware.RequestIDMiddleware(middleware.LoggerMiddleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteError(w, errors.New("connection refused"))
+	})))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/v1/employee", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.NotContains(t, res.Body.String(), "connection refused")
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "ERROR", lines[0]["level"])
+	assert.Equal(t, "connection refused", lines[0]["error"])
+	assert.Equal(t, res.Header().Get(constants.RequestIDHeader), lines[0]["request_id"])
+}
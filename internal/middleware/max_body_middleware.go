@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// MaxBodyMiddleware answers 413 to requests with a body larger than limit
+// bytes. Bodies of unknown length are read up front, up to limit, so the
+// handlers never see a truncated body. A limit of zero or less disables it.
+func MaxBodyMiddleware(limit int64, next http.Handler) http.Handler {
+	if limit <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			tooLarge(w)
+			return
+		}
+
+		// Like http.MaxBytesHandler, leave the request of the caller untouched
+		limited := new(http.Request)
+		*limited = *r
+
+		if r.ContentLength >= 0 {
+			limited.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, limited)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			tooLarge(w)
+			return
+		}
+		if err != nil {
+			utils.SendErrorResponse(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		limited.Body = io.NopCloser(bytes.NewReader(body))
+		limited.ContentLength = int64(len(body))
+		next.ServeHTTP(w, limited)
+	})
+}
+
+func tooLarge(w http.ResponseWriter) {
+	utils.SendErrorResponse(w, "Request body too large", http.StatusRequestEntityTooLarge)
+}
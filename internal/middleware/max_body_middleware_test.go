@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+func TestMaxBodyMiddleware(t *testing.T) {
+	handler := middleware.MaxBodyMiddleware(8, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Write(body) //nolint:errcheck
+	}))
+
+	tests := []struct {
+		name          string
+		body          string
+		unknownLength bool
+		status        int
+	}{
+		{name: "small", body: "small", status: http.StatusOK},
+		{name: "large", body: "larger than eight bytes", status: http.StatusRequestEntityTooLarge},
+		{name: "small of unknown length", body: "small", unknownLength: true, status: http.StatusOK},
+		{name: "large of unknown length", body: "larger than eight bytes", unknownLength: true, status: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/employee", strings.NewReader(tt.body))
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+
+			assert.Equal(t, tt.status, res.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.body, res.Body.String())
+				return
+			}
+
+			var body utils.ErrorResponse
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+			assert.Equal(t, "Request body too large", body.Message)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/metrics"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+)
+
+func TestMetricsMiddleware_LabelsByRoutePattern(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /v1/metrics-test/{identityNumber}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := middleware.MetricsMiddleware(mux)
+
+	matched := metrics.HTTPRequests.WithLabelValues("PATCH /v1/metrics-test/{identityNumber}", http.MethodPatch, "202")
+	unmatched := metrics.HTTPRequests.WithLabelValues(metrics.UnmatchedRoute, http.MethodGet, "404")
+	matchedBefore := testutil.ToFloat64(matched)
+	unmatchedBefore := testutil.ToFloat64(unmatched)
+
+	for _, identityNumber := range []string{"12345", "67890"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPatch, "/v1/metrics-test/"+identityNumber, nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/unknown", nil))
+
+	assert.Equal(t, matchedBefore+2, testutil.ToFloat64(matched))
+	assert.Equal(t, unmatchedBefore+1, testutil.ToFloat64(unmatched))
+}
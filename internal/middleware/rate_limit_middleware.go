@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/ratelimit"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// RateLimitKey returns the key of the bucket a request takes its token from
+type RateLimitKey func(r *http.Request) string
+
+// ByManager keys the buckets by the manager of the JWT claims, so it must run
+// inside AuthMiddleware. Requests without claims share one bucket.
+func ByManager(r *http.Request) string {
+	claims, ok := r.Context().Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		return "manager:unknown"
+	}
+	return "manager:" + strconv.Itoa(claims.ID)
+}
+
+// ByClientIP keys the buckets by the address of the client. Behind a proxy,
+// trustForwardedFor takes it from the first X-Forwarded-For entry instead,
+// which clients can forge when not behind one.
+func ByClientIP(trustForwardedFor bool) RateLimitKey {
+	return func(r *http.Request) string {
+		if trustForwardedFor {
+			if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+				client, _, _ := strings.Cut(forwarded, ",")
+				return "ip:" + strings.TrimSpace(client)
+			}
+		}
+
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		return "ip:" + host
+	}
+}
+
+// RateLimitMiddleware takes a token per request from the bucket of key in
+// store, answering 429 with Retry-After when it is empty. Every response
+// carries the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers. Requests are let through when the store fails.
+func RateLimitMiddleware(store ratelimit.Store, name string, limit ratelimit.Limit, key RateLimitKey, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := store.Take(r.Context(), name+":"+key(r), limit)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Failed to rate limit request", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+			utils.SendErrorResponse(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/ratelimit"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	handler := middleware.RateLimitMiddleware(ratelimit.NewMemoryStore(), "api", ratelimit.Limit{Rate: 0.5, Burst: 2},
+		middleware.ByManager, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	request := func(managerID int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/employee", nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.JWTKey, &utils.Claims{ID: managerID}))
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	res := request(1)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "2", res.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", res.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", res.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, res.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, request(1).Code)
+
+	res = request(1)
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.Equal(t, "0", res.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", res.Header().Get("Retry-After"))
+	assert.Contains(t, res.Body.String(), "Too many requests")
+
+	// Another manager has a bucket of its own
+	assert.Equal(t, http.StatusOK, request(2).Code)
+}
+
+func TestByClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/auth", nil)
+	req.RemoteAddr = "10.0.0.1:5123"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	assert.Equal(t, "ip:10.0.0.1", middleware.ByClientIP(false)(req))
+	assert.Equal(t, "ip:203.0.113.7", middleware.ByClientIP(true)(req))
+}
+
+// failingStore fails every take
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func TestRateLimitMiddleware_LetsThroughWhenStoreFails(t *testing.T) {
+	handler := middleware.RateLimitMiddleware(failingStore{}, "auth", ratelimit.Limit{Rate: 1, Burst: 1},
+		middleware.ByClientIP(false), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/v1/auth", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+)
+
+// maxRequestIDLength bounds the request ids accepted from clients
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware tags every request with an id, reusing the one sent by
+// the client when present. The id is echoed in the response header, where
+// error responses pick it up, and stored in the request context.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(constants.RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(constants.RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), constants.RequestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,32 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	handler := middleware.SecurityHeadersMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/docs/" {
+			w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		}
+	}))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/v1/employee", nil))
+	assert.Equal(t, "nosniff", res.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", res.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", res.Header().Get("Referrer-Policy"))
+	assert.NotEmpty(t, res.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, middleware.ContentSecurityPolicy, res.Header().Get("Content-Security-Policy"))
+
+	// Pages override the policy
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+	assert.Equal(t, "default-src 'self'", res.Header().Get("Content-Security-Policy"))
+}
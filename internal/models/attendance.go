@@ -0,0 +1,239 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+var (
+	ErrAlreadyCheckedIn = utils.NewError(utils.ResourceConflict, "ALREADY_CHECKED_IN", "employee is already checked in")
+	ErrNotCheckedIn     = utils.NewError(utils.ResourceConflict, "NOT_CHECKED_IN", "employee is not checked in")
+)
+
+// ScheduleTimeLayout is the layout of the start and end time of a WorkSchedule
+const ScheduleTimeLayout = "15:04"
+
+// WorkSchedule is the working schedule of a department, used to detect late
+// and absent employees. Times are interpreted in the schedule's timezone.
+type WorkSchedule struct {
+	DepartmentID int            `json:"departmentId"`
+	StartTime    string         `json:"startTime" validate:"required"`
+	EndTime      string         `json:"endTime" validate:"required"`
+	GraceMinutes int            `json:"graceMinutes" validate:"min=0,max=240"`
+	WorkDays     []time.Weekday `json:"workDays" validate:"required,min=1,max=7"`
+	Timezone     string         `json:"timezone" validate:"required"`
+}
+
+// DefaultWorkSchedule is used for departments without a configured schedule
+func DefaultWorkSchedule(departmentID int) WorkSchedule {
+	return WorkSchedule{
+		DepartmentID: departmentID,
+		StartTime:    "09:00",
+		EndTime:      "17:00",
+		GraceMinutes: 15,
+		WorkDays:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Timezone:     "UTC",
+	}
+}
+
+func (s WorkSchedule) Validate() error {
+	start, err := time.Parse(ScheduleTimeLayout, s.StartTime)
+	if err != nil {
+		return fmt.Errorf("startTime must be formatted as HH:MM")
+	}
+
+	end, err := time.Parse(ScheduleTimeLayout, s.EndTime)
+	if err != nil {
+		return fmt.Errorf("endTime must be formatted as HH:MM")
+	}
+
+	if !end.After(start) {
+		return fmt.Errorf("endTime must be after startTime")
+	}
+
+	if s.GraceMinutes < 0 || s.GraceMinutes > 240 {
+		return fmt.Errorf("graceMinutes must be between 0 and 240")
+	}
+
+	if len(s.WorkDays) == 0 {
+		return fmt.Errorf("workDays must not be empty")
+	}
+	for _, day := range s.WorkDays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("workDays must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+
+	return nil
+}
+
+// Location returns the timezone of the schedule, falling back to UTC
+func (s WorkSchedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func (s WorkSchedule) IsWorkDay(day time.Weekday) bool {
+	for _, workDay := range s.WorkDays {
+		if workDay == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Start returns the moment the work day starts on the given date
+func (s WorkSchedule) Start(day Date) time.Time {
+	start, _ := time.Parse(ScheduleTimeLayout, s.StartTime)
+	return time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, s.Location())
+}
+
+// LocalDate returns the date of t in the schedule's timezone
+func (s WorkSchedule) LocalDate(t time.Time) Date {
+	return NewDate(t.In(s.Location()))
+}
+
+type AttendanceRecord struct {
+	ID             int        `json:"id" db:"id"`
+	EmployeeID     int        `json:"-" db:"employee_id"`
+	IdentityNumber string     `json:"identityNumber" db:"identity_number"`
+	CheckIn        time.Time  `json:"checkIn" db:"check_in"`
+	CheckOut       *time.Time `json:"checkOut,omitempty" db:"check_out"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// AttendanceRequest checks an employee in or out, at the current time when
+// no timestamp is given.
+type AttendanceRequest struct {
+	IdentityNumber string     `json:"identityNumber" validate:"required,min=5,max=33"`
+	Timestamp      *time.Time `json:"timestamp,omitempty"`
+}
+
+type AttendanceFilterOptions struct {
+	IdentityNumber *string
+	DepartmentID   *int
+	From           time.Time
+	To             time.Time
+}
+
+type AttendanceStatus string
+
+const (
+	AttendancePresent   AttendanceStatus = "present"
+	AttendanceLate      AttendanceStatus = "late"
+	AttendanceAbsent    AttendanceStatus = "absent"
+	AttendanceOnLeave   AttendanceStatus = "on_leave"
+	AttendanceDayOff    AttendanceStatus = "day_off"
+	AttendanceScheduled AttendanceStatus = "scheduled"
+)
+
+type DailyAttendance struct {
+	IdentityNumber string           `json:"identityNumber"`
+	Name           string           `json:"name"`
+	DepartmentID   int              `json:"departmentId"`
+	Date           Date             `json:"date"`
+	Status         AttendanceStatus `json:"status"`
+	FirstCheckIn   *time.Time       `json:"firstCheckIn,omitempty"`
+	LastCheckOut   *time.Time       `json:"lastCheckOut,omitempty"`
+	WorkedMinutes  int              `json:"workedMinutes"`
+	LateMinutes    int              `json:"lateMinutes"`
+}
+
+// Summarize derives the attendance of a single day from the records checked
+// in on that day. now decides whether an employee without records is absent
+// or simply not due yet.
+func (s WorkSchedule) Summarize(day Date, records []AttendanceRecord, onLeave bool, now time.Time) DailyAttendance {
+	summary := DailyAttendance{
+		DepartmentID: s.DepartmentID,
+		Date:         day,
+	}
+
+	for _, record := range records {
+		checkIn := record.CheckIn
+		if summary.FirstCheckIn == nil || checkIn.Before(*summary.FirstCheckIn) {
+			summary.FirstCheckIn = &checkIn
+		}
+		if record.CheckOut != nil {
+			checkOut := *record.CheckOut
+			if summary.LastCheckOut == nil || checkOut.After(*summary.LastCheckOut) {
+				summary.LastCheckOut = &checkOut
+			}
+			summary.WorkedMinutes += int(checkOut.Sub(checkIn).Minutes())
+		}
+	}
+
+	start := s.Start(day)
+	deadline := start.Add(time.Duration(s.GraceMinutes) * time.Minute)
+
+	switch {
+	case summary.FirstCheckIn != nil && !s.IsWorkDay(day.Weekday()):
+		summary.Status = AttendancePresent
+	case summary.FirstCheckIn != nil && summary.FirstCheckIn.After(deadline):
+		summary.Status = AttendanceLate
+		summary.LateMinutes = int(summary.FirstCheckIn.Sub(start).Minutes())
+	case summary.FirstCheckIn != nil:
+		summary.Status = AttendancePresent
+	case !s.IsWorkDay(day.Weekday()):
+		summary.Status = AttendanceDayOff
+	case onLeave:
+		summary.Status = AttendanceOnLeave
+	case now.Before(deadline):
+		summary.Status = AttendanceScheduled
+	default:
+		summary.Status = AttendanceAbsent
+	}
+
+	return summary
+}
+
+// AttendanceSummary aggregates the daily attendance of one employee over a
+// period. Present includes the days the employee was late.
+type AttendanceSummary struct {
+	IdentityNumber string `json:"identityNumber"`
+	Name           string `json:"name"`
+	DepartmentID   int    `json:"departmentId"`
+	WorkingDays    int    `json:"workingDays"`
+	Present        int    `json:"present"`
+	Late           int    `json:"late"`
+	Absent         int    `json:"absent"`
+	OnLeave        int    `json:"onLeave"`
+	WorkedMinutes  int    `json:"workedMinutes"`
+	LateMinutes    int    `json:"lateMinutes"`
+}
+
+func (s *AttendanceSummary) Add(day DailyAttendance) {
+	switch day.Status {
+	case AttendancePresent:
+		s.Present++
+	case AttendanceLate:
+		s.Present++
+		s.Late++
+	case AttendanceAbsent:
+		s.Absent++
+	case AttendanceOnLeave:
+		s.OnLeave++
+	}
+
+	if day.Status != AttendanceDayOff && day.Status != AttendanceScheduled {
+		s.WorkingDays++
+	}
+	s.WorkedMinutes += day.WorkedMinutes
+	s.LateMinutes += day.LateMinutes
+}
+
+type AttendanceReport struct {
+	Month     string              `json:"month"`
+	From      Date                `json:"from"`
+	To        Date                `json:"to"`
+	Employees []AttendanceSummary `json:"employees"`
+}
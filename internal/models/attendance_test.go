@@ -0,0 +1,99 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+func TestWorkSchedule_Validate(t *testing.T) {
+	assert.NoError(t, models.DefaultWorkSchedule(1).Validate())
+
+	invalid := []func(s *models.WorkSchedule){
+		func(s *models.WorkSchedule) { s.StartTime = "9am" },
+		func(s *models.WorkSchedule) { s.EndTime = "08:00" },
+		func(s *models.WorkSchedule) { s.GraceMinutes = -1 },
+		func(s *models.WorkSchedule) { s.WorkDays = nil },
+		func(s *models.WorkSchedule) { s.WorkDays = []time.Weekday{7} },
+		func(s *models.WorkSchedule) { s.Timezone = "Mars/Olympus" },
+	}
+	for _, mutate := range invalid {
+		schedule := models.DefaultWorkSchedule(1)
+		mutate(&schedule)
+		assert.Error(t, schedule.Validate())
+	}
+}
+
+func TestWorkSchedule_Summarize(t *testing.T) {
+	schedule := models.DefaultWorkSchedule(1)
+	schedule.Timezone = "Asia/Jakarta"
+	loc := schedule.Location()
+
+	monday := mustDate(t, "2025-03-03")
+	saturday := mustDate(t, "2025-03-08")
+	at := func(day models.Date, hour, minute int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+	}
+	record := func(in, out time.Time) models.AttendanceRecord {
+		return models.AttendanceRecord{CheckIn: in, CheckOut: &out}
+	}
+	later := at(monday, 23, 0)
+
+	t.Run("present within grace period", func(t *testing.T) {
+		summary := schedule.Summarize(monday, []models.AttendanceRecord{record(at(monday, 9, 10), at(monday, 17, 10))}, false, later)
+		assert.Equal(t, models.AttendancePresent, summary.Status)
+		assert.Equal(t, 480, summary.WorkedMinutes)
+		assert.Equal(t, 0, summary.LateMinutes)
+	})
+
+	t.Run("late after grace period", func(t *testing.T) {
+		summary := schedule.Summarize(monday, []models.AttendanceRecord{
+			record(at(monday, 9, 30), at(monday, 12, 0)),
+			record(at(monday, 13, 0), at(monday, 17, 0)),
+		}, false, later)
+		assert.Equal(t, models.AttendanceLate, summary.Status)
+		assert.Equal(t, 30, summary.LateMinutes)
+		assert.Equal(t, 390, summary.WorkedMinutes)
+		assert.Equal(t, at(monday, 17, 0), *summary.LastCheckOut)
+	})
+
+	t.Run("open record is not counted as worked", func(t *testing.T) {
+		summary := schedule.Summarize(monday, []models.AttendanceRecord{{CheckIn: at(monday, 8, 55)}}, false, later)
+		assert.Equal(t, models.AttendancePresent, summary.Status)
+		assert.Nil(t, summary.LastCheckOut)
+		assert.Equal(t, 0, summary.WorkedMinutes)
+	})
+
+	t.Run("absent, on leave or not due yet", func(t *testing.T) {
+		assert.Equal(t, models.AttendanceAbsent, schedule.Summarize(monday, nil, false, later).Status)
+		assert.Equal(t, models.AttendanceOnLeave, schedule.Summarize(monday, nil, true, later).Status)
+		assert.Equal(t, models.AttendanceScheduled, schedule.Summarize(monday, nil, false, at(monday, 9, 5)).Status)
+	})
+
+	t.Run("day off", func(t *testing.T) {
+		assert.Equal(t, models.AttendanceDayOff, schedule.Summarize(saturday, nil, false, later).Status)
+		summary := schedule.Summarize(saturday, []models.AttendanceRecord{record(at(saturday, 11, 0), at(saturday, 12, 0))}, false, later)
+		assert.Equal(t, models.AttendancePresent, summary.Status)
+		assert.Equal(t, 0, summary.LateMinutes)
+	})
+}
+
+func TestAttendanceSummary_Add(t *testing.T) {
+	var summary models.AttendanceSummary
+	for _, status := range []models.AttendanceStatus{
+		models.AttendancePresent, models.AttendanceLate, models.AttendanceAbsent,
+		models.AttendanceOnLeave, models.AttendanceDayOff, models.AttendanceScheduled,
+	} {
+		summary.Add(models.DailyAttendance{Status: status, WorkedMinutes: 60})
+	}
+
+	assert.Equal(t, 4, summary.WorkingDays)
+	assert.Equal(t, 2, summary.Present)
+	assert.Equal(t, 1, summary.Late)
+	assert.Equal(t, 1, summary.Absent)
+	assert.Equal(t, 1, summary.OnLeave)
+	assert.Equal(t, 360, summary.WorkedMinutes)
+}
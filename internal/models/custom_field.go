@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldString  CustomFieldType = "string"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldBoolean CustomFieldType = "boolean"
+	CustomFieldDate    CustomFieldType = "date"
+	CustomFieldEnum    CustomFieldType = "enum"
+)
+
+// CustomFieldDateLayout is the only accepted format for date custom fields.
+const CustomFieldDateLayout = "2006-01-02"
+
+func (t CustomFieldType) Valid() bool {
+	switch t {
+	case CustomFieldString, CustomFieldNumber, CustomFieldBoolean, CustomFieldDate, CustomFieldEnum:
+		return true
+	}
+	return false
+}
+
+// CustomField is a manager-defined attribute that can be attached to
+// every employee in the manager's departments.
+type CustomField struct {
+	ID            int             `json:"id" db:"id"`
+	ManagerID     int             `json:"-" db:"manager_id"`
+	Key           string          `json:"key" db:"key"`
+	Label         string          `json:"label" db:"label"`
+	Type          CustomFieldType `json:"type" db:"type"`
+	Required      bool            `json:"required" db:"required"`
+	AllowedValues StringList      `json:"allowedValues,omitempty" db:"allowed_values"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+type CreateCustomFieldRequest struct {
+	Key           string          `json:"key" validate:"required,min=1,max=33"`
+	Label         string          `json:"label" validate:"required,min=1,max=52"`
+	Type          CustomFieldType `json:"type" validate:"required,oneof=string number boolean date enum"`
+	Required      bool            `json:"required"`
+	AllowedValues []string        `json:"allowedValues,omitempty"`
+}
+
+// CustomFieldValues holds the custom field values of an employee and is
+// stored as JSONB.
+type CustomFieldValues map[string]interface{}
+
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(v)
+}
+
+func (v *CustomFieldValues) Scan(src interface{}) error {
+	return scanJSON(src, v)
+}
+
+// StringList is a list of strings stored as a JSONB array.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *StringList) Scan(src interface{}) error {
+	return scanJSON(src, l)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	default:
+		return errors.New("unsupported type for JSON column")
+	}
+}
@@ -16,6 +16,14 @@ type CreateDepartmentRequest struct {
 
 type UpdateDepartmentRequest struct {
     Name string `json:"name" validate:"required,min=4,max=33"`
+    // IfUpdatedAt fails the update with ErrPreconditionFailed unless the
+    // department was last updated at it, see the If-Match header
+    IfUpdatedAt *time.Time `json:"-"`
+}
+
+type MergeDepartmentsRequest struct {
+    SourceDepartmentIds []int `json:"sourceDepartmentIds" validate:"required,min=1"`
+    TargetDepartmentId  int   `json:"targetDepartmentId" validate:"required"`
 }
 
 type DepartmentResponse struct {
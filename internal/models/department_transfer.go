@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+var (
+	ErrTransferPending    = utils.NewError(utils.ResourceConflict, "TRANSFER_PENDING", "department already has a pending transfer")
+	ErrTransferNotPending = utils.NewError(utils.ResourceConflict, "TRANSFER_NOT_PENDING", "department transfer is no longer pending")
+)
+
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "pending"
+	TransferAccepted  TransferStatus = "accepted"
+	TransferRejected  TransferStatus = "rejected"
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+// DepartmentTransfer hands a department, with its employees, over to another
+// manager. The current owner proposes it and it only takes effect once the
+// receiving manager accepts.
+type DepartmentTransfer struct {
+	ID               int            `json:"transfer_id"`
+	DepartmentID     int            `json:"department_id"`
+	DepartmentName   string         `json:"department_name"`
+	FromManagerID    int            `json:"-"`
+	FromManagerEmail string         `json:"from_manager_email"`
+	ToManagerID      int            `json:"-"`
+	ToManagerEmail   string         `json:"to_manager_email"`
+	Status           TransferStatus `json:"status"`
+	CreatedAt        time.Time      `json:"created_at"`
+	DecidedAt        *time.Time     `json:"decided_at,omitempty"`
+}
+
+type CreateDepartmentTransferRequest struct {
+	DepartmentID   int    `json:"department_id" validate:"required"`
+	ToManagerEmail string `json:"to_manager_email" validate:"required,email"`
+}
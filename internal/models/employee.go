@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
 type Gender string
@@ -12,37 +14,95 @@ const (
 )
 
 type Employee struct {
-	ID               int        `json:"id" db:"id"`
-	IdentityNumber   string     `json:"identityNumber" db:"identity_number"`
-	Name             string     `json:"name" db:"name"`
-	EmployeeImageURI string     `json:"employeeImageUri" db:"employee_image_uri"`
-	Gender           Gender     `json:"gender" db:"gender"`
-	DepartmentID     int        `json:"departmentId" db:"department_id"`
-	CreatedAt        time.Time  `json:"createdAt" db:"created_at"`
-	UpdatedAt        time.Time  `json:"updatedAt" db:"updated_at"`
-	DeletedAt        *time.Time `json:"deletedAt,omitempty" db:"deleted_at"`
+	ID                int               `json:"id" db:"id"`
+	IdentityNumber    string            `json:"identityNumber" db:"identity_number"`
+	Name              string            `json:"name" db:"name"`
+	EmployeeImageURI  string            `json:"employeeImageUri" db:"employee_image_uri"`
+	Gender            Gender            `json:"gender" db:"gender"`
+	DepartmentID      int               `json:"departmentId" db:"department_id"`
+	CustomFields      CustomFieldValues `json:"customFields,omitempty" db:"custom_fields"`
+	Status            EmploymentStatus  `json:"status" db:"employment_status"`
+	HireDate          *Date             `json:"hireDate,omitempty" db:"hire_date"`
+	TerminationDate   *Date             `json:"terminationDate,omitempty" db:"termination_date"`
+	TerminationReason *string           `json:"terminationReason,omitempty" db:"termination_reason"`
+	CreatedAt         time.Time         `json:"createdAt" db:"created_at"`
+	UpdatedAt         time.Time         `json:"updatedAt" db:"updated_at"`
+	DeletedAt         *time.Time        `json:"deletedAt,omitempty" db:"deleted_at"`
 }
 
 type CreateEmployeeRequest struct {
-	IdentityNumber   string `json:"identityNumber" validate:"required,min=5,max=33"`
-	Name             string `json:"name" validate:"required,min=4,max=33"`
-	EmployeeImageURI string `json:"employeeImageUri" validate:"required,url"`
-	Gender           Gender `json:"gender" validate:"required,oneof=male female"`
-	DepartmentID     int    `json:"departmentId" validate:"required"`
+	IdentityNumber   string            `json:"identityNumber" validate:"required,min=5,max=33"`
+	Name             string            `json:"name" validate:"required,min=4,max=33"`
+	EmployeeImageURI string            `json:"employeeImageUri" validate:"required,url"`
+	Gender           Gender            `json:"gender" validate:"required,oneof=male female"`
+	DepartmentID     int               `json:"departmentId" validate:"required"`
+	CustomFields     CustomFieldValues `json:"customFields,omitempty"`
+	Status           EmploymentStatus  `json:"status,omitempty" validate:"omitempty,oneof=probation active"`
+	HireDate         *Date             `json:"hireDate,omitempty"`
 }
 
 type UpdateEmployeeRequest struct {
-	IdentityNumber   *string `json:"identityNumber,omitempty" validate:"omitempty,min=5,max=33"`
-	Name             *string `json:"name,omitempty" validate:"omitempty,min=4,max=33"`
-	EmployeeImageURI *string `json:"employeeImageUri,omitempty" validate:"omitempty,url"`
-	Gender           *Gender `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
-	DepartmentID     *int    `json:"departmentId,omitempty" validate:"omitempty"`
+	IdentityNumber   *string           `json:"identityNumber,omitempty" validate:"omitempty,min=5,max=33"`
+	Name             *string           `json:"name,omitempty" validate:"omitempty,min=4,max=33"`
+	EmployeeImageURI *string           `json:"employeeImageUri,omitempty" validate:"omitempty,url"`
+	Gender           *Gender           `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
+	DepartmentID     *int              `json:"departmentId,omitempty" validate:"omitempty"`
+	CustomFields     CustomFieldValues `json:"customFields,omitempty"`
+	// IfUpdatedAt fails the update with ErrPreconditionFailed unless the
+	// employee was last updated at it, see the If-Match header
+	IfUpdatedAt *time.Time `json:"-"`
 }
 
 type FilterOptions struct {
-	IdentityNumber *string `json:"identityNumber,omitempty"`
-	Gender         *Gender `json:"gender,omitempty"`
-	DepartmentID   *int    `json:"departmentId,omitempty"`
-	Limit          int     `json:"limit" validate:"required,min=1" default:"10"`
-	Offset         int     `json:"offset" validate:"min=0" default:"0"`
+	IdentityNumber *string            `json:"identityNumber,omitempty"`
+	Gender         *Gender            `json:"gender,omitempty"`
+	DepartmentID   *int               `json:"departmentId,omitempty"`
+	CustomFields   CustomFieldValues  `json:"customFields,omitempty"`
+	Statuses       []EmploymentStatus `json:"statuses,omitempty"`
+	Limit          int                `json:"limit" validate:"required,min=1" default:"10"`
+	Offset         int                `json:"offset" validate:"min=0" default:"0"`
+	// AfterID lists the employees past it in id order, paging without an
+	// offset, employees added or removed meanwhile don't shift the pages
+	AfterID int `json:"-"`
+}
+
+// MaxBulkEmployees is the most employees a single bulk operation may touch
+const MaxBulkEmployees = 500
+
+var ErrBulkTooLarge = utils.NewError(utils.InvalidInput, "BULK_TOO_LARGE", "bulk operation selects too many employees")
+
+// BulkEmployeeRequest selects the employees of a bulk operation, either by
+// identity number or by filter (limit and offset are ignored).
+type BulkEmployeeRequest struct {
+	IdentityNumbers []string       `json:"identityNumbers,omitempty" validate:"omitempty,max=500,dive,min=5,max=33"`
+	Filter          *FilterOptions `json:"filter,omitempty" validate:"-"`
+}
+
+type BulkMoveRequest struct {
+	BulkEmployeeRequest
+	DepartmentID int `json:"departmentId" validate:"required"`
+}
+
+type BulkItemStatus string
+
+const (
+	BulkItemUpdated  BulkItemStatus = "updated"
+	BulkItemDeleted  BulkItemStatus = "deleted"
+	BulkItemNotFound BulkItemStatus = "not_found"
+	// BulkItemSkipped marks items left untouched because another item failed
+	BulkItemSkipped BulkItemStatus = "skipped"
+)
+
+type BulkItemResult struct {
+	IdentityNumber string         `json:"identityNumber"`
+	Status         BulkItemStatus `json:"status"`
+}
+
+// BulkResult reports the outcome of a bulk operation per employee. Bulk
+// operations are all or nothing, Applied is false when any item failed.
+type BulkResult struct {
+	Applied   bool             `json:"applied"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
 }
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+var ErrInvalidStatusTransition = utils.NewError(utils.ResourceConflict, "INVALID_STATUS_TRANSITION", "invalid employment status transition")
+
+type EmploymentStatus string
+
+const (
+	Probation  EmploymentStatus = "probation"
+	Active     EmploymentStatus = "active"
+	OnLeave    EmploymentStatus = "on_leave"
+	Terminated EmploymentStatus = "terminated"
+)
+
+// employmentTransitions lists the statuses each status may move to.
+// Terminated is final, a rehire is recorded as a new employee.
+var employmentTransitions = map[EmploymentStatus][]EmploymentStatus{
+	Probation: {Active, Terminated},
+	Active:    {OnLeave, Terminated},
+	OnLeave:   {Active, Terminated},
+}
+
+func (s EmploymentStatus) Valid() bool {
+	switch s {
+	case Probation, Active, OnLeave, Terminated:
+		return true
+	}
+	return false
+}
+
+func (s EmploymentStatus) CanTransitionTo(next EmploymentStatus) bool {
+	for _, allowed := range employmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// EmploymentEvent is one entry of an employee's status history.
+type EmploymentEvent struct {
+	ID            int              `json:"id" db:"id"`
+	EmployeeID    int              `json:"-" db:"employee_id"`
+	FromStatus    EmploymentStatus `json:"fromStatus" db:"from_status"`
+	ToStatus      EmploymentStatus `json:"toStatus" db:"to_status"`
+	Reason        *string          `json:"reason,omitempty" db:"reason"`
+	EffectiveDate Date             `json:"effectiveDate" db:"effective_date"`
+	ChangedBy     int              `json:"changedBy" db:"changed_by"`
+	CreatedAt     time.Time        `json:"createdAt" db:"created_at"`
+}
+
+type ChangeStatusRequest struct {
+	Status        EmploymentStatus `json:"status" validate:"required,oneof=probation active on_leave terminated"`
+	Reason        *string          `json:"reason,omitempty" validate:"omitempty,min=1,max=255"`
+	EffectiveDate *Date            `json:"effectiveDate,omitempty"`
+}
@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+func TestEmploymentStatus_CanTransitionTo(t *testing.T) {
+	allowed := map[models.EmploymentStatus][]models.EmploymentStatus{
+		models.Probation: {models.Active, models.Terminated},
+		models.Active:    {models.OnLeave, models.Terminated},
+		models.OnLeave:   {models.Active, models.Terminated},
+	}
+	statuses := []models.EmploymentStatus{models.Probation, models.Active, models.OnLeave, models.Terminated}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			expected := false
+			for _, next := range allowed[from] {
+				if next == to {
+					expected = true
+				}
+			}
+			assert.Equal(t, expected, from.CanTransitionTo(to), "%s -> %s", from, to)
+		}
+	}
+}
+
+func TestEmploymentStatus_Valid(t *testing.T) {
+	assert.True(t, models.OnLeave.Valid())
+	assert.False(t, models.EmploymentStatus("retired").Valid())
+}
+
+func TestDate_JSON(t *testing.T) {
+	var date models.Date
+
+	err := date.UnmarshalJSON([]byte(`"2024-02-29"`))
+	assert.NoError(t, err)
+
+	data, err := date.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `"2024-02-29"`, string(data))
+
+	err = date.UnmarshalJSON([]byte(`"29/02/2024"`))
+	assert.Error(t, err)
+}
@@ -1,8 +1,21 @@
 package models
 
-// type ErrorResponse struct {
-//     Status  int    `json:"status"`
-//     Message string `json:"message"`
-//     Code    string `json:"code,omitempty"`
-//     Details string `json:"details,omitempty"`
-// }
+import "github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+
+// Errors shared by repositories and services. Each carries the code clients
+// receive in the error response.
+var (
+	ErrEmployeeNotFound        = utils.NewError(utils.ResourceNotFound, "EMPLOYEE_NOT_FOUND", "employee not found")
+	ErrDuplicateIdentityNumber = utils.NewError(utils.ResourceConflict, "IDENTITY_NUMBER_TAKEN", "identityNumber is already used")
+	ErrDepartmentNotFound      = utils.NewError(utils.ResourceNotFound, "DEPARTMENT_NOT_FOUND", "department not found")
+	ErrInvalidDepartment       = utils.NewError(utils.InvalidInput, "INVALID_DEPARTMENT", "departmentId is not a valid department")
+	ErrDepartmentHasEmployees  = utils.NewError(utils.ResourceConflict, "DEPARTMENT_HAS_EMPLOYEES", "department has employees")
+	ErrDepartmentNotOwned      = utils.NewError(utils.PermissionDenied, "DEPARTMENT_NOT_OWNED", "department does not belong to this manager")
+	ErrManagerNotFound         = utils.NewError(utils.ResourceNotFound, "MANAGER_NOT_FOUND", "manager not found")
+	ErrCustomFieldNotFound     = utils.NewError(utils.ResourceNotFound, "CUSTOM_FIELD_NOT_FOUND", "custom field not found")
+	ErrDuplicateCustomField    = utils.NewError(utils.ResourceConflict, "CUSTOM_FIELD_EXISTS", "custom field key already exists")
+	ErrLeaveNotFound           = utils.NewError(utils.ResourceNotFound, "LEAVE_REQUEST_NOT_FOUND", "leave request not found")
+	ErrLeaveBalanceNotFound    = utils.NewError(utils.ResourceNotFound, "LEAVE_BALANCE_NOT_FOUND", "leave balance not found")
+	ErrTransferNotFound        = utils.NewError(utils.ResourceNotFound, "TRANSFER_NOT_FOUND", "department transfer not found")
+	ErrSelfTransfer            = utils.NewError(utils.InvalidInput, "INVALID_TRANSFER", "cannot transfer a department to its own manager")
+)
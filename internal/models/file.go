@@ -0,0 +1,10 @@
+package models
+
+// MaxFileBytes is the largest file a manager may upload
+const MaxFileBytes = 100 << 10
+
+// File is an uploaded file, downloaded from URI
+type File struct {
+	FileID string `json:"fileId"`
+	URI    string `json:"uri"`
+}
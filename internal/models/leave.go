@@ -0,0 +1,136 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+var (
+	ErrLeaveOverlap        = utils.NewError(utils.ResourceConflict, "LEAVE_OVERLAP", "leave request overlaps an existing request")
+	ErrInsufficientBalance = utils.NewError(utils.ResourceConflict, "INSUFFICIENT_LEAVE_BALANCE", "insufficient leave balance")
+	ErrLeaveNotPending     = utils.NewError(utils.ResourceConflict, "LEAVE_NOT_PENDING", "leave request is no longer pending")
+)
+
+type LeaveType string
+
+const (
+	AnnualLeave LeaveType = "annual"
+	SickLeave   LeaveType = "sick"
+	UnpaidLeave LeaveType = "unpaid"
+)
+
+// LeaveAllowances is the yearly number of leave days accrued per leave type.
+// Leave types missing from the map are not limited by a balance.
+var LeaveAllowances = map[LeaveType]int{
+	AnnualLeave: 12,
+	SickLeave:   14,
+}
+
+func (t LeaveType) Valid() bool {
+	switch t {
+	case AnnualLeave, SickLeave, UnpaidLeave:
+		return true
+	}
+	return false
+}
+
+type LeaveStatus string
+
+const (
+	LeavePending   LeaveStatus = "pending"
+	LeaveApproved  LeaveStatus = "approved"
+	LeaveRejected  LeaveStatus = "rejected"
+	LeaveCancelled LeaveStatus = "cancelled"
+)
+
+func (s LeaveStatus) Valid() bool {
+	switch s {
+	case LeavePending, LeaveApproved, LeaveRejected, LeaveCancelled:
+		return true
+	}
+	return false
+}
+
+type LeaveRequest struct {
+	ID             int         `json:"id" db:"id"`
+	EmployeeID     int         `json:"-" db:"employee_id"`
+	IdentityNumber string      `json:"identityNumber" db:"identity_number"`
+	Type           LeaveType   `json:"type" db:"leave_type"`
+	StartDate      Date        `json:"startDate" db:"start_date"`
+	EndDate        Date        `json:"endDate" db:"end_date"`
+	Days           int         `json:"days" db:"days"`
+	Reason         *string     `json:"reason,omitempty" db:"reason"`
+	Status         LeaveStatus `json:"status" db:"status"`
+	DecidedBy      *int        `json:"decidedBy,omitempty" db:"decided_by"`
+	DecidedAt      *time.Time  `json:"decidedAt,omitempty" db:"decided_at"`
+	DecisionNote   *string     `json:"decisionNote,omitempty" db:"decision_note"`
+	CreatedAt      time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time   `json:"updatedAt" db:"updated_at"`
+}
+
+type CreateLeaveRequest struct {
+	IdentityNumber string    `json:"identityNumber" validate:"required,min=5,max=33"`
+	Type           LeaveType `json:"type" validate:"required,oneof=annual sick unpaid"`
+	StartDate      Date      `json:"startDate" validate:"required"`
+	EndDate        Date      `json:"endDate" validate:"required"`
+	Reason         *string   `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+type LeaveDecisionRequest struct {
+	Note *string `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+type LeaveFilterOptions struct {
+	IdentityNumber *string
+	Status         *LeaveStatus
+	Limit          int
+	Offset         int
+}
+
+type LeaveBalance struct {
+	IdentityNumber string    `json:"identityNumber"`
+	Year           int       `json:"year"`
+	Type           LeaveType `json:"type"`
+	Allowance      int       `json:"allowance"`
+	Used           int       `json:"used"`
+	Pending        int       `json:"pending"`
+	Remaining      int       `json:"remaining"`
+}
+
+// LeaveCalendarEntry is an approved absence shown on the team calendar.
+type LeaveCalendarEntry struct {
+	IdentityNumber string    `json:"identityNumber"`
+	Name           string    `json:"name"`
+	DepartmentID   int       `json:"departmentId"`
+	Type           LeaveType `json:"type"`
+	StartDate      Date      `json:"startDate"`
+	EndDate        Date      `json:"endDate"`
+}
+
+// LeaveDays counts the working days (Monday to Friday) between start and
+// end, both inclusive.
+func LeaveDays(start, end Date) int {
+	days := 0
+	for day := start.Time; !day.After(end.Time); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days
+}
+
+// LeaveAllowance returns the allowance of a leave type for the given year,
+// prorated by month for employees hired during that year.
+func LeaveAllowance(leaveType LeaveType, year int, hireDate *Date) int {
+	allowance := LeaveAllowances[leaveType]
+	if hireDate == nil || hireDate.Year() < year {
+		return allowance
+	}
+	if hireDate.Year() > year {
+		return 0
+	}
+
+	months := 12 - int(hireDate.Month()) + 1
+	return allowance * months / 12
+}
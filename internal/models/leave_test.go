@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+func mustDate(t *testing.T, s string) models.Date {
+	t.Helper()
+	d, err := models.ParseDate(s)
+	assert.NoError(t, err)
+	return d
+}
+
+func TestLeaveDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		expected int
+	}{
+		{"single weekday", "2025-03-03", "2025-03-03", 1},
+		{"full week", "2025-03-03", "2025-03-09", 5},
+		{"weekend only", "2025-03-08", "2025-03-09", 0},
+		{"across weekend", "2025-03-07", "2025-03-10", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, models.LeaveDays(mustDate(t, tt.start), mustDate(t, tt.end)))
+		})
+	}
+}
+
+func TestLeaveAllowance(t *testing.T) {
+	hiredJuly := mustDate(t, "2025-07-15")
+
+	assert.Equal(t, 12, models.LeaveAllowance(models.AnnualLeave, 2025, nil))
+	assert.Equal(t, 12, models.LeaveAllowance(models.AnnualLeave, 2026, &hiredJuly))
+	assert.Equal(t, 6, models.LeaveAllowance(models.AnnualLeave, 2025, &hiredJuly))
+	assert.Equal(t, 0, models.LeaveAllowance(models.AnnualLeave, 2024, &hiredJuly))
+	assert.Equal(t, 0, models.LeaveAllowance(models.UnpaidLeave, 2025, nil))
+}
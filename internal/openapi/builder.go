@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const bearerAuth = "bearerAuth"
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// Route documents one operation of the API
+type Route struct {
+	Method string
+	// Path is the documented path, e.g. /v1/employee/{identityNumber}, which
+	// with Method is the ServeMux pattern serving the operation. Path
+	// parameters ending in Id are integers, the others strings.
+	Path    string
+	Summary string
+	Tag     string
+	// Public operations don't require a bearer token
+	Public bool
+	Query  []Parameter
+	// Headers are the request headers the operation reads
+	Headers []Parameter
+	// Request and Response are zero values of the body types, nil when the
+	// operation has no body
+	Request  interface{}
+	Response interface{}
+	// Status is the success status, 200 when zero
+	Status int
+	// Envelope wraps Response in the {data, message} envelope of utils.Response
+	Envelope bool
+	// ContentType of the response, application/json when empty
+	ContentType string
+}
+
+// Builder generates a Document from the routes of the API
+type Builder struct {
+	Info Info
+	// ErrorBody is the body of every error response
+	ErrorBody interface{}
+	// Types overrides the schema of types with custom JSON encodings
+	Types map[reflect.Type]Schema
+}
+
+func (b Builder) Build(routes []Route) *Document {
+	g := newSchemaGenerator(b.Types)
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    b.Info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	var errorSchema *Schema
+	if b.ErrorBody != nil {
+		errorSchema = g.schemaFor(reflect.TypeOf(b.ErrorBody))
+	}
+
+	for _, route := range routes {
+		op := &Operation{
+			Summary:     route.Summary,
+			OperationID: operationID(route),
+			Parameters:  append(append(pathParameters(route.Path), route.Query...), route.Headers...),
+			Responses:   make(map[string]Response),
+		}
+		if route.Tag != "" {
+			op.Tags = []string{route.Tag}
+		}
+		if !route.Public {
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+		}
+
+		if route.Request != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(g.schemaFor(reflect.TypeOf(route.Request))),
+			}
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		op.Responses[strconv.Itoa(status)] = b.successResponse(g, route, status)
+
+		if errorSchema != nil {
+			op.Responses["default"] = Response{
+				Description: "Error",
+				Content:     jsonContent(errorSchema),
+			}
+		}
+
+		item, ok := doc.Paths[route.Path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+func (b Builder) successResponse(g *schemaGenerator, route Route, status int) Response {
+	response := Response{Description: http.StatusText(status)}
+
+	if route.ContentType != "" {
+		response.Content = map[string]MediaType{route.ContentType: {Schema: &Schema{Type: "string"}}}
+		return response
+	}
+
+	var schema *Schema
+	if route.Response != nil {
+		schema = g.schemaFor(reflect.TypeOf(route.Response))
+	}
+
+	if route.Envelope {
+		envelope := &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{"message": {Type: "string"}},
+		}
+		if schema != nil {
+			envelope.Properties["data"] = schema
+		}
+		schema = envelope
+	}
+
+	if schema != nil {
+		response.Content = jsonContent(schema)
+	}
+	return response
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		schema := &Schema{Type: "string"}
+		if strings.HasSuffix(match[1], "Id") || match[1] == "id" {
+			schema = &Schema{Type: "integer", Format: "int32"}
+		}
+		params = append(params, Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+// operationID derives an id such as patchV1EmployeeIdentityNumber
+func operationID(route Route) string {
+	id := strings.ToLower(route.Method)
+	for _, part := range strings.FieldsFunc(route.Path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-'
+	}) {
+		id += upperFirst(part)
+	}
+	return id
+}
+
+// Pattern is the ServeMux pattern serving the route, e.g. GET /v1/employee
+func (r Route) Pattern() string {
+	return r.Method + " " + r.Path
+}
@@ -0,0 +1,61 @@
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+//go:embed swagger-ui/index.html
+var swaggerUI embed.FS
+
+var swaggerUITemplate = template.Must(template.ParseFS(swaggerUI, "swagger-ui/index.html"))
+
+// Handler serves the document as JSON. The document is encoded once.
+func Handler(doc *Document) http.Handler {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		log.Fatalf("Failed to encode OpenAPI document: %v", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			utils.SendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body) //nolint:errcheck
+	})
+}
+
+// swaggerUIPolicy lets the Swagger UI page load its assets from unpkg.com,
+// run its inline setup script and fetch the OpenAPI document
+const swaggerUIPolicy = "default-src 'none'; script-src https://unpkg.com 'unsafe-inline'; " +
+	"style-src https://unpkg.com 'unsafe-inline'; img-src https: data:; connect-src 'self'; frame-ancestors 'none'"
+
+// SwaggerUIHandler serves a Swagger UI page rendering the document at specURL
+func SwaggerUIHandler(specURL string) http.Handler {
+	var page bytes.Buffer
+	if err := swaggerUITemplate.Execute(&page, struct{ SpecURL string }{specURL}); err != nil {
+		log.Fatalf("Failed to render Swagger UI: %v", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			utils.SendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", swaggerUIPolicy)
+		w.Write(page.Bytes()) //nolint:errcheck
+	})
+}
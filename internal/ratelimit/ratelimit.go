@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is a token bucket holding up to Burst tokens, refilled at Rate
+// tokens per second. Every request takes a token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result tells whether a request is allowed and how the bucket stands
+// after it
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is when the next request is allowed, zero when allowed
+	RetryAfter time.Duration
+	// Reset is when the bucket is full again
+	Reset time.Duration
+}
+
+// Store keeps the buckets, by key
+type Store interface {
+	// Take takes a token from the bucket of key, created full
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// refill returns the tokens of a bucket holding tokens elapsed ago
+func refill(limit Limit, tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+}
+
+// take takes a token from a bucket holding tokens, returning the tokens left
+func take(limit Limit, tokens float64) (Result, float64) {
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return newResult(limit, allowed, tokens), tokens
+}
+
+func newResult(limit Limit, allowed bool, tokens float64) Result {
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	if math.IsInf(s, 0) || math.IsNaN(s) {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
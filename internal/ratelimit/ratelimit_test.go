@@ -0,0 +1,91 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/ratelimit"
+)
+
+func newRedisStore(t *testing.T) (*ratelimit.RedisStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return ratelimit.NewRedisStore(client, "test:"), server
+}
+
+func TestStores_TakeUntilEmpty(t *testing.T) {
+	redisStore, _ := newRedisStore(t)
+	stores := map[string]ratelimit.Store{
+		"memory": ratelimit.NewMemoryStore(),
+		"redis":  redisStore,
+	}
+
+	// A token every 10s, so the bucket doesn't refill during the test
+	limit := ratelimit.Limit{Rate: 0.1, Burst: 3}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for remaining := 2; remaining >= 0; remaining-- {
+				result, err := store.Take(ctx, "manager:1", limit)
+				require.NoError(t, err)
+				assert.True(t, result.Allowed)
+				assert.Equal(t, 3, result.Limit)
+				assert.Equal(t, remaining, result.Remaining)
+			}
+
+			result, err := store.Take(ctx, "manager:1", limit)
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+			assert.InDelta(t, 10*time.Second, result.RetryAfter, float64(time.Second))
+			assert.InDelta(t, 30*time.Second, result.Reset, float64(time.Second))
+
+			// Buckets are per key
+			result, err = store.Take(ctx, "manager:2", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		})
+	}
+}
+
+func TestRedisStore_Refills(t *testing.T) {
+	store, server := newRedisStore(t)
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+	for i := 0; i < 2; i++ {
+		_, err := store.Take(ctx, "ip:127.0.0.1", limit)
+		require.NoError(t, err)
+	}
+	result, err := store.Take(ctx, "ip:127.0.0.1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	server.SetTime(now.Add(1500 * time.Millisecond))
+	result, err = store.Take(ctx, "ip:127.0.0.1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// The bucket expires once full again
+	assert.True(t, server.Exists("test:ip:127.0.0.1"))
+	server.FastForward(3 * time.Second)
+	assert.False(t, server.Exists("test:ip:127.0.0.1"))
+}
+
+func TestRedisStore_Unavailable(t *testing.T) {
+	store, server := newRedisStore(t)
+	server.Close()
+
+	_, err := store.Take(context.Background(), "manager:1", ratelimit.Limit{Rate: 1, Burst: 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+type AttendanceRepository interface {
+	Employees(ctx context.Context, managerID int, departmentID *int, identityNumber *string) ([]models.Employee, error)
+	CheckIn(ctx context.Context, employeeID int, at time.Time) (*models.AttendanceRecord, error)
+	CheckOut(ctx context.Context, employeeID int, at time.Time) (*models.AttendanceRecord, error)
+	Records(ctx context.Context, managerID int, filter models.AttendanceFilterOptions) ([]models.AttendanceRecord, error)
+	Schedules(ctx context.Context, managerID int) (map[int]models.WorkSchedule, error)
+	SaveSchedule(ctx context.Context, managerID int, schedule models.WorkSchedule) (*models.WorkSchedule, error)
+}
+
+type attendanceRepository struct {
+	db database.DB
+}
+
+func NewAttendanceRepository(db database.DB) AttendanceRepository {
+	return &attendanceRepository{
+		db: db,
+	}
+}
+
+// Employees lists the manager's employees, optionally limited to one
+// department or one identity number.
+func (r *attendanceRepository) Employees(ctx context.Context, managerID int, departmentID *int, identityNumber *string) ([]models.Employee, error) {
+	query := `
+			SELECT e.id, e.identity_number, e.name, e.department_id, e.employment_status, e.hire_date, e.termination_date
+			FROM employees e
+			JOIN departments d ON e.department_id = d.department_id
+			WHERE d.manager_id = $1
+			AND e.deleted_at IS NULL
+	`
+	args := []interface{}{managerID}
+	argCount := 2
+
+	if departmentID != nil {
+		query += fmt.Sprintf(" AND e.department_id = $%d", argCount)
+		args = append(args, *departmentID)
+		argCount++
+	}
+
+	if identityNumber != nil {
+		query += fmt.Sprintf(" AND e.identity_number = $%d", argCount)
+		args = append(args, *identityNumber)
+	}
+
+	query += " ORDER BY e.department_id, e.identity_number"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying employees: %w", err)
+	}
+	defer rows.Close()
+
+	var employees []models.Employee
+	for rows.Next() {
+		var employee models.Employee
+		err := rows.Scan(
+			&employee.ID,
+			&employee.IdentityNumber,
+			&employee.Name,
+			&employee.DepartmentID,
+			&employee.Status,
+			&employee.HireDate,
+			&employee.TerminationDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning employee: %w", err)
+		}
+		employees = append(employees, employee)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating employees: %w", err)
+	}
+
+	return employees, nil
+}
+
+// CheckIn opens an attendance record unless the employee still has one open.
+func (r *attendanceRepository) CheckIn(ctx context.Context, employeeID int, at time.Time) (*models.AttendanceRecord, error) {
+	record := models.AttendanceRecord{EmployeeID: employeeID}
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		// Lock the employee so two check-ins can't both see no open record
+		err := r.db.QueryRowContext(ctx, `SELECT identity_number FROM employees WHERE id = $1 FOR UPDATE`, employeeID).Scan(&record.IdentityNumber)
+		if err == sql.ErrNoRows {
+			return models.ErrEmployeeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error locking employee: %w", err)
+		}
+
+		var open int
+		err = r.db.QueryRowContext(ctx, `
+				SELECT COUNT(*)
+				FROM attendance_records
+				WHERE employee_id = $1
+				AND check_out IS NULL`,
+			employeeID,
+		).Scan(&open)
+		if err != nil {
+			return fmt.Errorf("error checking open attendance: %w", err)
+		}
+
+		if open > 0 {
+			return models.ErrAlreadyCheckedIn
+		}
+
+		err = r.db.QueryRowContext(ctx, `
+				INSERT INTO attendance_records (employee_id, check_in, created_at, updated_at)
+				VALUES ($1, $2, NOW(), NOW())
+				RETURNING id, check_in, created_at, updated_at`,
+			employeeID, at,
+		).Scan(&record.ID, &record.CheckIn, &record.CreatedAt, &record.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating attendance record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// CheckOut closes the open attendance record of the employee. The check-out
+// can't be before the check-in.
+func (r *attendanceRepository) CheckOut(ctx context.Context, employeeID int, at time.Time) (*models.AttendanceRecord, error) {
+	var record models.AttendanceRecord
+	err := r.db.QueryRowContext(ctx, `
+			UPDATE attendance_records a
+			SET check_out = $2, updated_at = NOW()
+			FROM employees e
+			WHERE a.employee_id = e.id
+			AND a.employee_id = $1
+			AND a.check_out IS NULL
+			AND a.check_in <= $2
+			RETURNING a.id, a.employee_id, e.identity_number, a.check_in, a.check_out, a.created_at, a.updated_at`,
+		employeeID, at,
+	).Scan(
+		&record.ID,
+		&record.EmployeeID,
+		&record.IdentityNumber,
+		&record.CheckIn,
+		&record.CheckOut,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotCheckedIn
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error checking out: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Records lists the attendance records checked in within [From, To) of the
+// manager's employees.
+func (r *attendanceRepository) Records(ctx context.Context, managerID int, filter models.AttendanceFilterOptions) ([]models.AttendanceRecord, error) {
+	query := `
+			SELECT a.id, a.employee_id, e.identity_number, a.check_in, a.check_out, a.created_at, a.updated_at
+			FROM attendance_records a
+			JOIN employees e ON a.employee_id = e.id
+			JOIN departments d ON e.department_id = d.department_id
+			WHERE d.manager_id = $1
+			AND a.check_in >= $2
+			AND a.check_in < $3
+	`
+	args := []interface{}{managerID, filter.From, filter.To}
+	argCount := 4
+
+	if filter.DepartmentID != nil {
+		query += fmt.Sprintf(" AND e.department_id = $%d", argCount)
+		args = append(args, *filter.DepartmentID)
+		argCount++
+	}
+
+	if filter.IdentityNumber != nil {
+		query += fmt.Sprintf(" AND e.identity_number = $%d", argCount)
+		args = append(args, *filter.IdentityNumber)
+	}
+
+	query += " ORDER BY a.check_in"
+
+	rows, err := r.db.QueryContext(database.ReadOnly(ctx), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying attendance records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.AttendanceRecord
+	for rows.Next() {
+		var record models.AttendanceRecord
+		err := rows.Scan(
+			&record.ID,
+			&record.EmployeeID,
+			&record.IdentityNumber,
+			&record.CheckIn,
+			&record.CheckOut,
+			&record.CreatedAt,
+			&record.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning attendance record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attendance records: %w", err)
+	}
+
+	return records, nil
+}
+
+// Schedules returns the schedule of every department of the manager, using
+// the default schedule for departments without one.
+func (r *attendanceRepository) Schedules(ctx context.Context, managerID int) (map[int]models.WorkSchedule, error) {
+	rows, err := r.db.QueryContext(ctx, `
+			SELECT d.department_id, to_char(s.start_time, 'HH24:MI'), to_char(s.end_time, 'HH24:MI'),
+					s.grace_minutes, s.work_days, s.timezone
+			FROM departments d
+			LEFT JOIN department_schedules s ON s.department_id = d.department_id
+			WHERE d.manager_id = $1`,
+		managerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make(map[int]models.WorkSchedule)
+	for rows.Next() {
+		var (
+			departmentID int
+			startTime    sql.NullString
+			endTime      sql.NullString
+			grace        sql.NullInt64
+			workDays     []int64
+			timezone     sql.NullString
+		)
+		if err := rows.Scan(&departmentID, &startTime, &endTime, &grace, &workDays, &timezone); err != nil {
+			return nil, fmt.Errorf("error scanning schedule: %w", err)
+		}
+
+		if !startTime.Valid {
+			schedules[departmentID] = models.DefaultWorkSchedule(departmentID)
+			continue
+		}
+
+		schedule := models.WorkSchedule{
+			DepartmentID: departmentID,
+			StartTime:    startTime.String,
+			EndTime:      endTime.String,
+			GraceMinutes: int(grace.Int64),
+			Timezone:     timezone.String,
+		}
+		for _, day := range workDays {
+			schedule.WorkDays = append(schedule.WorkDays, time.Weekday(day))
+		}
+		schedules[departmentID] = schedule
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// SaveSchedule creates or replaces the schedule of one of the manager's
+// departments.
+func (r *attendanceRepository) SaveSchedule(ctx context.Context, managerID int, schedule models.WorkSchedule) (*models.WorkSchedule, error) {
+	workDays := make([]int64, 0, len(schedule.WorkDays))
+	for _, day := range schedule.WorkDays {
+		workDays = append(workDays, int64(day))
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+			INSERT INTO department_schedules (
+					department_id, start_time, end_time, grace_minutes, work_days, timezone, created_at, updated_at
+			)
+			SELECT d.department_id, $3, $4, $5, $6, $7, NOW(), NOW()
+			FROM departments d
+			WHERE d.department_id = $1
+			AND d.manager_id = $2
+			ON CONFLICT (department_id) DO UPDATE SET
+					start_time = EXCLUDED.start_time,
+					end_time = EXCLUDED.end_time,
+					grace_minutes = EXCLUDED.grace_minutes,
+					work_days = EXCLUDED.work_days,
+					timezone = EXCLUDED.timezone,
+					updated_at = NOW()`,
+		schedule.DepartmentID, managerID, schedule.StartTime, schedule.EndTime,
+		schedule.GraceMinutes, workDays, schedule.Timezone,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error saving schedule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, models.ErrDepartmentNotFound
+	}
+
+	return &schedule, nil
+}
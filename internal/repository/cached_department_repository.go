@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/cache"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+// cachedDepartmentRepository serves FindByID from a cache, read to check
+// the owner of a department on every update and delete. Departments are
+// invalidated once renamed, deleted or merged.
+type cachedDepartmentRepository struct {
+	DepartmentRepository
+	departments *cache.Cache[models.Department]
+}
+
+func NewCachedDepartmentRepository(repo DepartmentRepository, departments *cache.Cache[models.Department]) DepartmentRepository {
+	return &cachedDepartmentRepository{DepartmentRepository: repo, departments: departments}
+}
+
+func (r *cachedDepartmentRepository) FindByID(ctx context.Context, id int) (*models.Department, error) {
+	dept, err := r.departments.Get(ctx, strconv.Itoa(id), func(ctx context.Context) (models.Department, error) {
+		dept, err := r.DepartmentRepository.FindByID(ctx, id)
+		if err != nil {
+			return models.Department{}, err
+		}
+		return *dept, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dept, nil
+}
+
+func (r *cachedDepartmentRepository) Update(ctx context.Context, id int, req models.UpdateDepartmentRequest, managerID int) (*models.Department, error) {
+	defer r.departments.Invalidate(ctx, strconv.Itoa(id))
+	return r.DepartmentRepository.Update(ctx, id, req, managerID)
+}
+
+func (r *cachedDepartmentRepository) Delete(ctx context.Context, id int, managerID int) error {
+	defer r.departments.Invalidate(ctx, strconv.Itoa(id))
+	return r.DepartmentRepository.Delete(ctx, id, managerID)
+}
+
+func (r *cachedDepartmentRepository) Merge(ctx context.Context, sourceIDs []int, targetID int, managerID int) (int, error) {
+	keys := make([]string, len(sourceIDs))
+	for i, id := range sourceIDs {
+		keys[i] = strconv.Itoa(id)
+	}
+	defer r.departments.Invalidate(ctx, keys...)
+	return r.DepartmentRepository.Merge(ctx, sourceIDs, targetID, managerID)
+}
+
+// cachedDepartmentTransferRepository invalidates the departments whose
+// owner changed once their transfer is accepted
+type cachedDepartmentTransferRepository struct {
+	DepartmentTransferRepository
+	departments *cache.Cache[models.Department]
+}
+
+func NewCachedDepartmentTransferRepository(repo DepartmentTransferRepository, departments *cache.Cache[models.Department]) DepartmentTransferRepository {
+	return &cachedDepartmentTransferRepository{DepartmentTransferRepository: repo, departments: departments}
+}
+
+func (r *cachedDepartmentTransferRepository) Accept(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	transfer, err := r.DepartmentTransferRepository.Accept(ctx, managerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.departments.Invalidate(ctx, strconv.Itoa(transfer.DepartmentID))
+	return transfer, nil
+}
@@ -0,0 +1,87 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/cache"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+)
+
+// departmentStore is a DepartmentRepository over a map, counting the
+// lookups of FindByID
+type departmentStore struct {
+	repository.DepartmentRepository
+	departments map[int]models.Department
+	finds       int
+}
+
+func (s *departmentStore) FindByID(ctx context.Context, id int) (*models.Department, error) {
+	s.finds++
+	dept, ok := s.departments[id]
+	if !ok {
+		return nil, models.ErrDepartmentNotFound
+	}
+	return &dept, nil
+}
+
+func (s *departmentStore) Update(ctx context.Context, id int, req models.UpdateDepartmentRequest, managerID int) (*models.Department, error) {
+	dept := s.departments[id]
+	dept.Name = req.Name
+	s.departments[id] = dept
+	return &dept, nil
+}
+
+// transferStore accepts every transfer of department 1 to manager 2
+type transferStore struct {
+	repository.DepartmentTransferRepository
+	departments *departmentStore
+}
+
+func (s *transferStore) Accept(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	dept := s.departments.departments[1]
+	dept.ManagerID = managerID
+	s.departments.departments[1] = dept
+	return &models.DepartmentTransfer{ID: id, DepartmentID: 1}, nil
+}
+
+func TestCachedDepartmentRepository_InvalidatesOnChange(t *testing.T) {
+	store := &departmentStore{departments: map[int]models.Department{1: {ID: 1, Name: "Sales", ManagerID: 1}}}
+	departments := cache.New[models.Department](cache.NewMemoryStore(10), "test_department", time.Minute)
+	repo := repository.NewCachedDepartmentRepository(store, departments)
+	transfers := repository.NewCachedDepartmentTransferRepository(&transferStore{departments: store}, departments)
+	ctx := context.Background()
+
+	for range 2 {
+		dept, err := repo.FindByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Sales", dept.Name)
+	}
+	assert.Equal(t, 1, store.finds)
+
+	_, err := repo.Update(ctx, 1, models.UpdateDepartmentRequest{Name: "Marketing"}, 1)
+	require.NoError(t, err)
+	dept, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Marketing", dept.Name)
+	assert.Equal(t, 2, store.finds)
+
+	// Accepting a transfer changes the owner
+	_, err = transfers.Accept(ctx, 2, 10)
+	require.NoError(t, err)
+	dept, err = repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dept.ManagerID)
+
+	// Missing departments aren't cached
+	for range 2 {
+		_, err = repo.FindByID(ctx, 404)
+		assert.ErrorIs(t, err, models.ErrDepartmentNotFound)
+	}
+	assert.Equal(t, 5, store.finds)
+}
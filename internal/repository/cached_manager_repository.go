@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/cache"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// cachedManagerRepository serves the profiles of GetByID from a cache, read
+// on every GET /v1/user. Cached profiles leave the password hash out, so
+// GetByID never returns it, logins read it with GetByEmail.
+type cachedManagerRepository struct {
+	ManagerRepository
+	profiles *cache.Cache[models.Manager]
+}
+
+func NewCachedManagerRepository(repo ManagerRepository, profiles *cache.Cache[models.Manager]) ManagerRepository {
+	return &cachedManagerRepository{ManagerRepository: repo, profiles: profiles}
+}
+
+func (r *cachedManagerRepository) GetByID(ctx context.Context, id int) (*models.Manager, *utils.GoGoError) {
+	var loadErr *utils.GoGoError
+	manager, err := r.profiles.Get(ctx, strconv.Itoa(id), func(ctx context.Context) (models.Manager, error) {
+		manager, err := r.ManagerRepository.GetByID(ctx, id)
+		if err != nil {
+			loadErr = err
+			return models.Manager{}, err
+		}
+		manager.Password = ""
+		return *manager, nil
+	})
+	if err != nil {
+		return nil, loadErr
+	}
+
+	return &manager, nil
+}
+
+func (r *cachedManagerRepository) Update(ctx context.Context, manager *utils.ManagerRequest) *utils.GoGoError {
+	err := r.ManagerRepository.Update(ctx, manager)
+	// Even on error, the update may have gone through before a timeout
+	r.profiles.Invalidate(ctx, strconv.Itoa(manager.ID))
+	return err
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+type CustomFieldRepository interface {
+	List(ctx context.Context, managerID int) ([]models.CustomField, error)
+	// ListForShare lists like List, locking the fields so they can't be
+	// deleted until the transaction of ctx ends
+	ListForShare(ctx context.Context, managerID int) ([]models.CustomField, error)
+	Create(ctx context.Context, field *models.CustomField) (*models.CustomField, error)
+	Delete(ctx context.Context, managerID int, key string) error
+}
+
+type customFieldRepository struct {
+	db database.DB
+}
+
+func NewCustomFieldRepository(db database.DB) CustomFieldRepository {
+	return &customFieldRepository{
+		db: db,
+	}
+}
+
+func (r *customFieldRepository) List(ctx context.Context, managerID int) ([]models.CustomField, error) {
+	return r.list(ctx, managerID, "")
+}
+
+func (r *customFieldRepository) ListForShare(ctx context.Context, managerID int) ([]models.CustomField, error) {
+	return r.list(ctx, managerID, " FOR SHARE")
+}
+
+// list selects the custom fields of the manager followed by lock
+func (r *customFieldRepository) list(ctx context.Context, managerID int, lock string) ([]models.CustomField, error) {
+	query := `
+			SELECT id, manager_id, key, label, type, required, allowed_values, created_at, updated_at
+			FROM employee_custom_fields
+			WHERE manager_id = $1
+			ORDER BY id
+	` + lock
+
+	rows, err := r.db.QueryContext(ctx, query, managerID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	var fields []models.CustomField
+	for rows.Next() {
+		var field models.CustomField
+		err := rows.Scan(
+			&field.ID,
+			&field.ManagerID,
+			&field.Key,
+			&field.Label,
+			&field.Type,
+			&field.Required,
+			&field.AllowedValues,
+			&field.CreatedAt,
+			&field.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning custom field: %w", err)
+		}
+		fields = append(fields, field)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custom fields: %w", err)
+	}
+
+	return fields, nil
+}
+
+func (r *customFieldRepository) Create(ctx context.Context, field *models.CustomField) (*models.CustomField, error) {
+	query := `
+			INSERT INTO employee_custom_fields (
+					manager_id, key, label, type, required, allowed_values, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+			RETURNING id, manager_id, key, label, type, required, allowed_values, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		field.ManagerID,
+		field.Key,
+		field.Label,
+		field.Type,
+		field.Required,
+		field.AllowedValues,
+	).Scan(
+		&field.ID,
+		&field.ManagerID,
+		&field.Key,
+		&field.Label,
+		&field.Type,
+		&field.Required,
+		&field.AllowedValues,
+		&field.CreatedAt,
+		&field.UpdatedAt,
+	)
+	if err != nil {
+		if utils.UniqueConstraintError(err) != nil {
+			return nil, models.ErrDuplicateCustomField.Wrap(err)
+		}
+		return nil, fmt.Errorf("error creating custom field: %w", err)
+	}
+
+	return field, nil
+}
+
+func (r *customFieldRepository) Delete(ctx context.Context, managerID int, key string) error {
+	return r.db.InTx(ctx, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx,
+			`DELETE FROM employee_custom_fields WHERE manager_id = $1 AND key = $2`,
+			managerID, key,
+		)
+		if err != nil {
+			return fmt.Errorf("error deleting custom field: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking deletion result: %w", err)
+		}
+
+		if rows == 0 {
+			return models.ErrCustomFieldNotFound
+		}
+
+		// Drop the stored values so they don't linger on the manager's employees
+		_, err = r.db.ExecContext(ctx, `
+				UPDATE employees e
+				SET custom_fields = e.custom_fields - $2
+				FROM departments d
+				WHERE e.department_id = d.department_id
+				AND d.manager_id = $1
+				AND e.custom_fields ? $2
+		`, managerID, key)
+		if err != nil {
+			return fmt.Errorf("error removing custom field values: %w", err)
+		}
+
+		return nil
+	})
+}
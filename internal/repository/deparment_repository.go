@@ -1,34 +1,39 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
 // repositories/department.go
 type DepartmentRepository interface {
-    Create(name string, managerID int) (*models.Department, error)
-    FindAll(limit, offset int, name string) ([]models.Department, error)
-    FindByID(id int) (*models.Department, error)  // Added
-    Update(id int, name string) (*models.Department, error) 
-    Delete(id int) error                         // Added
-    HasEmployees(id int) (bool, error)           // Added
+    Create(ctx context.Context, name string, managerID int) (*models.Department, error)
+    FindAll(ctx context.Context, limit, offset int, name string, managerID int) ([]models.Department, error)
+    FindByID(ctx context.Context, id int) (*models.Department, error)  // Added
+    Update(ctx context.Context, id int, req models.UpdateDepartmentRequest, managerID int) (*models.Department, error)
+    Delete(ctx context.Context, id int, managerID int) error          // Added
+    HasEmployees(ctx context.Context, id int) (bool, error)           // Added
+    Merge(ctx context.Context, sourceIDs []int, targetID int, managerID int) (int, error)
 }
 
 // Define the implementation struct
 type departmentRepository struct {
-    db *sql.DB
+    db database.DB
 }
 
 // Constructor
-func NewDepartmentRepository(db *sql.DB) DepartmentRepository {
+func NewDepartmentRepository(db database.DB) DepartmentRepository {
     return &departmentRepository{db: db}
 }
 
 // Implement Create method
-func (r *departmentRepository) Create(name string, managerID int) (*models.Department, error) {
+func (r *departmentRepository) Create(ctx context.Context, name string, managerID int) (*models.Department, error) {
     var dept models.Department
     
     query := `
@@ -36,7 +41,7 @@ func (r *departmentRepository) Create(name string, managerID int) (*models.Depar
         VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
         RETURNING department_id, name, manager_id, created_at, updated_at`
     
-    err := r.db.QueryRow(query, name, managerID).Scan(
+    err := r.db.QueryRowContext(ctx, query, name, managerID).Scan(
         &dept.ID,
         &dept.Name,
         &dept.ManagerID,
@@ -45,24 +50,25 @@ func (r *departmentRepository) Create(name string, managerID int) (*models.Depar
     )
     
     if err != nil {
-        return nil, fmt.Errorf("error creating department: %v", err)
+        return nil, fmt.Errorf("error creating department: %w", err)
     }
     
     return &dept, nil
 }
 
-// Implement FindAll method
-func (r *departmentRepository) FindAll(limit, offset int, name string) ([]models.Department, error) {
+// FindAll lists the departments of the manager
+func (r *departmentRepository) FindAll(ctx context.Context, limit, offset int, name string, managerID int) ([]models.Department, error) {
     query := `
-        SELECT department_id, name
+        SELECT department_id, name, updated_at
         FROM departments 
-        WHERE ($1 = '' OR name ILIKE $1 || '%')
+        WHERE manager_id = $1
+        AND ($2 = '' OR name ILIKE $2 || '%')
         ORDER BY created_at DESC
-        LIMIT $2 OFFSET $3`
+        LIMIT $3 OFFSET $4`
 
-    rows, err := r.db.Query(query, name, limit, offset)
+    rows, err := r.db.QueryContext(database.ReadOnly(ctx), query, managerID, name, limit, offset)
     if err != nil {
-        return nil, fmt.Errorf("error querying departments: %v", err)
+        return nil, fmt.Errorf("error querying departments: %w", err)
     }
     defer rows.Close()
 
@@ -72,79 +78,129 @@ func (r *departmentRepository) FindAll(limit, offset int, name string) ([]models
         err := rows.Scan(
             &dept.ID,
             &dept.Name,
+            &dept.UpdatedAt,
         )
         if err != nil {
-            return nil, fmt.Errorf("error scanning department: %v", err)
+            return nil, fmt.Errorf("error scanning department: %w", err)
         }
         departments = append(departments, dept)
     }
 
     if err = rows.Err(); err != nil {
-        return nil, fmt.Errorf("error iterating departments: %v", err)
+        return nil, fmt.Errorf("error iterating departments: %w", err)
     }
 
     return departments, nil
 }
 
-func (r *departmentRepository) FindByID(id int) (*models.Department, error) {
+func (r *departmentRepository) FindByID(ctx context.Context, id int) (*models.Department, error) {
     var dept models.Department
     query := `SELECT department_id, name, manager_id FROM departments WHERE department_id = $1`
     
-    err := r.db.QueryRow(query, id).Scan(&dept.ID, &dept.Name, &dept.ManagerID)
+    err := r.db.QueryRowContext(ctx, query, id).Scan(&dept.ID, &dept.Name, &dept.ManagerID)
     if err == sql.ErrNoRows {
-        return nil, fmt.Errorf("department not found")
+        return nil, models.ErrDepartmentNotFound
     }
     if err != nil {
-        return nil, fmt.Errorf("error finding department: %v", err)
+        return nil, fmt.Errorf("error finding department: %w", err)
     }
     
     return &dept, nil
 }
 
-func (r *departmentRepository) Update(id int, name string) (*models.Department, error) {
+// Update renames the department if it still belongs to the manager
+func (r *departmentRepository) Update(ctx context.Context, id int, req models.UpdateDepartmentRequest, managerID int) (*models.Department, error) {
     var dept models.Department
-    query := `
-        UPDATE departments 
-        SET name = $1, updated_at = CURRENT_TIMESTAMP 
-        WHERE department_id = $2 
-        RETURNING department_id, name`
+    update := func(ctx context.Context) error {
+        query := `
+            UPDATE departments 
+            SET name = $1, updated_at = CURRENT_TIMESTAMP 
+            WHERE department_id = $2 
+            AND manager_id = $3
+            RETURNING department_id, name, updated_at`
 
-    err := r.db.QueryRow(query, name, id).Scan(&dept.ID, &dept.Name)
-    if err == sql.ErrNoRows {
-        return nil, fmt.Errorf("department not found")
+        err := r.db.QueryRowContext(ctx, query, req.Name, id, managerID).Scan(&dept.ID, &dept.Name, &dept.UpdatedAt)
+        if err == sql.ErrNoRows {
+            return r.notOwned(ctx, id)
+        }
+        if err != nil {
+            return fmt.Errorf("error updating department: %w", err)
+        }
+        return nil
     }
+
+    if req.IfUpdatedAt == nil {
+        if err := update(ctx); err != nil {
+            return nil, err
+        }
+        return &dept, nil
+    }
+
+    // Lock the department so no other update goes through between the
+    // check and this one
+    err := r.db.InTx(ctx, func(ctx context.Context) error {
+        var updatedAt time.Time
+        err := r.db.QueryRowContext(ctx,
+            "SELECT updated_at FROM departments WHERE department_id = $1 AND manager_id = $2 FOR UPDATE", id, managerID,
+        ).Scan(&updatedAt)
+        if err == sql.ErrNoRows {
+            return r.notOwned(ctx, id)
+        }
+        if err != nil {
+            return fmt.Errorf("error locking department: %w", err)
+        }
+
+        if !updatedAt.Equal(*req.IfUpdatedAt) {
+            return utils.ErrPreconditionFailed
+        }
+        return update(ctx)
+    })
     if err != nil {
-        return nil, fmt.Errorf("error updating department: %v", err)
+        return nil, err
     }
 
     return &dept, nil
 }
 
-func (r *departmentRepository) Delete(id int) error {
-    query := `DELETE FROM departments WHERE department_id = $1`
+// Delete deletes the department if it still belongs to the manager
+func (r *departmentRepository) Delete(ctx context.Context, id int, managerID int) error {
+    query := `DELETE FROM departments WHERE department_id = $1 AND manager_id = $2`
     
-    result, err := r.db.Exec(query, id)
+    result, err := r.db.ExecContext(ctx, query, id, managerID)
     if err != nil {
-        return fmt.Errorf("error deleting department: %v", err)
+        // Employees, soft deleted ones included, still reference it
+        if utils.ForeignKeyError(err) != nil {
+            return models.ErrDepartmentHasEmployees.Wrap(err)
+        }
+        return fmt.Errorf("error deleting department: %w", err)
     }
 
     rowsAffected, err := result.RowsAffected()
     if err != nil {
-        return fmt.Errorf("error getting rows affected: %v", err)
+        return fmt.Errorf("error getting rows affected: %w", err)
     }
 
     if rowsAffected == 0 {
-        return fmt.Errorf("department not found")
+        return r.notOwned(ctx, id)
     }
 
     return nil
 }
 
-func (r *departmentRepository) HasEmployees(id int) (bool, error) {
+// notOwned tells why a statement limited to the departments of a manager
+// matched none, the department is either missing or owned by someone else
+func (r *departmentRepository) notOwned(ctx context.Context, id int) error {
+    if _, err := r.FindByID(ctx, id); err != nil {
+        return err
+    }
+    return models.ErrDepartmentNotOwned
+}
+
+func (r *departmentRepository) HasEmployees(ctx context.Context, id int) (bool, error) {
     var count int
     query := `SELECT COUNT(*) FROM employees WHERE department_id = $1`
     
-    err := r.db.QueryRow(query, id).Scan(&count)
+    err := r.db.QueryRowContext(ctx, query, id).Scan(&count)
     if err != nil {
         return false, err
     }
@@ -152,3 +208,59 @@ func (r *departmentRepository) HasEmployees(id int) (bool, error) {
     return count > 0, nil
 }
 
+// Merge moves every employee of the source departments into the target and
+// deletes the sources, in one transaction. All departments must belong to
+// the manager. Returns the number of employees moved.
+func (r *departmentRepository) Merge(ctx context.Context, sourceIDs []int, targetID int, managerID int) (int, error) {
+    var moved int64
+    err := r.db.InTx(ctx, func(ctx context.Context) error {
+        // Lock every department involved so no employee can be added to a source meanwhile
+        ids := append([]int{targetID}, sourceIDs...)
+        var owned int
+        err := r.db.QueryRowContext(ctx, `
+            SELECT COUNT(*) FROM (
+                SELECT department_id
+                FROM departments
+                WHERE department_id = ANY($1)
+                AND manager_id = $2
+                AND deleted_at IS NULL
+                FOR UPDATE
+            ) locked`,
+            ids, managerID,
+        ).Scan(&owned)
+        if err != nil {
+            return fmt.Errorf("error locking departments: %w", err)
+        }
+
+        if owned != len(ids) {
+            return models.ErrDepartmentNotFound
+        }
+
+        // Soft deleted employees are moved too, they still reference the department
+        result, err := r.db.ExecContext(ctx, `
+            UPDATE employees
+            SET department_id = $1, updated_at = CURRENT_TIMESTAMP
+            WHERE department_id = ANY($2)`,
+            targetID, sourceIDs,
+        )
+        if err != nil {
+            return fmt.Errorf("error moving employees: %w", err)
+        }
+
+        moved, err = result.RowsAffected()
+        if err != nil {
+            return fmt.Errorf("error getting rows affected: %w", err)
+        }
+
+        if _, err := r.db.ExecContext(ctx, `DELETE FROM departments WHERE department_id = ANY($1)`, sourceIDs); err != nil {
+            return fmt.Errorf("error deleting merged departments: %w", err)
+        }
+
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+
+    return int(moved), nil
+}
@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	mocksDatabase "github.com/ngikut-project-sprint/GoGoManager/mocks/database"
+)
+
+func TestDepartmentRepository_Delete_HasEmployees(t *testing.T) {
+	mockDB := &mocksDatabase.DB{}
+	repo := repository.NewDepartmentRepository(mockDB)
+
+	mockDB.On("ExecContext", mock.Anything, mock.Anything, 1, 10).
+		Return(nil, &pq.Error{Code: "23503", Constraint: "employees_department_id_fkey"})
+
+	err := repo.Delete(context.Background(), 1, 10)
+	assert.ErrorIs(t, err, models.ErrDepartmentHasEmployees)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDepartmentRepository_FindAll_OnlyOwnDepartments(t *testing.T) {
+	mockDB := &mocksDatabase.DB{}
+	repo := repository.NewDepartmentRepository(mockDB)
+
+	// Manager 2 lists without seeing the departments of manager 1
+	ownDepartments := mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "WHERE manager_id = $1")
+	})
+	mockDB.On("QueryContext", mock.Anything, ownDepartments, 2, "", 10, 0).
+		Return(nil, sql.ErrConnDone)
+
+	_, err := repo.FindAll(context.Background(), 10, 0, "", 2)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDepartmentRepository_Delete_NotOwned(t *testing.T) {
+	mockDB := &mocksDatabase.DB{}
+	mockRow := &mocksDatabase.Row{}
+	repo := repository.NewDepartmentRepository(mockDB)
+
+	// The department was transferred to another manager since it was read
+	mockDB.On("ExecContext", mock.Anything, mock.Anything, 1, 10).Return(driver.RowsAffected(0), nil)
+	mockDB.On("QueryRowContext", mock.Anything, mock.Anything, 1).Return(mockRow)
+	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := repo.Delete(context.Background(), 1, 10)
+	assert.ErrorIs(t, err, models.ErrDepartmentNotOwned)
+
+	mockDB.AssertExpectations(t)
+	mockRow.AssertExpectations(t)
+}
+
+func TestDepartmentRepository_Update_NotFound(t *testing.T) {
+	mockDB := &mocksDatabase.DB{}
+	updated := &mocksDatabase.Row{}
+	found := &mocksDatabase.Row{}
+	repo := repository.NewDepartmentRepository(mockDB)
+
+	updateDepartment := mock.MatchedBy(func(query string) bool {
+		return strings.Contains(query, "AND manager_id = $3")
+	})
+	mockDB.On("QueryRowContext", mock.Anything, updateDepartment, "Marketing", 1, 10).Return(updated)
+	updated.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+	mockDB.On("QueryRowContext", mock.Anything, mock.Anything, 1).Return(found)
+	found.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+
+	_, err := repo.Update(context.Background(), 1, models.UpdateDepartmentRequest{Name: "Marketing"}, 10)
+	assert.ErrorIs(t, err, models.ErrDepartmentNotFound)
+
+	mockDB.AssertExpectations(t)
+}
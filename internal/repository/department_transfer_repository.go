@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+type DepartmentTransferRepository interface {
+	Create(ctx context.Context, managerID int, departmentID int, toManagerEmail string) (*models.DepartmentTransfer, error)
+	List(ctx context.Context, managerID int) ([]models.DepartmentTransfer, error)
+	Accept(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error)
+	Reject(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error)
+	Cancel(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error)
+}
+
+const transferColumns = `t.id, t.department_id, d.name, t.from_manager_id, fm.email, t.to_manager_id, tm.email,
+			t.status, t.created_at, t.decided_at`
+
+const transferJoins = `
+			FROM department_transfers t
+			JOIN departments d ON t.department_id = d.department_id
+			JOIN managers fm ON t.from_manager_id = fm.id
+			JOIN managers tm ON t.to_manager_id = tm.id`
+
+type departmentTransferRepository struct {
+	db database.DB
+}
+
+func NewDepartmentTransferRepository(db database.DB) DepartmentTransferRepository {
+	return &departmentTransferRepository{
+		db: db,
+	}
+}
+
+// Create proposes to hand one of the manager's departments over to the
+// manager with the given email.
+func (r *departmentTransferRepository) Create(ctx context.Context, managerID int, departmentID int, toManagerEmail string) (*models.DepartmentTransfer, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM departments WHERE department_id = $1 AND manager_id = $2 AND deleted_at IS NULL",
+		departmentID, managerID,
+	).Scan(&count)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying department: %w", err)
+	}
+
+	if count == 0 {
+		return nil, models.ErrDepartmentNotFound
+	}
+
+	var toManagerID int
+	err = r.db.QueryRowContext(ctx,
+		"SELECT id FROM managers WHERE email = $1 AND deleted_at IS NULL",
+		toManagerEmail,
+	).Scan(&toManagerID)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrManagerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding manager: %w", err)
+	}
+
+	if toManagerID == managerID {
+		return nil, models.ErrSelfTransfer
+	}
+
+	var id int
+	err = r.db.QueryRowContext(ctx, `
+			INSERT INTO department_transfers (department_id, from_manager_id, to_manager_id, status, created_at)
+			VALUES ($1, $2, $3, 'pending', NOW())
+			RETURNING id`,
+		departmentID, managerID, toManagerID,
+	).Scan(&id)
+	if err != nil {
+		if utils.UniqueConstraintError(err) != nil {
+			return nil, models.ErrTransferPending
+		}
+		return nil, fmt.Errorf("error creating department transfer: %w", err)
+	}
+
+	return r.findByID(ctx, id)
+}
+
+// List returns the transfers the manager proposed or received, newest first.
+func (r *departmentTransferRepository) List(ctx context.Context, managerID int) ([]models.DepartmentTransfer, error) {
+	rows, err := r.db.QueryContext(database.ReadOnly(ctx), `
+			SELECT `+transferColumns+transferJoins+`
+			WHERE t.from_manager_id = $1 OR t.to_manager_id = $1
+			ORDER BY t.created_at DESC, t.id DESC`,
+		managerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying department transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []models.DepartmentTransfer
+	for rows.Next() {
+		var transfer models.DepartmentTransfer
+		if err := scanTransfer(rows, &transfer); err != nil {
+			return nil, fmt.Errorf("error scanning department transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating department transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// Accept completes a pending transfer addressed to the manager, moving the
+// department and so all of its employees to them.
+func (r *departmentTransferRepository) Accept(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	var transfer *models.DepartmentTransfer
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		var departmentID, fromManagerID int
+		var status models.TransferStatus
+		err := r.db.QueryRowContext(ctx, `
+				SELECT department_id, from_manager_id, status
+				FROM department_transfers
+				WHERE id = $1
+				AND to_manager_id = $2
+				FOR UPDATE`,
+			id, managerID,
+		).Scan(&departmentID, &fromManagerID, &status)
+		if err == sql.ErrNoRows {
+			return models.ErrTransferNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error finding department transfer: %w", err)
+		}
+
+		if status != models.TransferPending {
+			return models.ErrTransferNotPending
+		}
+
+		// The proposing manager must still own the department
+		result, err := r.db.ExecContext(ctx, `
+				UPDATE departments
+				SET manager_id = $1, updated_at = NOW()
+				WHERE department_id = $2
+				AND manager_id = $3
+				AND deleted_at IS NULL`,
+			managerID, departmentID, fromManagerID,
+		)
+		if err != nil {
+			return fmt.Errorf("error transferring department: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error getting rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return models.ErrTransferNotPending
+		}
+
+		_, err = r.db.ExecContext(ctx,
+			"UPDATE department_transfers SET status = 'accepted', decided_at = NOW() WHERE id = $1",
+			id,
+		)
+		if err != nil {
+			return fmt.Errorf("error accepting department transfer: %w", err)
+		}
+
+		transfer, err = r.findByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// Reject declines a pending transfer addressed to the manager.
+func (r *departmentTransferRepository) Reject(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	return r.decide(ctx, id, "to_manager_id", managerID, models.TransferRejected)
+}
+
+// Cancel withdraws a pending transfer the manager proposed.
+func (r *departmentTransferRepository) Cancel(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	return r.decide(ctx, id, "from_manager_id", managerID, models.TransferCancelled)
+}
+
+// decide closes a pending transfer on behalf of the manager in column.
+func (r *departmentTransferRepository) decide(ctx context.Context, id int, column string, managerID int, status models.TransferStatus) (*models.DepartmentTransfer, error) {
+	result, err := r.db.ExecContext(ctx, `
+			UPDATE department_transfers
+			SET status = $1, decided_at = NOW()
+			WHERE id = $2
+			AND `+column+` = $3
+			AND status = 'pending'`,
+		status, id, managerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating department transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	transfer, err := r.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tell a missing transfer apart from one that was already closed
+	if transfer.FromManagerID != managerID && transfer.ToManagerID != managerID {
+		return nil, models.ErrTransferNotFound
+	}
+
+	if rowsAffected == 0 {
+		return nil, models.ErrTransferNotPending
+	}
+
+	return transfer, nil
+}
+
+func (r *departmentTransferRepository) findByID(ctx context.Context, id int) (*models.DepartmentTransfer, error) {
+	var transfer models.DepartmentTransfer
+	err := scanTransfer(r.db.QueryRowContext(ctx, `SELECT `+transferColumns+transferJoins+` WHERE t.id = $1`, id), &transfer)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding department transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// scanTransfer scans a row selected with transferColumns
+func scanTransfer(row database.Row, transfer *models.DepartmentTransfer) error {
+	return row.Scan(
+		&transfer.ID,
+		&transfer.DepartmentID,
+		&transfer.DepartmentName,
+		&transfer.FromManagerID,
+		&transfer.FromManagerEmail,
+		&transfer.ToManagerID,
+		&transfer.ToManagerEmail,
+		&transfer.Status,
+		&transfer.CreatedAt,
+		&transfer.DecidedAt,
+	)
+}
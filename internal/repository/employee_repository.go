@@ -4,8 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/logging"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
@@ -15,13 +19,25 @@ type EmployeeRepository interface {
 	Create(ctx context.Context, employee *models.Employee) (*models.Employee, error)
 	Update(ctx context.Context, identityNumber string, req models.UpdateEmployeeRequest) (*models.Employee, error)
 	Delete(ctx context.Context, identityNumber string) error
+	ChangeStatus(ctx context.Context, identityNumber string, req models.ChangeStatusRequest) (*models.Employee, error)
+	StatusHistory(ctx context.Context, identityNumber string) ([]models.EmploymentEvent, error)
+	BulkMove(ctx context.Context, req models.BulkEmployeeRequest, departmentID int) (*models.BulkResult, error)
+	BulkDelete(ctx context.Context, req models.BulkEmployeeRequest) (*models.BulkResult, error)
 }
 
+const employeeColumns = `id, identity_number, name, employee_image_uri, gender, department_id,
+			custom_fields, employment_status, hire_date, termination_date, termination_reason,
+			created_at, updated_at, deleted_at`
+
+const employeeColumnsWithAlias = `e.id, e.identity_number, e.name, e.employee_image_uri, e.gender, e.department_id,
+			e.custom_fields, e.employment_status, e.hire_date, e.termination_date, e.termination_reason,
+			e.created_at, e.updated_at, e.deleted_at`
+
 type employeeRepository struct {
-	db *sql.DB
+	db database.DB
 }
 
-func NewEmployeeRepository(db *sql.DB) EmployeeRepository {
+func NewEmployeeRepository(db database.DB) EmployeeRepository {
 	return &employeeRepository{
 		db: db,
 	}
@@ -30,12 +46,11 @@ func NewEmployeeRepository(db *sql.DB) EmployeeRepository {
 func (r *employeeRepository) List(ctx context.Context, filter models.FilterOptions) ([]models.Employee, error) {
 	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
 	if !ok {
-		return nil, fmt.Errorf("unauthorized: missing or invalid JWT claims")
+		return nil, utils.ErrMissingClaims
 	}
 
 	query := `
-			SELECT e.id, e.identity_number, e.name, e.employee_image_uri, e.gender, e.department_id, 
-						 e.created_at, e.updated_at, e.deleted_at
+			SELECT ` + employeeColumnsWithAlias + `
 			FROM employees e
 			JOIN departments d ON e.department_id = d.department_id
 			WHERE e.deleted_at IS NULL
@@ -44,30 +59,24 @@ func (r *employeeRepository) List(ctx context.Context, filter models.FilterOptio
 	args := []interface{}{claims.ID} // Current manager's ID from JWT
 	argCount := 2                    // Start from 2 since we used $1 for manager_id
 
-	if filter.IdentityNumber != nil {
-		query += fmt.Sprintf(" AND identity_number LIKE $%d", argCount)
-		args = append(args, "%"+*filter.IdentityNumber+"%")
-		argCount++
-	}
+	conditions, filterArgs, argCount := employeeFilterConditions(filter, argCount)
+	query += conditions
+	args = append(args, filterArgs...)
 
-	if filter.Gender != nil {
-		query += fmt.Sprintf(" AND gender = $%d", argCount)
-		args = append(args, *filter.Gender)
-		argCount++
-	}
-
-	if filter.DepartmentID != nil {
-		query += fmt.Sprintf(" AND department_id = $%d", argCount)
-		args = append(args, *filter.DepartmentID)
+	if filter.AfterID > 0 {
+		query += fmt.Sprintf(" AND e.id > $%d", argCount)
+		args = append(args, filter.AfterID)
 		argCount++
 	}
 
+	query += " ORDER BY e.id"
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCount, argCount+1)
 	args = append(args, filter.Limit, filter.Offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	// Runs on a read replica when there is one, see database.Replicated
+	rows, err := r.db.QueryContext(database.ReadOnly(ctx), query, args...)
 	if err != nil {
-		fmt.Printf("Database error: %v\n", err) // Add this line for debugging
+		logging.FromContext(ctx).Error("Failed to query employees", "error", err)
 		return nil, fmt.Errorf("error querying employees: %w", err)
 	}
 	defer rows.Close()
@@ -75,18 +84,7 @@ func (r *employeeRepository) List(ctx context.Context, filter models.FilterOptio
 	var employees []models.Employee
 	for rows.Next() {
 		var emp models.Employee
-		err := rows.Scan(
-			&emp.ID,
-			&emp.IdentityNumber,
-			&emp.Name,
-			&emp.EmployeeImageURI,
-			&emp.Gender,
-			&emp.DepartmentID,
-			&emp.CreatedAt,
-			&emp.UpdatedAt,
-			&emp.DeletedAt,
-		)
-		if err != nil {
+		if err := scanEmployee(rows, &emp); err != nil {
 			return nil, fmt.Errorf("error scanning employee: %w", err)
 		}
 		employees = append(employees, emp)
@@ -95,15 +93,72 @@ func (r *employeeRepository) List(ctx context.Context, filter models.FilterOptio
 	return employees, nil
 }
 
+// employeeFilterConditions builds the WHERE conditions of filter on the
+// employees table aliased as e, numbering placeholders from argCount. It
+// returns the next free placeholder number.
+func employeeFilterConditions(filter models.FilterOptions, argCount int) (string, []interface{}, int) {
+	var query string
+	var args []interface{}
+
+	if filter.IdentityNumber != nil {
+		query += fmt.Sprintf(" AND e.identity_number LIKE $%d", argCount)
+		args = append(args, "%"+*filter.IdentityNumber+"%")
+		argCount++
+	}
+
+	if filter.Gender != nil {
+		query += fmt.Sprintf(" AND e.gender = $%d", argCount)
+		args = append(args, *filter.Gender)
+		argCount++
+	}
+
+	if filter.DepartmentID != nil {
+		query += fmt.Sprintf(" AND e.department_id = $%d", argCount)
+		args = append(args, *filter.DepartmentID)
+		argCount++
+	}
+
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			placeholders[i] = fmt.Sprintf("$%d", argCount)
+			args = append(args, status)
+			argCount++
+		}
+		query += fmt.Sprintf(" AND e.employment_status IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	// Containment can use the GIN index of custom_fields, the values are
+	// cast to the JSON type they are stored as
+	for key, value := range filter.CustomFields {
+		query += fmt.Sprintf(" AND e.custom_fields @> jsonb_build_object($%d::text, $%d%s)", argCount, argCount+1, jsonbCast(value))
+		args = append(args, key, value)
+		argCount += 2
+	}
+
+	return query, args, argCount
+}
+
+// jsonbCast returns the cast making jsonb_build_object store value as the
+// JSON type of its Go type
+func jsonbCast(value interface{}) string {
+	switch value.(type) {
+	case float64:
+		return "::numeric"
+	case bool:
+		return "::boolean"
+	default:
+		return "::text"
+	}
+}
+
 func (r *employeeRepository) Create(ctx context.Context, employee *models.Employee) (*models.Employee, error) {
 	query := `
 			INSERT INTO employees (
 					identity_number, name, employee_image_uri, gender, department_id,
-					created_at, updated_at
-			) VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-			RETURNING id, identity_number, name, employee_image_uri, gender, department_id, 
-								created_at, updated_at, deleted_at
-	`
+					custom_fields, employment_status, hire_date, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+			RETURNING ` + employeeColumns
 
 	row := r.db.QueryRowContext(
 		ctx,
@@ -113,20 +168,18 @@ func (r *employeeRepository) Create(ctx context.Context, employee *models.Employ
 		employee.EmployeeImageURI,
 		employee.Gender,
 		employee.DepartmentID,
+		employee.CustomFields,
+		employee.Status,
+		employee.HireDate,
 	)
 
-	err := row.Scan(
-		&employee.ID,
-		&employee.IdentityNumber,
-		&employee.Name,
-		&employee.EmployeeImageURI,
-		&employee.Gender,
-		&employee.DepartmentID,
-		&employee.CreatedAt,
-		&employee.UpdatedAt,
-		&employee.DeletedAt,
-	)
-	if err != nil {
+	if err := scanEmployee(row, employee); err != nil {
+		if utils.UniqueConstraintError(err) != nil {
+			return nil, models.ErrDuplicateIdentityNumber.Wrap(err)
+		}
+		if utils.ForeignKeyError(err) != nil {
+			return nil, models.ErrInvalidDepartment.Wrap(err)
+		}
 		return nil, fmt.Errorf("error creating employee: %w", err)
 	}
 
@@ -136,96 +189,115 @@ func (r *employeeRepository) Create(ctx context.Context, employee *models.Employ
 func (r *employeeRepository) Update(ctx context.Context, identityNumber string, req models.UpdateEmployeeRequest) (*models.Employee, error) {
 	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
 	if !ok {
-		return nil, fmt.Errorf("unauthorized: missing or invalid JWT claims")
+		return nil, utils.ErrMissingClaims
 	}
 
-	var existingDeptID int
-	err := r.db.QueryRowContext(ctx, `
-			SELECT e.department_id 
-			FROM employees e
-			JOIN departments d ON e.department_id = d.department_id
-			WHERE e.identity_number = $1 
-			AND e.deleted_at IS NULL 
-			AND d.manager_id = $2`,
-		identityNumber, claims.ID,
-	).Scan(&existingDeptID)
+	// The employee row stays locked until it is updated, so the ownership
+	// checks still hold when the update runs
+	var employee models.Employee
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		var existingDeptID int
+		var updatedAt time.Time
+		err := r.db.QueryRowContext(ctx, `
+				SELECT e.department_id, e.updated_at
+				FROM employees e
+				JOIN departments d ON e.department_id = d.department_id
+				WHERE e.identity_number = $1 
+				AND e.deleted_at IS NULL 
+				AND d.manager_id = $2
+				FOR UPDATE OF e`,
+			identityNumber, claims.ID,
+		).Scan(&existingDeptID, &updatedAt)
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("employee not found or unauthorized")
-	}
-	if err != nil {
-		return nil, fmt.Errorf("error verifying employee: %w", err)
-	}
+		if err == sql.ErrNoRows {
+			return models.ErrEmployeeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error verifying employee: %w", err)
+		}
 
-	if req.DepartmentID != nil {
-		var count int
-		err := r.db.QueryRowContext(ctx,
-			"SELECT COUNT(*) FROM departments WHERE department_id = $1 AND manager_id = $2 AND deleted_at IS NULL",
-			*req.DepartmentID, claims.ID,
-		).Scan(&count)
+		if req.IfUpdatedAt != nil && !updatedAt.Equal(*req.IfUpdatedAt) {
+			return utils.ErrPreconditionFailed
+		}
 
-		if err != nil {
-			return nil, fmt.Errorf("error verifying new department: %w", err)
+		if req.DepartmentID != nil {
+			// Share lock the department so it can't be deleted or handed over
+			// before the employee is moved into it
+			var departmentID int
+			err := r.db.QueryRowContext(ctx,
+				"SELECT department_id FROM departments WHERE department_id = $1 AND manager_id = $2 AND deleted_at IS NULL FOR SHARE",
+				*req.DepartmentID, claims.ID,
+			).Scan(&departmentID)
+
+			if err == sql.ErrNoRows {
+				return models.ErrDepartmentNotOwned
+			}
+			if err != nil {
+				return fmt.Errorf("error verifying new department: %w", err)
+			}
 		}
 
-		if count == 0 {
-			return nil, fmt.Errorf("unauthorized: new department does not belong to the current manager")
+		// Build dynamic update query
+		query := "UPDATE employees SET updated_at = NOW()"
+		args := []interface{}{}
+		argCount := 1
+
+		// Only include fields that are provided in the request
+		if req.Name != nil {
+			query += fmt.Sprintf(", name = $%d", argCount)
+			args = append(args, *req.Name)
+			argCount++
+		}
+		if req.EmployeeImageURI != nil {
+			query += fmt.Sprintf(", employee_image_uri = $%d", argCount)
+			args = append(args, *req.EmployeeImageURI)
+			argCount++
+		}
+		if req.Gender != nil {
+			query += fmt.Sprintf(", gender = $%d", argCount)
+			args = append(args, *req.Gender)
+			argCount++
+		}
+		if req.DepartmentID != nil {
+			query += fmt.Sprintf(", department_id = $%d", argCount)
+			args = append(args, *req.DepartmentID)
+			argCount++
+		}
+		if req.IdentityNumber != nil {
+			query += fmt.Sprintf(", identity_number = $%d", argCount)
+			args = append(args, *req.IdentityNumber)
+			argCount++
 		}
-	}
 
-	// Build dynamic update query
-	query := "UPDATE employees SET updated_at = NOW()"
-	args := []interface{}{}
-	argCount := 1
+		// Merge custom fields into the stored ones, null values remove the key
+		if len(req.CustomFields) > 0 {
+			query += fmt.Sprintf(", custom_fields = jsonb_strip_nulls(custom_fields || $%d::jsonb)", argCount)
+			args = append(args, req.CustomFields)
+			argCount++
+		}
 
-	// Only include fields that are provided in the request
-	if req.Name != nil {
-		query += fmt.Sprintf(", name = $%d", argCount)
-		args = append(args, *req.Name)
-		argCount++
-	}
-	if req.EmployeeImageURI != nil {
-		query += fmt.Sprintf(", employee_image_uri = $%d", argCount)
-		args = append(args, *req.EmployeeImageURI)
-		argCount++
-	}
-	if req.Gender != nil {
-		query += fmt.Sprintf(", gender = $%d", argCount)
-		args = append(args, *req.Gender)
-		argCount++
-	}
-	if req.DepartmentID != nil {
-		query += fmt.Sprintf(", department_id = $%d", argCount)
-		args = append(args, *req.DepartmentID)
-		argCount++
-	}
-	if req.IdentityNumber != nil {
-		query += fmt.Sprintf(", identity_number = $%d", argCount)
-		args = append(args, *req.IdentityNumber)
-		argCount++
-	}
+		query += fmt.Sprintf(" WHERE identity_number = $%d AND deleted_at IS NULL", argCount)
+		args = append(args, identityNumber)
+		query += " RETURNING " + employeeColumns
 
-	query += fmt.Sprintf(" WHERE identity_number = $%d AND deleted_at IS NULL", argCount)
-	args = append(args, identityNumber)
-	query += " RETURNING id, identity_number, name, employee_image_uri, gender, department_id, created_at, updated_at, deleted_at"
+		err = scanEmployee(r.db.QueryRowContext(ctx, query, args...), &employee)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return models.ErrEmployeeNotFound
+			}
+			if utils.UniqueConstraintError(err) != nil {
+				return models.ErrDuplicateIdentityNumber.Wrap(err)
+			}
+			if utils.ForeignKeyError(err) != nil {
+				return models.ErrInvalidDepartment.Wrap(err)
+			}
+			return fmt.Errorf("error updating employee: %w", err)
+		}
 
-	var employee models.Employee
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
-		&employee.ID,
-		&employee.IdentityNumber,
-		&employee.Name,
-		&employee.EmployeeImageURI,
-		&employee.Gender,
-		&employee.DepartmentID,
-		&employee.CreatedAt,
-		&employee.UpdatedAt,
-		&employee.DeletedAt,
-	)
+		return nil
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("employee not found")
-		}
-		return nil, fmt.Errorf("error updating employee: %w", err)
+		return nil, err
 	}
 
 	return &employee, nil
@@ -234,7 +306,7 @@ func (r *employeeRepository) Update(ctx context.Context, identityNumber string,
 func (r *employeeRepository) Delete(ctx context.Context, identityNumber string) error {
 	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
 	if !ok {
-		return fmt.Errorf("unauthorized: missing or invalid JWT claims")
+		return utils.ErrMissingClaims
 	}
 
 	query := `
@@ -258,8 +330,305 @@ func (r *employeeRepository) Delete(ctx context.Context, identityNumber string)
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("employee not found") // Changed this line
+		return models.ErrEmployeeNotFound
 	}
 
 	return nil
 }
+
+func (r *employeeRepository) ChangeStatus(ctx context.Context, identityNumber string, req models.ChangeStatusRequest) (*models.Employee, error) {
+	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		return nil, utils.ErrMissingClaims
+	}
+
+	var employee models.Employee
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		// Lock the employee row so concurrent transitions are applied one by one
+		var (
+			employeeID int
+			current    models.EmploymentStatus
+		)
+		err := r.db.QueryRowContext(ctx, `
+				SELECT e.id, e.employment_status
+				FROM employees e
+				JOIN departments d ON e.department_id = d.department_id
+				WHERE e.identity_number = $1
+				AND e.deleted_at IS NULL
+				AND d.manager_id = $2
+				FOR UPDATE OF e`,
+			identityNumber, claims.ID,
+		).Scan(&employeeID, &current)
+		if err == sql.ErrNoRows {
+			return models.ErrEmployeeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error verifying employee: %w", err)
+		}
+
+		if !current.CanTransitionTo(req.Status) {
+			return fmt.Errorf("%w: %s to %s", models.ErrInvalidStatusTransition, current, req.Status)
+		}
+
+		query := "UPDATE employees SET employment_status = $1, updated_at = NOW()"
+		args := []interface{}{req.Status}
+		if req.Status == models.Terminated {
+			query += ", termination_date = $2, termination_reason = $3"
+			args = append(args, req.EffectiveDate, req.Reason)
+		}
+		query += fmt.Sprintf(" WHERE id = $%d RETURNING %s", len(args)+1, employeeColumns)
+		args = append(args, employeeID)
+
+		if err := scanEmployee(r.db.QueryRowContext(ctx, query, args...), &employee); err != nil {
+			return fmt.Errorf("error updating employment status: %w", err)
+		}
+
+		_, err = r.db.ExecContext(ctx, `
+				INSERT INTO employee_status_history (
+						employee_id, from_status, to_status, reason, effective_date, changed_by, created_at
+				) VALUES ($1, $2, $3, $4, $5, $6, NOW())`,
+			employeeID, current, req.Status, req.Reason, req.EffectiveDate, claims.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("error recording status history: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &employee, nil
+}
+
+func (r *employeeRepository) StatusHistory(ctx context.Context, identityNumber string) ([]models.EmploymentEvent, error) {
+	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		return nil, utils.ErrMissingClaims
+	}
+
+	var employeeID int
+	err := r.db.QueryRowContext(ctx, `
+			SELECT e.id
+			FROM employees e
+			JOIN departments d ON e.department_id = d.department_id
+			WHERE e.identity_number = $1
+			AND e.deleted_at IS NULL
+			AND d.manager_id = $2`,
+		identityNumber, claims.ID,
+	).Scan(&employeeID)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrEmployeeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error verifying employee: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+			SELECT id, employee_id, from_status, to_status, reason, effective_date, changed_by, created_at
+			FROM employee_status_history
+			WHERE employee_id = $1
+			ORDER BY created_at, id`,
+		employeeID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying status history: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.EmploymentEvent
+	for rows.Next() {
+		var event models.EmploymentEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.EmployeeID,
+			&event.FromStatus,
+			&event.ToStatus,
+			&event.Reason,
+			&event.EffectiveDate,
+			&event.ChangedBy,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning status history: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status history: %w", err)
+	}
+
+	return events, nil
+}
+
+// BulkMove moves the selected employees to departmentID, which must belong
+// to the current manager like in Update.
+func (r *employeeRepository) BulkMove(ctx context.Context, req models.BulkEmployeeRequest, departmentID int) (*models.BulkResult, error) {
+	return r.bulk(ctx, req, models.BulkItemUpdated, func(ctx context.Context, managerID int, ids []int) error {
+		var count int
+		err := r.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM departments WHERE department_id = $1 AND manager_id = $2 AND deleted_at IS NULL",
+			departmentID, managerID,
+		).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("error verifying new department: %w", err)
+		}
+
+		if count == 0 {
+			return models.ErrDepartmentNotOwned
+		}
+
+		_, err = r.db.ExecContext(ctx,
+			"UPDATE employees SET department_id = $1, updated_at = NOW() WHERE id = ANY($2)",
+			departmentID, ids,
+		)
+		if err != nil {
+			return fmt.Errorf("error moving employees: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// BulkDelete soft deletes the selected employees.
+func (r *employeeRepository) BulkDelete(ctx context.Context, req models.BulkEmployeeRequest) (*models.BulkResult, error) {
+	return r.bulk(ctx, req, models.BulkItemDeleted, func(ctx context.Context, managerID int, ids []int) error {
+		_, err := r.db.ExecContext(ctx,
+			"UPDATE employees SET deleted_at = NOW(), updated_at = NOW() WHERE id = ANY($1)",
+			ids,
+		)
+		if err != nil {
+			return fmt.Errorf("error deleting employees: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// bulk locks the employees selected by req that belong to the current
+// manager and applies apply to them in one transaction. When an identity
+// number can't be found nothing is applied and the result says which ones
+// failed.
+func (r *employeeRepository) bulk(ctx context.Context, req models.BulkEmployeeRequest, status models.BulkItemStatus, apply func(ctx context.Context, managerID int, ids []int) error) (*models.BulkResult, error) {
+	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		return nil, utils.ErrMissingClaims
+	}
+
+	var result *models.BulkResult
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		query := `
+				SELECT e.id, e.identity_number
+				FROM employees e
+				JOIN departments d ON e.department_id = d.department_id
+				WHERE e.deleted_at IS NULL
+				AND d.manager_id = $1
+		`
+		args := []interface{}{claims.ID}
+
+		if req.Filter != nil {
+			conditions, filterArgs, _ := employeeFilterConditions(*req.Filter, 2)
+			query += conditions
+			args = append(args, filterArgs...)
+		} else {
+			query += " AND e.identity_number = ANY($2)"
+			args = append(args, req.IdentityNumbers)
+		}
+
+		// One employee past the limit is enough to reject the request, the
+		// others are never locked
+		args = append(args, models.MaxBulkEmployees+1)
+		query += fmt.Sprintf(" ORDER BY e.id LIMIT $%d FOR UPDATE OF e", len(args))
+
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("error selecting employees: %w", err)
+		}
+
+		var ids []int
+		found := make(map[string]bool)
+		var matched []string
+		for rows.Next() {
+			var id int
+			var identityNumber string
+			if err := rows.Scan(&id, &identityNumber); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning employee: %w", err)
+			}
+			ids = append(ids, id)
+			found[identityNumber] = true
+			matched = append(matched, identityNumber)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating employees: %w", err)
+		}
+
+		if len(ids) > models.MaxBulkEmployees {
+			return models.ErrBulkTooLarge
+		}
+
+		// A filter selects exactly the matching employees, identity numbers
+		// are reported in the requested order
+		requested := matched
+		if req.Filter == nil {
+			requested = req.IdentityNumbers
+		}
+
+		result = &models.BulkResult{Results: make([]models.BulkItemResult, 0, len(requested))}
+		for _, identityNumber := range requested {
+			item := models.BulkItemResult{IdentityNumber: identityNumber, Status: status}
+			if !found[identityNumber] {
+				item.Status = models.BulkItemNotFound
+				result.Failed++
+			}
+			result.Results = append(result.Results, item)
+		}
+
+		if result.Failed > 0 {
+			for i := range result.Results {
+				if result.Results[i].Status == status {
+					result.Results[i].Status = models.BulkItemSkipped
+				}
+			}
+			return nil
+		}
+
+		if len(ids) > 0 {
+			if err := apply(ctx, claims.ID, ids); err != nil {
+				return err
+			}
+		}
+
+		result.Applied = true
+		result.Succeeded = len(result.Results)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// scanEmployee scans a row selected with employeeColumns
+func scanEmployee(row database.Row, employee *models.Employee) error {
+	return row.Scan(
+		&employee.ID,
+		&employee.IdentityNumber,
+		&employee.Name,
+		&employee.EmployeeImageURI,
+		&employee.Gender,
+		&employee.DepartmentID,
+		&employee.CustomFields,
+		&employee.Status,
+		&employee.HireDate,
+		&employee.TerminationDate,
+		&employee.TerminationReason,
+		&employee.CreatedAt,
+		&employee.UpdatedAt,
+		&employee.DeletedAt,
+	)
+}
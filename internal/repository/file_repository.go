@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type FileRepository interface {
+	// Save stores content under name, which must not be taken yet
+	Save(ctx context.Context, name string, content io.Reader) error
+}
+
+// diskFileRepository keeps the files in a directory, created on the first
+// upload
+type diskFileRepository struct {
+	dir string
+}
+
+func NewDiskFileRepository(dir string) FileRepository {
+	return &diskFileRepository{
+		dir: dir,
+	}
+}
+
+func (r *diskFileRepository) Save(ctx context.Context, name string, content io.Reader) error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating file directory: %w", err)
+	}
+
+	path := filepath.Join(r.dir, filepath.Base(name))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+
+	_, err = io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Leave no partial file behind
+		os.Remove(path)
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,446 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+type LeaveRepository interface {
+	FindEmployee(ctx context.Context, managerID int, identityNumber string) (*models.Employee, error)
+	Create(ctx context.Context, leave *models.LeaveRequest) (*models.LeaveRequest, error)
+	FindByID(ctx context.Context, managerID int, id int) (*models.LeaveRequest, error)
+	List(ctx context.Context, managerID int, filter models.LeaveFilterOptions) ([]models.LeaveRequest, error)
+	EnsureBalance(ctx context.Context, employeeID int, year int, leaveType models.LeaveType, allowance int) error
+	Balance(ctx context.Context, employeeID int, year int, leaveType models.LeaveType) (*models.LeaveBalance, error)
+	Approve(ctx context.Context, managerID int, id int, note *string) (*models.LeaveRequest, error)
+	Decide(ctx context.Context, managerID int, id int, status models.LeaveStatus, note *string) (*models.LeaveRequest, error)
+	Calendar(ctx context.Context, managerID int, from, to models.Date, departmentID *int) ([]models.LeaveCalendarEntry, error)
+}
+
+const leaveColumns = `l.id, l.employee_id, e.identity_number, l.leave_type, l.start_date, l.end_date, l.days,
+			l.reason, l.status, l.decided_by, l.decided_at, l.decision_note, l.created_at, l.updated_at`
+
+type leaveRepository struct {
+	db database.DB
+}
+
+func NewLeaveRepository(db database.DB) LeaveRepository {
+	return &leaveRepository{
+		db: db,
+	}
+}
+
+func (r *leaveRepository) FindEmployee(ctx context.Context, managerID int, identityNumber string) (*models.Employee, error) {
+	var employee models.Employee
+	err := r.db.QueryRowContext(ctx, `
+			SELECT e.id, e.identity_number, e.name, e.department_id, e.employment_status, e.hire_date
+			FROM employees e
+			JOIN departments d ON e.department_id = d.department_id
+			WHERE e.identity_number = $1
+			AND e.deleted_at IS NULL
+			AND d.manager_id = $2`,
+		identityNumber, managerID,
+	).Scan(
+		&employee.ID,
+		&employee.IdentityNumber,
+		&employee.Name,
+		&employee.DepartmentID,
+		&employee.Status,
+		&employee.HireDate,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrEmployeeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding employee: %w", err)
+	}
+
+	return &employee, nil
+}
+
+// Create inserts a pending leave request unless it overlaps a pending or
+// approved request of the same employee. Pending requests are reserved so
+// they can't all be approved past the allowance, the remaining balance is
+// checked while the balance row is locked so concurrent requests can't
+// reserve it twice. The balance must have been accrued, see EnsureBalance.
+func (r *leaveRepository) Create(ctx context.Context, leave *models.LeaveRequest) (*models.LeaveRequest, error) {
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		// Lock the employee so two overlapping requests can't be created concurrently
+		_, err := r.db.ExecContext(ctx, `SELECT id FROM employees WHERE id = $1 FOR UPDATE`, leave.EmployeeID)
+		if err != nil {
+			return fmt.Errorf("error locking employee: %w", err)
+		}
+
+		var overlapping int
+		err = r.db.QueryRowContext(ctx, `
+				SELECT COUNT(*)
+				FROM leave_requests
+				WHERE employee_id = $1
+				AND status IN ('pending', 'approved')
+				AND start_date <= $3
+				AND end_date >= $2`,
+			leave.EmployeeID, leave.StartDate, leave.EndDate,
+		).Scan(&overlapping)
+		if err != nil {
+			return fmt.Errorf("error checking overlapping leave: %w", err)
+		}
+
+		if overlapping > 0 {
+			return models.ErrLeaveOverlap
+		}
+
+		if _, limited := models.LeaveAllowances[leave.Type]; limited {
+			var allowance, reserved int
+			err = r.db.QueryRowContext(ctx, `
+					SELECT b.allowance, COALESCE((
+							SELECT SUM(days) FROM leave_requests
+							WHERE employee_id = b.employee_id
+							AND leave_type = b.leave_type
+							AND EXTRACT(YEAR FROM start_date) = b.year
+							AND status IN ('pending', 'approved')
+					), 0)
+					FROM leave_balances b
+					WHERE b.employee_id = $1 AND b.year = $2 AND b.leave_type = $3
+					FOR UPDATE`,
+				leave.EmployeeID, leave.StartDate.Year(), leave.Type,
+			).Scan(&allowance, &reserved)
+			if err == sql.ErrNoRows {
+				return models.ErrInsufficientBalance
+			}
+			if err != nil {
+				return fmt.Errorf("error checking leave balance: %w", err)
+			}
+
+			if allowance-reserved < leave.Days {
+				return models.ErrInsufficientBalance
+			}
+		}
+
+		err = r.db.QueryRowContext(ctx, `
+				INSERT INTO leave_requests (
+						employee_id, leave_type, start_date, end_date, days, reason, status, created_at, updated_at
+				) VALUES ($1, $2, $3, $4, $5, $6, 'pending', NOW(), NOW())
+				RETURNING id, status, created_at, updated_at`,
+			leave.EmployeeID, leave.Type, leave.StartDate, leave.EndDate, leave.Days, leave.Reason,
+		).Scan(&leave.ID, &leave.Status, &leave.CreatedAt, &leave.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating leave request: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leave, nil
+}
+
+func (r *leaveRepository) FindByID(ctx context.Context, managerID int, id int) (*models.LeaveRequest, error) {
+	query := `
+			SELECT ` + leaveColumns + `
+			FROM leave_requests l
+			JOIN employees e ON l.employee_id = e.id
+			JOIN departments d ON e.department_id = d.department_id
+			WHERE l.id = $1
+			AND d.manager_id = $2
+	`
+
+	var leave models.LeaveRequest
+	err := scanLeaveRequest(r.db.QueryRowContext(ctx, query, id, managerID), &leave)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrLeaveNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding leave request: %w", err)
+	}
+
+	return &leave, nil
+}
+
+func (r *leaveRepository) List(ctx context.Context, managerID int, filter models.LeaveFilterOptions) ([]models.LeaveRequest, error) {
+	query := `
+			SELECT ` + leaveColumns + `
+			FROM leave_requests l
+			JOIN employees e ON l.employee_id = e.id
+			JOIN departments d ON e.department_id = d.department_id
+			WHERE d.manager_id = $1
+	`
+
+	args := []interface{}{managerID}
+	argCount := 2
+
+	if filter.IdentityNumber != nil {
+		query += fmt.Sprintf(" AND e.identity_number = $%d", argCount)
+		args = append(args, *filter.IdentityNumber)
+		argCount++
+	}
+
+	if filter.Status != nil {
+		query += fmt.Sprintf(" AND l.status = $%d", argCount)
+		args = append(args, *filter.Status)
+		argCount++
+	}
+
+	query += " ORDER BY l.start_date DESC, l.id DESC"
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(database.ReadOnly(ctx), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying leave requests: %w", err)
+	}
+	defer rows.Close()
+
+	var leaves []models.LeaveRequest
+	for rows.Next() {
+		var leave models.LeaveRequest
+		if err := scanLeaveRequest(rows, &leave); err != nil {
+			return nil, fmt.Errorf("error scanning leave request: %w", err)
+		}
+		leaves = append(leaves, leave)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating leave requests: %w", err)
+	}
+
+	return leaves, nil
+}
+
+// EnsureBalance accrues the yearly allowance of a leave type the first time
+// the balance of that year is needed. Existing balances are left untouched
+// so manual adjustments are kept.
+func (r *leaveRepository) EnsureBalance(ctx context.Context, employeeID int, year int, leaveType models.LeaveType, allowance int) error {
+	_, err := r.db.ExecContext(ctx, `
+			INSERT INTO leave_balances (employee_id, year, leave_type, allowance, created_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (employee_id, year, leave_type) DO NOTHING`,
+		employeeID, year, leaveType, allowance,
+	)
+	if err != nil {
+		return fmt.Errorf("error accruing leave balance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *leaveRepository) Balance(ctx context.Context, employeeID int, year int, leaveType models.LeaveType) (*models.LeaveBalance, error) {
+	balance := models.LeaveBalance{Year: year, Type: leaveType}
+	err := r.db.QueryRowContext(ctx, `
+			SELECT e.identity_number, b.allowance,
+					COALESCE(SUM(l.days) FILTER (WHERE l.status = 'approved'), 0),
+					COALESCE(SUM(l.days) FILTER (WHERE l.status = 'pending'), 0)
+			FROM leave_balances b
+			JOIN employees e ON b.employee_id = e.id
+			LEFT JOIN leave_requests l
+					ON l.employee_id = b.employee_id
+					AND l.leave_type = b.leave_type
+					AND EXTRACT(YEAR FROM l.start_date) = b.year
+			WHERE b.employee_id = $1
+			AND b.year = $2
+			AND b.leave_type = $3
+			GROUP BY e.identity_number, b.allowance`,
+		employeeID, year, leaveType,
+	).Scan(&balance.IdentityNumber, &balance.Allowance, &balance.Used, &balance.Pending)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrLeaveBalanceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying leave balance: %w", err)
+	}
+
+	balance.Remaining = balance.Allowance - balance.Used
+	return &balance, nil
+}
+
+// Approve approves a pending leave request, checking the remaining balance
+// while the balance row is locked so concurrent approvals can't overdraw it.
+// Leave types without an allowance are approved without a balance check.
+func (r *leaveRepository) Approve(ctx context.Context, managerID int, id int, note *string) (*models.LeaveRequest, error) {
+	var leave models.LeaveRequest
+	err := r.db.InTx(ctx, func(ctx context.Context) error {
+		err := scanLeaveRequest(r.db.QueryRowContext(ctx, `
+				SELECT `+leaveColumns+`
+				FROM leave_requests l
+				JOIN employees e ON l.employee_id = e.id
+				JOIN departments d ON e.department_id = d.department_id
+				WHERE l.id = $1
+				AND d.manager_id = $2
+				FOR UPDATE OF l`,
+			id, managerID,
+		), &leave)
+		if err == sql.ErrNoRows {
+			return models.ErrLeaveNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error finding leave request: %w", err)
+		}
+
+		if leave.Status != models.LeavePending {
+			return models.ErrLeaveNotPending
+		}
+
+		if _, limited := models.LeaveAllowances[leave.Type]; limited {
+			var allowance, used int
+			err = r.db.QueryRowContext(ctx, `
+					SELECT b.allowance, COALESCE((
+							SELECT SUM(days) FROM leave_requests
+							WHERE employee_id = b.employee_id
+							AND leave_type = b.leave_type
+							AND EXTRACT(YEAR FROM start_date) = b.year
+							AND status = 'approved'
+					), 0)
+					FROM leave_balances b
+					WHERE b.employee_id = $1 AND b.year = $2 AND b.leave_type = $3
+					FOR UPDATE`,
+				leave.EmployeeID, leave.StartDate.Year(), leave.Type,
+			).Scan(&allowance, &used)
+			if err == sql.ErrNoRows {
+				return models.ErrInsufficientBalance
+			}
+			if err != nil {
+				return fmt.Errorf("error checking leave balance: %w", err)
+			}
+
+			if allowance-used < leave.Days {
+				return models.ErrInsufficientBalance
+			}
+		}
+
+		err = r.db.QueryRowContext(ctx, `
+				UPDATE leave_requests
+				SET status = 'approved', decided_by = $1, decided_at = NOW(), decision_note = $2, updated_at = NOW()
+				WHERE id = $3
+				RETURNING status, decided_by, decided_at, decision_note, updated_at`,
+			managerID, note, id,
+		).Scan(&leave.Status, &leave.DecidedBy, &leave.DecidedAt, &leave.DecisionNote, &leave.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error approving leave request: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &leave, nil
+}
+
+// Decide rejects or cancels a leave request. Only pending requests can be
+// rejected, approved requests can still be cancelled.
+func (r *leaveRepository) Decide(ctx context.Context, managerID int, id int, status models.LeaveStatus, note *string) (*models.LeaveRequest, error) {
+	current := "l.status = 'pending'"
+	if status == models.LeaveCancelled {
+		current = "l.status IN ('pending', 'approved')"
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+			UPDATE leave_requests l
+			SET status = $1, decided_by = $2, decided_at = NOW(), decision_note = $3, updated_at = NOW()
+			FROM employees e, departments d
+			WHERE l.employee_id = e.id
+			AND e.department_id = d.department_id
+			AND l.id = $4
+			AND d.manager_id = $2
+			AND `+current,
+		status, managerID, note, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating leave request: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error checking update result: %w", err)
+	}
+
+	leave, err := r.FindByID(ctx, managerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rows == 0 {
+		return nil, models.ErrLeaveNotPending
+	}
+
+	return leave, nil
+}
+
+// Calendar lists approved leave overlapping [from, to] for the manager's
+// employees, optionally limited to one department.
+func (r *leaveRepository) Calendar(ctx context.Context, managerID int, from, to models.Date, departmentID *int) ([]models.LeaveCalendarEntry, error) {
+	query := `
+			SELECT e.identity_number, e.name, e.department_id, l.leave_type, l.start_date, l.end_date
+			FROM leave_requests l
+			JOIN employees e ON l.employee_id = e.id
+			JOIN departments d ON e.department_id = d.department_id
+			WHERE d.manager_id = $1
+			AND e.deleted_at IS NULL
+			AND l.status = 'approved'
+			AND l.start_date <= $3
+			AND l.end_date >= $2
+	`
+	args := []interface{}{managerID, from, to}
+
+	if departmentID != nil {
+		query += " AND e.department_id = $4"
+		args = append(args, *departmentID)
+	}
+
+	query += " ORDER BY l.start_date, e.identity_number"
+
+	rows, err := r.db.QueryContext(database.ReadOnly(ctx), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying leave calendar: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.LeaveCalendarEntry
+	for rows.Next() {
+		var entry models.LeaveCalendarEntry
+		err := rows.Scan(
+			&entry.IdentityNumber,
+			&entry.Name,
+			&entry.DepartmentID,
+			&entry.Type,
+			&entry.StartDate,
+			&entry.EndDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning leave calendar: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating leave calendar: %w", err)
+	}
+
+	return entries, nil
+}
+
+// scanLeaveRequest scans a row selected with leaveColumns
+func scanLeaveRequest(row database.Row, leave *models.LeaveRequest) error {
+	return row.Scan(
+		&leave.ID,
+		&leave.EmployeeID,
+		&leave.IdentityNumber,
+		&leave.Type,
+		&leave.StartDate,
+		&leave.EndDate,
+		&leave.Days,
+		&leave.Reason,
+		&leave.Status,
+		&leave.DecidedBy,
+		&leave.DecidedAt,
+		&leave.DecisionNote,
+		&leave.CreatedAt,
+		&leave.UpdatedAt,
+	)
+}
@@ -1,9 +1,10 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -16,11 +17,11 @@ import (
 )
 
 type ManagerRepository interface {
-	Create(email string, password string) (int, *utils.GoGoError)
-	GetAll() ([]models.Manager, *utils.GoGoError)
-	GetByID(id int) (*models.Manager, *utils.GoGoError)
-	GetByEmail(email string) (*models.Manager, *utils.GoGoError)
-	Update(manager *utils.ManagerRequest) *utils.GoGoError
+	Create(ctx context.Context, email string, password string) (int, *utils.GoGoError)
+	GetAll(ctx context.Context) ([]models.Manager, *utils.GoGoError)
+	GetByID(ctx context.Context, id int) (*models.Manager, *utils.GoGoError)
+	GetByEmail(ctx context.Context, email string) (*models.Manager, *utils.GoGoError)
+	Update(ctx context.Context, manager *utils.ManagerRequest) *utils.GoGoError
 }
 
 type managerRepository struct {
@@ -32,7 +33,7 @@ func NewManagerRepository(db database.DB, hashPassword utils.HashPassword) Manag
 	return &managerRepository{db: db, hashPassword: hashPassword}
 }
 
-func (r *managerRepository) Create(email string, password string) (int, *utils.GoGoError) {
+func (r *managerRepository) Create(ctx context.Context, email string, password string) (int, *utils.GoGoError) {
 	// Hash password
 	hashedPassword, err := r.hashPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -45,7 +46,7 @@ func (r *managerRepository) Create(email string, password string) (int, *utils.G
   VALUES ($1, $2)
   RETURNING id`
 
-	row := r.db.QueryRow(query, email, string(hashedPassword))
+	row := r.db.QueryRowContext(ctx, query, email, string(hashedPassword))
 
 	var id int
 	error := row.Scan(&id)
@@ -60,14 +61,14 @@ func (r *managerRepository) Create(email string, password string) (int, *utils.G
 	return id, nil
 }
 
-func (r *managerRepository) GetAll() ([]models.Manager, *utils.GoGoError) {
+func (r *managerRepository) GetAll(ctx context.Context) ([]models.Manager, *utils.GoGoError) {
 	var managers []models.Manager
 
 	query := `
   SELECT id, email, password, name, user_image_uri, company_name, company_image_uri, created_at, updated_at, deleted_at
   FROM managers`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, utils.WrapError(err, utils.SQLError, "Error querying managers")
 	}
@@ -89,7 +90,7 @@ func (r *managerRepository) GetAll() ([]models.Manager, *utils.GoGoError) {
 	return managers, nil
 }
 
-func (r *managerRepository) GetByID(id int) (*models.Manager, *utils.GoGoError) {
+func (r *managerRepository) GetByID(ctx context.Context, id int) (*models.Manager, *utils.GoGoError) {
 	var manager models.Manager
 
 	query := `
@@ -97,7 +98,10 @@ func (r *managerRepository) GetByID(id int) (*models.Manager, *utils.GoGoError)
   FROM managers 
   WHERE id = $1`
 
-	err := r.db.QueryRow(query, id).Scan(&manager.ID, &manager.Email, &manager.Password, &manager.Name, &manager.UserImageUri, &manager.CompanyName, &manager.CompanyImageUri, &manager.CreatedAt, &manager.UpdatedAt, &manager.DeletedAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&manager.ID, &manager.Email, &manager.Password, &manager.Name, &manager.UserImageUri, &manager.CompanyName, &manager.CompanyImageUri, &manager.CreatedAt, &manager.UpdatedAt, &manager.DeletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrManagerNotFound.Wrap(err)
+	}
 	if err != nil {
 		return nil, utils.WrapError(err, utils.SQLError, "Error querying manager by id")
 	}
@@ -105,7 +109,7 @@ func (r *managerRepository) GetByID(id int) (*models.Manager, *utils.GoGoError)
 	return &manager, nil
 }
 
-func (r *managerRepository) GetByEmail(email string) (*models.Manager, *utils.GoGoError) {
+func (r *managerRepository) GetByEmail(ctx context.Context, email string) (*models.Manager, *utils.GoGoError) {
 	var manager models.Manager
 
 	query := `
@@ -113,16 +117,18 @@ func (r *managerRepository) GetByEmail(email string) (*models.Manager, *utils.Go
   FROM managers 
   WHERE email = $1`
 
-	err := r.db.QueryRow(query, email).Scan(&manager.ID, &manager.Email, &manager.Password, &manager.Name, &manager.UserImageUri, &manager.CompanyName, &manager.CompanyImageUri, &manager.CreatedAt, &manager.UpdatedAt, &manager.DeletedAt)
+	err := r.db.QueryRowContext(ctx, query, email).Scan(&manager.ID, &manager.Email, &manager.Password, &manager.Name, &manager.UserImageUri, &manager.CompanyName, &manager.CompanyImageUri, &manager.CreatedAt, &manager.UpdatedAt, &manager.DeletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrManagerNotFound.Wrap(err)
+	}
 	if err != nil {
-		log.Println(err)
 		return nil, utils.WrapError(err, utils.SQLError, "Error querying manager by email")
 	}
 
 	return &manager, nil
 }
 
-func (r *managerRepository) Update(manager *utils.ManagerRequest) *utils.GoGoError {
+func (r *managerRepository) Update(ctx context.Context, manager *utils.ManagerRequest) *utils.GoGoError {
 	query := "UPDATE managers SET "
 	var params []interface{}
 	var setClauses []string
@@ -199,7 +205,32 @@ func (r *managerRepository) Update(manager *utils.ManagerRequest) *utils.GoGoErr
 	query += strings.Join(setClauses, ", ") + fmt.Sprintf(" WHERE id = $%d", paramCounter)
 	params = append(params, manager.ID)
 
-	_, err := r.db.Exec(query, params...)
+	update := func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, params...)
+		return err
+	}
+
+	var err error
+	if manager.IfUpdatedAt == nil {
+		err = update(ctx)
+	} else {
+		// Lock the manager so no other update goes through between the
+		// check and this one
+		err = r.db.InTx(ctx, func(ctx context.Context) error {
+			var updatedAt time.Time
+			err := r.db.QueryRowContext(ctx, "SELECT updated_at FROM managers WHERE id = $1 FOR UPDATE", manager.ID).Scan(&updatedAt)
+			if err != nil {
+				return err
+			}
+			if !updatedAt.Equal(*manager.IfUpdatedAt) {
+				return utils.ErrPreconditionFailed
+			}
+			return update(ctx)
+		})
+	}
+	if errors.Is(err, utils.ErrPreconditionFailed) {
+		return utils.ErrPreconditionFailed
+	}
 	if err != nil {
 		return utils.WrapError(err, utils.SQLError, "Error updating manager")
 	}
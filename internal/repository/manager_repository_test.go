@@ -1,6 +1,8 @@
 package repository_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -38,13 +40,14 @@ func TestManagerRepository_Create_Success(t *testing.T) {
 	}).Return(nil)
 
 	mockDB.On(
-		"QueryRow",
+		"QueryRowContext",
+		mock.Anything,
 		query,
 		email,
 		string(hashedPassword),
 	).Return(mockRow)
 
-	id, error := repo.Create(email, password)
+	id, error := repo.Create(context.Background(), email, password)
 
 	utils.NoError(t, error)
 	assert.Equal(t, 1, id)
@@ -66,7 +69,7 @@ func TestManagerRepository_Create_FailedHashPassword(t *testing.T) {
 
 	mockEncrypt.On("GenerateFromPassword", []byte(password), bcrypt.DefaultCost).Return(nil, errors.New("Failed hash password"))
 
-	id, error := repo.Create(email, password)
+	id, error := repo.Create(context.Background(), email, password)
 
 	utils.Error(t, error)
 	assert.Equal(t, 0, id)
@@ -96,13 +99,14 @@ func TestManagerRepository_Create_EmailAlreadyRegistered(t *testing.T) {
 	mockRow.On("Scan", mock.AnythingOfType("*int")).Return(errors.New("Email already registered"))
 
 	mockDB.On(
-		"QueryRow",
+		"QueryRowContext",
+		mock.Anything,
 		query,
 		email,
 		string(hashedPassword),
 	).Return(mockRow)
 
-	id, error := repo.Create(email, password)
+	id, error := repo.Create(context.Background(), email, password)
 
 	utils.Error(t, error)
 	assert.Equal(t, 0, id)
@@ -132,13 +136,14 @@ func TestManagerRepository_Create_DatabaseError(t *testing.T) {
 	mockRow.On("Scan", mock.AnythingOfType("*int")).Return(errors.New("Database error"))
 
 	mockDB.On(
-		"QueryRow",
+		"QueryRowContext",
+		mock.Anything,
 		query,
 		email,
 		string(hashedPassword),
 	).Return(mockRow)
 
-	id, error := repo.Create(email, password)
+	id, error := repo.Create(context.Background(), email, password)
 
 	utils.Error(t, error)
 	assert.Equal(t, 0, id)
@@ -225,9 +230,9 @@ func TestManagerRepository_GetAll_Success(t *testing.T) {
   SELECT id, email, password, name, user_image_uri, company_name, company_image_uri, created_at, updated_at, deleted_at
   FROM managers`
 
-	mockDB.On("Query", query).Return(mockRows, nil)
+	mockDB.On("QueryContext", mock.Anything, query).Return(mockRows, nil)
 
-	managers, err := repo.GetAll()
+	managers, err := repo.GetAll(context.Background())
 
 	utils.NoError(t, err)
 	assert.Equal(t, expectedManagers, managers)
@@ -265,9 +270,9 @@ func TestManagerRepository_GetAll_ScanError(t *testing.T) {
   SELECT id, email, password, name, user_image_uri, company_name, company_image_uri, created_at, updated_at, deleted_at
   FROM managers`
 
-	mockDB.On("Query", query).Return(mockRows, nil)
+	mockDB.On("QueryContext", mock.Anything, query).Return(mockRows, nil)
 
-	managers, err := repo.GetAll()
+	managers, err := repo.GetAll(context.Background())
 
 	utils.Error(t, err)
 	assert.Nil(t, managers)
@@ -293,9 +298,9 @@ func TestManagerRepository_GetAll_RowsError(t *testing.T) {
   SELECT id, email, password, name, user_image_uri, company_name, company_image_uri, created_at, updated_at, deleted_at
   FROM managers`
 
-	mockDB.On("Query", query).Return(mockRows, nil)
+	mockDB.On("QueryContext", mock.Anything, query).Return(mockRows, nil)
 
-	managers, err := repo.GetAll()
+	managers, err := repo.GetAll(context.Background())
 
 	utils.Error(t, err)
 	assert.Nil(t, managers)
@@ -317,9 +322,9 @@ func TestManagerRepository_GetAll_DatabaseError(t *testing.T) {
   SELECT id, email, password, name, user_image_uri, company_name, company_image_uri, created_at, updated_at, deleted_at
   FROM managers`
 
-	mockDB.On("Query", query).Return(nil, errors.New("Failed to execute query"))
+	mockDB.On("QueryContext", mock.Anything, query).Return(nil, errors.New("Failed to execute query"))
 
-	managers, err := repo.GetAll()
+	managers, err := repo.GetAll(context.Background())
 
 	utils.Error(t, err)
 	assert.Nil(t, managers)
@@ -379,9 +384,9 @@ func TestManagerRepository_GetByID_Success(t *testing.T) {
   FROM managers 
   WHERE id = $1`
 
-	mockDB.On("QueryRow", query, manager.ID).Return(mockRow)
+	mockDB.On("QueryRowContext", mock.Anything, query, manager.ID).Return(mockRow)
 
-	actualManager, err := repo.GetByID(manager.ID)
+	actualManager, err := repo.GetByID(context.Background(), manager.ID)
 
 	utils.NoError(t, err)
 	assert.Equal(t, manager, actualManager)
@@ -419,9 +424,9 @@ func TestManagerRepository_GetByID_DatabaseError(t *testing.T) {
   FROM managers 
   WHERE id = $1`
 
-	mockDB.On("QueryRow", query, id).Return(mockRow)
+	mockDB.On("QueryRowContext", mock.Anything, query, id).Return(mockRow)
 
-	actualManager, err := repo.GetByID(id)
+	actualManager, err := repo.GetByID(context.Background(), id)
 
 	utils.Error(t, err)
 	assert.Nil(t, actualManager)
@@ -481,9 +486,9 @@ func TestManagerRepository_GetByEmail_Success(t *testing.T) {
   FROM managers 
   WHERE email = $1`
 
-	mockDB.On("QueryRow", query, manager.Email).Return(mockRow)
+	mockDB.On("QueryRowContext", mock.Anything, query, manager.Email).Return(mockRow)
 
-	actualManager, err := repo.GetByEmail(manager.Email)
+	actualManager, err := repo.GetByEmail(context.Background(), manager.Email)
 
 	utils.NoError(t, err)
 	assert.Equal(t, manager, actualManager)
@@ -521,9 +526,9 @@ func TestManagerRepository_GetByEmail_DatabaseError(t *testing.T) {
   FROM managers 
   WHERE email = $1`
 
-	mockDB.On("QueryRow", query, email).Return(mockRow)
+	mockDB.On("QueryRowContext", mock.Anything, query, email).Return(mockRow)
 
-	actualManager, err := repo.GetByEmail(email)
+	actualManager, err := repo.GetByEmail(context.Background(), email)
 
 	utils.Error(t, err)
 	assert.Nil(t, actualManager)
@@ -533,6 +538,28 @@ func TestManagerRepository_GetByEmail_DatabaseError(t *testing.T) {
 	mockEncrypt.AssertExpectations(t)
 }
 
+func TestManagerRepository_GetByEmail_NotFound(t *testing.T) {
+	mockDB := &mocksDatabase.DB{}
+	mockRow := &mocksDatabase.Row{}
+	mockEncrypt := &mocksUtils.Encryption{}
+
+	repo := repository.NewManagerRepository(mockDB, mockEncrypt.GenerateFromPassword)
+
+	email := "test1@example.com"
+
+	mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+	mockDB.On("QueryRowContext", mock.Anything, mock.Anything, email).Return(mockRow)
+
+	actualManager, err := repo.GetByEmail(context.Background(), email)
+
+	assert.ErrorIs(t, err, models.ErrManagerNotFound)
+	assert.Nil(t, actualManager)
+
+	mockRow.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
 func TestManagerRepository_Update_Success(t *testing.T) {
 	mockDB := &mocksDatabase.DB{}
 	mockEncrypt := &mocksUtils.Encryption{}
@@ -554,7 +581,7 @@ func TestManagerRepository_Update_Success(t *testing.T) {
 
 	mockEncrypt.On("GenerateFromPassword", []byte(*manager.Password), bcrypt.DefaultCost).Return(hashedPassword, nil)
 
-	mockDB.On("Exec",
+	mockDB.On("ExecContext",
 		query,
 		mock.AnythingOfType("string"),
 		mock.AnythingOfType("[]uint8"),
@@ -566,7 +593,7 @@ func TestManagerRepository_Update_Success(t *testing.T) {
 		mock.AnythingOfType("int"),
 	).Return(nil, nil)
 
-	err := repo.Update(manager)
+	err := repo.Update(context.Background(), manager)
 
 	utils.NoError(t, err)
 
@@ -590,14 +617,14 @@ func TestManagerRepository_Update_NoField(t *testing.T) {
 		CompanyImageUri: nil,
 	}
 
-	mockDB.On("Exec",
+	mockDB.On("ExecContext",
 		`UPDATE managers SET email = $1, updated_at = $2 WHERE id = $3`,
 		mock.AnythingOfType("string"),
 		mock.AnythingOfType("time.Time"),
 		mock.AnythingOfType("int"),
 	).Return(nil, nil)
 
-	err := repo.Update(manager)
+	err := repo.Update(context.Background(), manager)
 
 	utils.NoError(t, err)
 
@@ -626,7 +653,7 @@ func TestManagerRepository_Update_ExecError(t *testing.T) {
 
 	mockEncrypt.On("GenerateFromPassword", []byte(*manager.Password), bcrypt.DefaultCost).Return(hashedPassword, nil)
 
-	mockDB.On("Exec",
+	mockDB.On("ExecContext",
 		query,
 		mock.AnythingOfType("string"),
 		mock.AnythingOfType("[]uint8"),
@@ -638,7 +665,7 @@ func TestManagerRepository_Update_ExecError(t *testing.T) {
 		mock.AnythingOfType("int"),
 	).Return(nil, errors.New("Database error"))
 
-	err := repo.Update(manager)
+	err := repo.Update(context.Background(), manager)
 
 	utils.Error(t, err)
 
@@ -662,7 +689,7 @@ func TestManagerRepository_Update_PartialUpdate(t *testing.T) {
 		CompanyImageUri: nil,
 	}
 
-	mockDB.On("Exec",
+	mockDB.On("ExecContext",
 		`UPDATE managers SET email = $1, name = $2, updated_at = $3 WHERE id = $4`,
 		mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"),
@@ -670,7 +697,7 @@ func TestManagerRepository_Update_PartialUpdate(t *testing.T) {
 		mock.AnythingOfType("int"),
 	).Return(nil, errors.New("Database error"))
 
-	err := repo.Update(manager)
+	err := repo.Update(context.Background(), manager)
 
 	utils.Error(t, err)
 
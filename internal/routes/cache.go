@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/cache"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+)
+
+// Caches are the caches of the lookups run on most requests, kept in Store
+// for TTL. The zero value caches nothing.
+type Caches struct {
+	Store cache.Store
+	TTL   time.Duration
+}
+
+func NewCaches(cfg config.CacheConfig, store cache.Store) Caches {
+	return Caches{Store: store, TTL: cfg.TTL}
+}
+
+// ManagerProfiles caches the managers read by GET /v1/user
+func (c Caches) ManagerProfiles() *cache.Cache[models.Manager] {
+	return cache.New[models.Manager](c.Store, "manager_profile", c.TTL)
+}
+
+// Departments caches the departments read to check their owner
+func (c Caches) Departments() *cache.Cache[models.Department] {
+	return cache.New[models.Department](c.Store, "department", c.TTL)
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/idempotency"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+)
+
+// Idempotency keeps the responses of the POST requests carrying an
+// Idempotency-Key in Store for TTL, see middleware.IdempotencyMiddleware.
+// The zero value keeps nothing.
+type Idempotency struct {
+	Store       idempotency.Store
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+func NewIdempotency(cfg config.IdempotencyConfig, store idempotency.Store) Idempotency {
+	return Idempotency{Store: store, TTL: cfg.TTL, LockTimeout: cfg.LockTimeout}
+}
+
+// ReplayRetries replays the responses to the retries of the protected
+// group, inside Authenticated. Without a store or a TTL it lets every
+// request through.
+func (i Idempotency) ReplayRetries() Middleware {
+	return func(next http.Handler) http.Handler {
+		if i.Store == nil || i.TTL <= 0 {
+			return next
+		}
+		return middleware.IdempotencyMiddleware(i.Store, i.TTL, i.LockTimeout, next)
+	}
+}
@@ -0,0 +1,222 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/handlers"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/health"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/openapi"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+const (
+	OpenAPIPath   = "/openapi.json"
+	SwaggerUIPath = "/docs/"
+)
+
+func DocsRouter(mux Mux) {
+	mux.Handle(http.MethodGet+" "+OpenAPIPath, openapi.Handler(OpenAPIDocument()))
+	mux.Handle(http.MethodGet+" "+SwaggerUIPath, openapi.SwaggerUIHandler(OpenAPIPath))
+}
+
+// OpenAPIDocument describes every route registered by RegisterRoutes
+func OpenAPIDocument() *openapi.Document {
+	builder := openapi.Builder{
+		Info: openapi.Info{
+			Title:       "GoGoManager API",
+			Description: "Manage the employees, departments, leave and attendance of a company.",
+			Version:     "1.0.0",
+		},
+		ErrorBody: utils.ErrorResponse{},
+		Types: map[reflect.Type]openapi.Schema{
+			reflect.TypeOf(models.Date{}):              {Type: "string", Format: "date"},
+			reflect.TypeOf(models.CustomFieldValues{}): {Type: "object", Description: "Values of the custom fields, keyed by custom field key"},
+		},
+	}
+
+	return builder.Build(idempotentPosts(APIRoutes))
+}
+
+// idempotentPosts documents the Idempotency-Key header on every protected
+// POST route, see Idempotency
+func idempotentPosts(routes []openapi.Route) []openapi.Route {
+	documented := make([]openapi.Route, len(routes))
+	for i, route := range routes {
+		if route.Method == http.MethodPost && !route.Public {
+			route.Headers = append(slices.Clip(route.Headers), idempotencyKeyHeader)
+		}
+		documented[i] = route
+	}
+	return documented
+}
+
+func query(name string, schemaType string, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: schemaType}}
+}
+
+func header(name string, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "header", Description: description, Schema: &openapi.Schema{Type: "string"}}
+}
+
+var (
+	limitParam        = query("limit", "integer", "Maximum number of items to return")
+	offsetParam       = query("offset", "integer", "Number of items to skip")
+	departmentIDParam = query("departmentId", "integer", "Only include the given department")
+
+	// The ETag of a response is sent back to revalidate it or to update the
+	// version it describes
+	ifNoneMatchHeader = []openapi.Parameter{header("If-None-Match", "ETag of the response the client holds, answered with 304 Not Modified while still current")}
+	ifMatchHeader     = []openapi.Parameter{header("If-Match", "ETag of the version to update, answered with 412 Precondition Failed once another update went through")}
+
+	idempotencyKeyHeader = header("Idempotency-Key", "Unique key of the request, retries sending it with the same body get the first response replayed")
+)
+
+var employeeFilterParams = []openapi.Parameter{
+	limitParam,
+	offsetParam,
+	query("identityNumber", "string", "Filter by identity number prefix"),
+	query("gender", "string", "Filter by gender, male or female"),
+	departmentIDParam,
+	query("status", "string", "Comma separated employment statuses"),
+}
+
+// APIRoutes documents every operation of the API. Each pattern registered by
+// RegisterRoutes must be documented here, which the routes tests enforce.
+var APIRoutes = []openapi.Route{
+	// Authentication and the current manager
+	{Method: http.MethodPost, Path: "/v1/auth", Tag: "auth", Public: true,
+		Summary: "Register (action create) or log in (action login) a manager",
+		Request: utils.Credential{}, Response: utils.AuthResponse{}},
+	{Method: http.MethodGet, Path: "/v1/protected", Tag: "auth",
+		Summary: "Return the id and email of the authenticated manager", Status: http.StatusCreated,
+		Response: map[string]interface{}{}},
+	{Method: http.MethodGet, Path: "/v1/user", Tag: "manager",
+		Summary: "Get the profile of the current manager", Response: utils.ManagerResponse{}, Headers: ifNoneMatchHeader},
+	{Method: http.MethodPatch, Path: "/v1/user", Tag: "manager",
+		Summary: "Update the profile of the current manager", Request: utils.ManagerRequest{}, Response: utils.ManagerResponse{},
+		Headers: ifMatchHeader},
+
+	// Employees
+	{Method: http.MethodGet, Path: "/v1/employee", Tag: "employee",
+		Summary: "List employees", Query: employeeFilterParams, Response: []handlers.EmployeeResponse{}, Envelope: true,
+		Headers: ifNoneMatchHeader},
+	{Method: http.MethodPost, Path: "/v1/employee", Tag: "employee",
+		Summary: "Create an employee", Request: models.CreateEmployeeRequest{}, Response: handlers.EmployeeResponse{}, Envelope: true,
+		Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/v1/employee/export", Tag: "employee",
+		Summary: "Export employees as CSV", Query: employeeFilterParams, ContentType: "text/csv"},
+	{Method: http.MethodPatch, Path: "/v1/employee/bulk", Tag: "employee",
+		Summary: "Move employees to another department", Request: models.BulkMoveRequest{}, Response: models.BulkResult{}, Envelope: true},
+	{Method: http.MethodDelete, Path: "/v1/employee/bulk", Tag: "employee",
+		Summary: "Delete employees", Request: models.BulkEmployeeRequest{}, Response: models.BulkResult{}, Envelope: true},
+	{Method: http.MethodPatch, Path: "/v1/employee/{identityNumber}", Tag: "employee",
+		Summary: "Update an employee", Request: models.UpdateEmployeeRequest{}, Response: handlers.EmployeeResponse{}, Envelope: true,
+		Headers: ifMatchHeader},
+	{Method: http.MethodDelete, Path: "/v1/employee/{identityNumber}", Tag: "employee",
+		Summary: "Delete an employee", Envelope: true},
+	{Method: http.MethodGet, Path: "/v1/employee/{identityNumber}/status", Tag: "employee",
+		Summary: "List the employment status changes of an employee", Response: []models.EmploymentEvent{}, Envelope: true},
+	{Method: http.MethodPost, Path: "/v1/employee/{identityNumber}/status", Tag: "employee",
+		Summary: "Change the employment status of an employee", Request: models.ChangeStatusRequest{}, Response: handlers.EmployeeResponse{}, Envelope: true},
+
+	// Custom fields
+	{Method: http.MethodGet, Path: "/v1/custom-field", Tag: "custom-field",
+		Summary: "List custom fields", Response: []models.CustomField{}, Envelope: true},
+	{Method: http.MethodPost, Path: "/v1/custom-field", Tag: "custom-field",
+		Summary: "Create a custom field", Request: models.CreateCustomFieldRequest{}, Response: models.CustomField{}, Envelope: true,
+		Status: http.StatusCreated},
+	{Method: http.MethodDelete, Path: "/v1/custom-field/{key}", Tag: "custom-field",
+		Summary: "Delete a custom field", Envelope: true},
+
+	// Departments
+	{Method: http.MethodGet, Path: "/v1/department", Tag: "department",
+		Summary: "List departments", Response: []services.DepartmentResponse{},
+		Query: []openapi.Parameter{limitParam, offsetParam, query("name", "string", "Filter by name")}, Headers: ifNoneMatchHeader},
+	{Method: http.MethodPost, Path: "/v1/department", Tag: "department",
+		Summary: "Create a department", Request: models.CreateDepartmentRequest{}, Response: services.DepartmentResponse{},
+		Status: http.StatusCreated},
+	{Method: http.MethodPatch, Path: "/v1/department/{departmentId}", Tag: "department",
+		Summary: "Update a department", Request: models.UpdateDepartmentRequest{}, Response: services.DepartmentResponse{},
+		Headers: ifMatchHeader},
+	{Method: http.MethodDelete, Path: "/v1/department/{departmentId}", Tag: "department",
+		Summary: "Delete a department", Response: map[string]string{}},
+	{Method: http.MethodPost, Path: "/v1/department/merge", Tag: "department",
+		Summary: "Merge departments into a target department", Request: models.MergeDepartmentsRequest{},
+		Response: services.MergeDepartmentsResponse{}},
+	{Method: http.MethodGet, Path: "/v1/department/transfer", Tag: "department",
+		Summary: "List department transfers proposed or received", Response: []models.DepartmentTransfer{}},
+	{Method: http.MethodPost, Path: "/v1/department/transfer", Tag: "department",
+		Summary: "Propose to transfer a department to another manager", Request: models.CreateDepartmentTransferRequest{},
+		Response: models.DepartmentTransfer{}, Status: http.StatusCreated},
+	{Method: http.MethodPost, Path: "/v1/department/transfer/{id}/accept", Tag: "department",
+		Summary: "Accept a department transfer", Response: models.DepartmentTransfer{}},
+	{Method: http.MethodPost, Path: "/v1/department/transfer/{id}/reject", Tag: "department",
+		Summary: "Reject a department transfer", Response: models.DepartmentTransfer{}},
+	{Method: http.MethodPost, Path: "/v1/department/transfer/{id}/cancel", Tag: "department",
+		Summary: "Cancel a department transfer", Response: models.DepartmentTransfer{}},
+
+	// Leave
+	{Method: http.MethodGet, Path: "/v1/leave", Tag: "leave",
+		Summary: "List leave requests", Response: []models.LeaveRequest{}, Envelope: true,
+		Query: []openapi.Parameter{limitParam, offsetParam, query("identityNumber", "string", "Filter by employee"),
+			query("status", "string", "Filter by status")}},
+	{Method: http.MethodPost, Path: "/v1/leave", Tag: "leave",
+		Summary: "Request leave for an employee", Request: models.CreateLeaveRequest{}, Response: models.LeaveRequest{}, Envelope: true,
+		Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/v1/leave/balance", Tag: "leave",
+		Summary: "Get the leave balances of an employee", Response: []models.LeaveBalance{}, Envelope: true,
+		Query: []openapi.Parameter{{Name: "identityNumber", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+			query("year", "integer", "Year of the balances, the current year by default")}},
+	{Method: http.MethodGet, Path: "/v1/leave/calendar", Tag: "leave",
+		Summary: "List the approved leave of the team in a period", Response: []models.LeaveCalendarEntry{}, Envelope: true,
+		Query: []openapi.Parameter{query("from", "string", "First day, YYYY-MM-DD"), query("to", "string", "Last day, YYYY-MM-DD"),
+			departmentIDParam}},
+	{Method: http.MethodPost, Path: "/v1/leave/{id}/approve", Tag: "leave",
+		Summary: "Approve a leave request", Request: models.LeaveDecisionRequest{}, Response: models.LeaveRequest{}, Envelope: true},
+	{Method: http.MethodPost, Path: "/v1/leave/{id}/reject", Tag: "leave",
+		Summary: "Reject a leave request", Request: models.LeaveDecisionRequest{}, Response: models.LeaveRequest{}, Envelope: true},
+	{Method: http.MethodPost, Path: "/v1/leave/{id}/cancel", Tag: "leave",
+		Summary: "Cancel a leave request", Request: models.LeaveDecisionRequest{}, Response: models.LeaveRequest{}, Envelope: true},
+
+	// Attendance
+	{Method: http.MethodGet, Path: "/v1/attendance", Tag: "attendance",
+		Summary: "Get the attendance of the team on one day", Response: []models.DailyAttendance{}, Envelope: true,
+		Query: []openapi.Parameter{query("date", "string", "Day, YYYY-MM-DD, today by default"), departmentIDParam}},
+	{Method: http.MethodPost, Path: "/v1/attendance/check-in", Tag: "attendance",
+		Summary: "Check an employee in", Request: models.AttendanceRequest{}, Response: models.AttendanceRecord{}, Envelope: true},
+	{Method: http.MethodPost, Path: "/v1/attendance/check-out", Tag: "attendance",
+		Summary: "Check an employee out", Request: models.AttendanceRequest{}, Response: models.AttendanceRecord{}, Envelope: true},
+	{Method: http.MethodGet, Path: "/v1/attendance/report", Tag: "attendance",
+		Summary: "Get the monthly attendance report", Response: models.AttendanceReport{}, Envelope: true,
+		Query: []openapi.Parameter{query("month", "string", "Month, YYYY-MM, the current month by default"), departmentIDParam}},
+	{Method: http.MethodGet, Path: "/v1/attendance/schedule/{departmentId}", Tag: "attendance",
+		Summary: "Get the work schedule of a department", Response: models.WorkSchedule{}, Envelope: true},
+	{Method: http.MethodPut, Path: "/v1/attendance/schedule/{departmentId}", Tag: "attendance",
+		Summary: "Set the work schedule of a department", Request: models.WorkSchedule{}, Response: models.WorkSchedule{}, Envelope: true},
+
+	// Files
+	{Method: http.MethodPost, Path: "/v1/file", Tag: "file",
+		Summary:  fmt.Sprintf("Upload a file of at most %dKiB, sent in the file field of a multipart form", models.MaxFileBytes>>10),
+		Response: models.File{}},
+	{Method: http.MethodGet, Path: FilesPath + "{name}", Tag: "file", Public: true,
+		Summary: "Download an uploaded file", ContentType: "application/octet-stream"},
+
+	// Documentation
+	{Method: http.MethodGet, Path: OpenAPIPath, Tag: "docs", Public: true,
+		Summary: "This OpenAPI document", Response: map[string]interface{}{}},
+	{Method: http.MethodGet, Path: SwaggerUIPath, Tag: "docs", Public: true,
+		Summary: "Swagger UI rendering this document", ContentType: "text/html"},
+
+	// Monitoring
+	{Method: http.MethodGet, Path: MetricsPath, Tag: "monitoring", Public: true,
+		Summary: "Prometheus metrics of the requests, database pool, auth and business gauges", ContentType: "text/plain"},
+	{Method: http.MethodGet, Path: HealthPath, Tag: "monitoring", Public: true,
+		Summary: "Liveness probe, ok while the process serves", Response: health.Report{}},
+	{Method: http.MethodGet, Path: ReadyPath, Tag: "monitoring", Public: true,
+		Summary: "Readiness probe of the database, migrations and background workers, 503 when failing or shutting down", Response: health.Report{}},
+}
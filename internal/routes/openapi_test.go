@@ -0,0 +1,93 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/health"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/routes"
+)
+
+// recordingMux records the patterns registered by the routers
+type recordingMux struct {
+	patterns []string
+}
+
+func (m *recordingMux) Handle(pattern string, handler http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+}
+
+func TestAPIRoutes_DocumentEveryRegisteredRoute(t *testing.T) {
+	mux := &recordingMux{}
+	routes.RegisterRoutes(mux, &config.Config{}, nil, health.NewChecker(), routes.RateLimits{}, routes.Caches{}, routes.Idempotency{})
+
+	documented := make(map[string]bool)
+	for _, route := range routes.APIRoutes {
+		documented[route.Pattern()] = true
+	}
+
+	registered := make(map[string]bool)
+	for _, pattern := range mux.patterns {
+		registered[pattern] = true
+		assert.True(t, documented[pattern], "route %s is registered without an OpenAPI entry in routes.APIRoutes", pattern)
+	}
+
+	for _, route := range routes.APIRoutes {
+		assert.True(t, registered[route.Pattern()], "OpenAPI entry %s is not served by any registered route", route.Pattern())
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	doc := routes.OpenAPIDocument()
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	for _, name := range []string{"EmployeeResponse", "DepartmentResponse", "ManagerResponse", "AuthResponse", "ErrorResponse"} {
+		assert.Contains(t, doc.Components.Schemas, name)
+	}
+
+	create := doc.Paths["/v1/employee"]["post"]
+	if assert.NotNil(t, create) {
+		assert.Contains(t, create.Responses, "201")
+		assert.Contains(t, create.Responses, "default")
+		assert.Equal(t, "#/components/schemas/CreateEmployeeRequest", create.RequestBody.Content["application/json"].Schema.Ref)
+		if assert.Len(t, create.Parameters, 1) {
+			assert.Equal(t, "Idempotency-Key", create.Parameters[0].Name)
+			assert.Equal(t, "header", create.Parameters[0].In)
+		}
+	}
+	assert.Empty(t, doc.Paths["/v1/auth"]["post"].Parameters)
+
+	request := doc.Components.Schemas["CreateEmployeeRequest"]
+	assert.ElementsMatch(t, []string{"identityNumber", "name", "employeeImageUri", "gender", "departmentId"}, request.Required)
+	assert.Equal(t, []string{"male", "female"}, request.Properties["gender"].Enum)
+	assert.Equal(t, 33, *request.Properties["name"].MaxLength)
+
+	update := doc.Paths["/v1/employee/{identityNumber}"]["patch"]
+	if assert.NotNil(t, update) {
+		assert.Equal(t, "identityNumber", update.Parameters[0].Name)
+		assert.Equal(t, "path", update.Parameters[0].In)
+	}
+}
+
+func TestDocsRouter(t *testing.T) {
+	mux := http.NewServeMux()
+	routes.DocsRouter(mux)
+
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, httptest.NewRequest(http.MethodGet, routes.OpenAPIPath, nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &doc))
+	assert.Contains(t, doc, "paths")
+
+	res = httptest.NewRecorder()
+	mux.ServeHTTP(res, httptest.NewRequest(http.MethodGet, routes.SwaggerUIPath, nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), "swagger-ui")
+}
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/ratelimit"
+)
+
+// RateLimits are the limits of the route groups, kept in Store. The zero
+// value limits nothing.
+type RateLimits struct {
+	Store ratelimit.Store
+	// Auth limits /v1/auth by client IP
+	Auth ratelimit.Limit
+	// API limits the protected routes by manager
+	API               ratelimit.Limit
+	TrustForwardedFor bool
+}
+
+func NewRateLimits(cfg config.RateLimitConfig, store ratelimit.Store) RateLimits {
+	return RateLimits{
+		Store:             store,
+		Auth:              ratelimit.Limit{Rate: cfg.AuthRate, Burst: cfg.AuthBurst},
+		API:               ratelimit.Limit{Rate: cfg.APIRate, Burst: cfg.APIBurst},
+		TrustForwardedFor: cfg.TrustForwardedFor,
+	}
+}
+
+// RateLimited limits the requests of a group, the buckets of name being
+// keyed by key, see middleware.RateLimitMiddleware. Without a store or a
+// limit it lets every request through.
+func RateLimited(store ratelimit.Store, name string, limit ratelimit.Limit, key middleware.RateLimitKey) Middleware {
+	return func(next http.Handler) http.Handler {
+		if store == nil || limit.Rate <= 0 || limit.Burst <= 0 {
+			return next
+		}
+		return middleware.RateLimitMiddleware(store, name, limit, key, next)
+	}
+}
+
+// ByClientIP limits the auth group
+func (l RateLimits) ByClientIP() Middleware {
+	return RateLimited(l.Store, "auth", l.Auth, middleware.ByClientIP(l.TrustForwardedFor))
+}
+
+// ByManager limits the protected group, inside Authenticated
+func (l RateLimits) ByManager() Middleware {
+	return RateLimited(l.Store, "api", l.API, middleware.ByManager)
+}
@@ -1,97 +1,262 @@
 package routes
 
 import (
-	"database/sql"
+	"log/slog"
 	"net/http"
-	"strings"
+	"strconv"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/ngikut-project-sprint/GoGoManager/internal/cache"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/handlers"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/health"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/validators"
 )
 
-func NewRouter(cfg *config.Config, db *sql.DB) *http.ServeMux {
-	mux := http.NewServeMux()
-	ManagerRouter(mux, cfg, db)
-	DepartmentRouter(mux, cfg, db)
-	EmployeeRouter(mux, cfg, db)
+// Mux is where the routers register their handlers, an *http.ServeMux.
+// Patterns carry the method, e.g. "GET /v1/employee".
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+func NewRouter(cfg *config.Config, db database.DB, checker *health.Checker, limits RateLimits, caches Caches, idempotent Idempotency) *Router {
+	mux := NewMux()
+	RegisterRoutes(mux, cfg, db, checker, limits, caches, idempotent)
 	return mux
 }
-func ManagerRouter(mux *http.ServeMux, cfg *config.Config, db *sql.DB) {
-	dbAdapter := &database.SqlDBAdapter{DB: db}
-	repo := repository.NewManagerRepository(dbAdapter, bcrypt.GenerateFromPassword)
+
+// NewHandler puts router behind the middleware every request goes through,
+// outermost first. Tracing and metrics wrap the router, which sets the
+// matched route on the request they pass it.
+func NewHandler(router http.Handler, cfg *config.Config, logger *slog.Logger) http.Handler {
+	return Chain(router,
+		middleware.RequestIDMiddleware,
+		func(next http.Handler) http.Handler { return middleware.LoggerMiddleware(logger, next) },
+		middleware.SecurityHeadersMiddleware,
+		func(next http.Handler) http.Handler { return middleware.CORSMiddleware(cfg.CORS, next) },
+		func(next http.Handler) http.Handler { return middleware.MaxBodyMiddleware(cfg.Server.MaxBodyBytes, next) },
+		middleware.TracingMiddleware,
+		middleware.MetricsMiddleware,
+	)
+}
+
+// Chain wraps handler in middleware, the first one outermost
+func Chain(handler http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// RegisterRoutes registers every route of the API on mux. Routes of the
+// public group only get the configuration, the auth group is rate limited by
+// client IP and the protected group requires a bearer token, is rate
+// limited by manager and replays the retries of its POST requests sent with
+// an Idempotency-Key. checker runs the readiness checks.
+//
+// Every repository shares db, so a transaction started by one of them is
+// joined by the others. The manager profiles and departments are cached in
+// caches.
+func RegisterRoutes(mux Mux, cfg *config.Config, db database.DB, checker *health.Checker, limits RateLimits, caches Caches, idempotent Idempotency) {
+	public := NewGroup(mux, APIPrefix, WithConfig(cfg))
+	auth := public.Group("", limits.ByClientIP())
+	protected := public.Group("", Authenticated, limits.ByManager(), idempotent.ReplayRetries())
+
+	ManagerRouter(auth, protected, db, caches)
+	DepartmentRouter(protected, db, caches)
+	EmployeeRouter(protected, db)
+	CustomFieldRouter(protected, db)
+	LeaveRouter(protected, db)
+	AttendanceRouter(protected, db)
+	FileRouter(mux, protected, cfg.Files)
+	DocsRouter(mux)
+	MetricsRouter(mux)
+	HealthRouter(mux, checker)
+}
+
+// FilesPath serves the uploaded files, outside of the versioned API so
+// their URIs don't change with it
+const FilesPath = "/files/"
+
+func FileRouter(mux Mux, protected *Group, cfg config.FileConfig) {
+	repo := repository.NewDiskFileRepository(cfg.Dir)
+	service := services.NewFileService(repo, cfg.BaseURL+FilesPath)
+	handler := handlers.NewFileHandler(service)
+
+	protected.HandleFunc(http.MethodPost, "/file", handler.Upload)
+	mux.Handle(http.MethodGet+" "+FilesPath+"{name}", http.StripPrefix(FilesPath, http.FileServer(http.Dir(cfg.Dir))))
+}
+
+// MetricsPath serves the Prometheus metrics, outside of the versioned API
+const MetricsPath = "/metrics"
+
+func MetricsRouter(mux Mux) {
+	mux.Handle(http.MethodGet+" "+MetricsPath, promhttp.Handler())
+}
+
+// HealthPath and ReadyPath serve the liveness and readiness probes, outside
+// of the versioned API
+const (
+	HealthPath = "/healthz"
+	ReadyPath  = "/readyz"
+)
+
+func HealthRouter(mux Mux, checker *health.Checker) {
+	handler := handlers.NewHealthHandler(checker)
+	mux.Handle(http.MethodGet+" "+HealthPath, http.HandlerFunc(handler.Live))
+	mux.Handle(http.MethodGet+" "+ReadyPath, http.HandlerFunc(handler.Ready))
+}
+
+// pathID reads the integer path parameter name, answering 400 with message
+// when it isn't a number
+func pathID(w http.ResponseWriter, r *http.Request, name string, message string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		utils.SendErrorResponse(w, message, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// withPathID adapts a handler taking an integer path parameter
+func withPathID(name string, message string, handle func(http.ResponseWriter, *http.Request, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := pathID(w, r, name, message); ok {
+			handle(w, r, id)
+		}
+	}
+}
+
+// withPathValue adapts a handler taking a string path parameter
+func withPathValue(name string, handle func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, r.PathValue(name))
+	}
+}
+
+func ManagerRouter(auth *Group, protected *Group, db database.DB, caches Caches) {
+	repo := repository.NewCachedManagerRepository(
+		repository.NewManagerRepository(db, bcrypt.GenerateFromPassword),
+		caches.ManagerProfiles(),
+	)
 	service := services.NewManagerService(repo, validators.ValidateEmail, validators.ValidatePassword)
-	AuthRouter(mux, cfg, service)
-	ManagersRouter(mux, cfg, service)
+	AuthRouter(auth, protected, service)
+	ManagersRouter(protected, service)
 }
 
-func ManagersRouter(mux *http.ServeMux, cfg *config.Config, manager_service services.ManagerService) {
+func ManagersRouter(protected *Group, manager_service services.ManagerService) {
 	handler := handlers.NewManagerHandler(manager_service)
-	mux.Handle("/v1/user", middleware.ConfigMiddleware(cfg, middleware.AuthMiddleware(jwt.ParseWithClaims, http.HandlerFunc(handler.Manager))))
+	protected.HandleFunc(http.MethodGet, "/user", handler.GetUser)
+	protected.HandleFunc(http.MethodPatch, "/user", handler.UpdateUser)
 }
 
-func EmployeeRouter(mux *http.ServeMux, cfg *config.Config, db *sql.DB) {
+func EmployeeRouter(protected *Group, db database.DB) {
 	repo := repository.NewEmployeeRepository(db)
-	service := services.NewEmployeeService(repo)
+	fieldRepo := repository.NewCustomFieldRepository(db)
+	service := services.NewEmployeeService(repo, fieldRepo, db)
 	handler := handlers.NewEmployeeHandler(service)
 
-	// Handle /v1/employee for GET (list) and POST (create)
-	mux.Handle("/v1/employee", middleware.ConfigMiddleware(cfg,
-		middleware.AuthMiddleware(jwt.ParseWithClaims, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			switch r.Method {
-			case http.MethodGet:
-				handler.List(w, r)
-			case http.MethodPost:
-				handler.Create(w, r)
-			default:
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		})),
-	))
-
-	// Handle /v1/employee/{identityNumber} for PATCH and DELETE
-	mux.Handle("/v1/employee/", middleware.ConfigMiddleware(cfg,
-		middleware.AuthMiddleware(jwt.ParseWithClaims, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Extract identityNumber from path
-			identityNumber := strings.TrimPrefix(r.URL.Path, "/v1/employee/")
-			if identityNumber == "" {
-				http.Error(w, "Missing employee identity number", http.StatusBadRequest)
-				return
-			}
-
-			switch r.Method {
-			case http.MethodPatch:
-				handler.Update(w, r, identityNumber)
-			case http.MethodDelete:
-				handler.Delete(w, r, identityNumber)
-			default:
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		})),
-	))
-}
-
-func AuthRouter(mux *http.ServeMux, cfg *config.Config, manager_service services.ManagerService) {
+	employees := protected.Group("/employee")
+	employees.HandleFunc(http.MethodGet, "", handler.List)
+	employees.HandleFunc(http.MethodPost, "", handler.Create)
+	employees.HandleFunc(http.MethodGet, "/export", handler.Export)
+	employees.HandleFunc(http.MethodPatch, "/bulk", handler.BulkMove)
+	employees.HandleFunc(http.MethodDelete, "/bulk", handler.BulkDelete)
+	employees.HandleFunc(http.MethodPatch, "/{identityNumber}", withPathValue("identityNumber", handler.Update))
+	employees.HandleFunc(http.MethodDelete, "/{identityNumber}", withPathValue("identityNumber", handler.Delete))
+	employees.HandleFunc(http.MethodGet, "/{identityNumber}/status", withPathValue("identityNumber", handler.StatusHistory))
+	employees.HandleFunc(http.MethodPost, "/{identityNumber}/status", withPathValue("identityNumber", handler.ChangeStatus))
+}
+
+func CustomFieldRouter(protected *Group, db database.DB) {
+	repo := repository.NewCustomFieldRepository(db)
+	service := services.NewCustomFieldService(repo)
+	handler := handlers.NewCustomFieldHandler(service)
+
+	fields := protected.Group("/custom-field")
+	fields.HandleFunc(http.MethodGet, "", handler.List)
+	fields.HandleFunc(http.MethodPost, "", handler.Create)
+	fields.HandleFunc(http.MethodDelete, "/{key}", withPathValue("key", handler.Delete))
+}
+
+func LeaveRouter(protected *Group, db database.DB) {
+	repo := repository.NewLeaveRepository(db)
+	service := services.NewLeaveService(repo)
+	handler := handlers.NewLeaveHandler(service)
+
+	leave := protected.Group("/leave")
+	leave.HandleFunc(http.MethodGet, "", handler.List)
+	leave.HandleFunc(http.MethodPost, "", handler.Create)
+	leave.HandleFunc(http.MethodGet, "/balance", handler.Balances)
+	leave.HandleFunc(http.MethodGet, "/calendar", handler.Calendar)
+	for _, action := range []string{"approve", "reject", "cancel"} {
+		leave.HandleFunc(http.MethodPost, "/{id}/"+action, withPathID("id", "Invalid leave request id",
+			func(w http.ResponseWriter, r *http.Request, id int) {
+				handler.Decide(w, r, id, action)
+			}))
+	}
+}
+
+func AttendanceRouter(protected *Group, db database.DB) {
+	repo := repository.NewAttendanceRepository(db)
+	leaveRepo := repository.NewLeaveRepository(db)
+	service := services.NewAttendanceService(repo, leaveRepo)
+	handler := handlers.NewAttendanceHandler(service)
+
+	attendance := protected.Group("/attendance")
+	attendance.HandleFunc(http.MethodGet, "", handler.Daily)
+	attendance.HandleFunc(http.MethodPost, "/check-in", handler.CheckIn)
+	attendance.HandleFunc(http.MethodPost, "/check-out", handler.CheckOut)
+	attendance.HandleFunc(http.MethodGet, "/report", handler.Report)
+	attendance.HandleFunc(http.MethodGet, "/schedule/{departmentId}", withPathID("departmentId", "Invalid department id", handler.GetSchedule))
+	attendance.HandleFunc(http.MethodPut, "/schedule/{departmentId}", withPathID("departmentId", "Invalid department id", handler.SaveSchedule))
+}
+
+func AuthRouter(auth *Group, protected *Group, manager_service services.ManagerService) {
 	handler := handlers.NewAuthHandler(manager_service, utils.GenerateJWT, bcrypt.CompareHashAndPassword)
-	mux.Handle("/v1/auth", middleware.ConfigMiddleware(cfg, http.HandlerFunc(handler.Auth)))
-	mux.Handle("/v1/protected", middleware.ConfigMiddleware(cfg, middleware.AuthMiddleware(jwt.ParseWithClaims, http.HandlerFunc(handlers.ExampleSecureHander))))
+	auth.HandleFunc(http.MethodPost, "/auth", handler.Auth)
+	protected.HandleFunc(http.MethodGet, "/protected", handlers.ExampleSecureHander)
 }
 
-func DepartmentRouter(mux *http.ServeMux, cfg *config.Config, db *sql.DB) {
-    repo := repository.NewDepartmentRepository(db)
+func DepartmentRouter(protected *Group, db database.DB, caches Caches) {
+    // Transfers invalidate the departments they hand over
+    departmentCache := caches.Departments()
+    repo := repository.NewCachedDepartmentRepository(repository.NewDepartmentRepository(db), departmentCache)
     service := services.NewDepartmentService(repo)
     handler := handlers.NewDepartmentHandler(service)
 
-    mux.Handle("/department", middleware.ConfigMiddleware(cfg, 
-        middleware.AuthMiddleware(jwt.ParseWithClaims, http.HandlerFunc(handler.HandleDepartment))))
-    mux.Handle("/department/", middleware.ConfigMiddleware(cfg, 
-        middleware.AuthMiddleware(jwt.ParseWithClaims, http.HandlerFunc(handler.HandleDepartmentWithID))))
-}
\ No newline at end of file
+    departments := protected.Group("/department")
+    departments.HandleFunc(http.MethodGet, "", handler.ListDepartments)
+    departments.HandleFunc(http.MethodPost, "", handler.CreateDepartment)
+    departments.HandleFunc(http.MethodPatch, "/{departmentId}",
+        withPathID("departmentId", "Invalid department id", handler.UpdateDepartment))
+    departments.HandleFunc(http.MethodDelete, "/{departmentId}",
+        withPathID("departmentId", "Invalid department id", handler.DeleteDepartment))
+    departments.HandleFunc(http.MethodPost, "/merge", handler.MergeDepartments)
+
+    DepartmentTransferRouter(departments, db, departmentCache)
+}
+
+func DepartmentTransferRouter(departments *Group, db database.DB, departmentCache *cache.Cache[models.Department]) {
+	repo := repository.NewCachedDepartmentTransferRepository(repository.NewDepartmentTransferRepository(db), departmentCache)
+	service := services.NewDepartmentTransferService(repo)
+	handler := handlers.NewDepartmentTransferHandler(service)
+
+	transfers := departments.Group("/transfer")
+	transfers.HandleFunc(http.MethodGet, "", handler.List)
+	transfers.HandleFunc(http.MethodPost, "", handler.Create)
+	for _, action := range []string{"accept", "reject", "cancel"} {
+		transfers.HandleFunc(http.MethodPost, "/{id}/"+action, withPathID("id", "Invalid department transfer id",
+			func(w http.ResponseWriter, r *http.Request, id int) {
+				handler.Decide(w, r, id, action)
+			}))
+	}
+}
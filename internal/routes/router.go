@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/middleware"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// APIPrefix versions every route of the API
+const APIPrefix = "/v1"
+
+// Router is an http.ServeMux answering requests no route matches with the
+// JSON error body of the API instead of plain text
+type Router struct {
+	*http.ServeMux
+}
+
+func NewMux() *Router {
+	return &Router{ServeMux: http.NewServeMux()}
+}
+
+func (m *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, pattern := m.Handler(r)
+	if pattern != "" {
+		m.ServeMux.ServeHTTP(w, r)
+		return
+	}
+
+	// The mux answers 404, or 405 with the Allow header when only the method
+	// doesn't match, record which one before writing our own body
+	recorder := &statusRecorder{header: make(http.Header)}
+	handler.ServeHTTP(recorder, r)
+
+	if allow := recorder.header.Get("Allow"); allow != "" {
+		w.Header().Set("Allow", allow)
+	}
+	switch recorder.status {
+	case http.StatusMethodNotAllowed:
+		utils.SendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		utils.SendErrorResponse(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// statusRecorder keeps the headers and status of a response and drops its body
+type statusRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *statusRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// Middleware wraps a handler
+type Middleware func(http.Handler) http.Handler
+
+// WithConfig puts cfg in the request context, see middleware.ConfigMiddleware
+func WithConfig(cfg *config.Config) Middleware {
+	return func(next http.Handler) http.Handler {
+		return middleware.ConfigMiddleware(cfg, next)
+	}
+}
+
+// Authenticated rejects requests without a valid bearer token, see
+// middleware.AuthMiddleware
+func Authenticated(next http.Handler) http.Handler {
+	return middleware.AuthMiddleware(jwt.ParseWithClaims, next)
+}
+
+// Group registers routes under a common path prefix, wrapping every handler
+// in the middleware of the group
+type Group struct {
+	mux        Mux
+	prefix     string
+	middleware []Middleware
+}
+
+func NewGroup(mux Mux, prefix string, middleware ...Middleware) *Group {
+	return &Group{mux: mux, prefix: prefix, middleware: middleware}
+}
+
+// Group returns a sub group under prefix adding middleware after the
+// middleware of g
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		mux:        g.mux,
+		prefix:     g.prefix + prefix,
+		middleware: append(append([]Middleware{}, g.middleware...), middleware...),
+	}
+}
+
+// Handle registers handler for method on the path under the group prefix.
+// The path may hold {name} parameters, read with r.PathValue.
+func (g *Group) Handle(method string, path string, handler http.Handler) {
+	g.mux.Handle(method+" "+g.prefix+path, Chain(handler, g.middleware...))
+}
+
+func (g *Group) HandleFunc(method string, path string, handler http.HandlerFunc) {
+	g.Handle(method, path, handler)
+}
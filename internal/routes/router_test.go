@@ -0,0 +1,156 @@
+package routes_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/health"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/ratelimit"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/routes"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+func serve(handler http.Handler, method string, path string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(method, path, nil))
+	return res
+}
+
+func TestRouter_NotFound(t *testing.T) {
+	mux := routes.NewMux()
+	mux.HandleFunc("GET /v1/employee", func(w http.ResponseWriter, r *http.Request) {})
+
+	res := serve(mux, http.MethodGet, "/v1/unknown")
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	var body utils.ErrorResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, "NOT_FOUND", body.Code)
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	mux := routes.NewMux()
+	mux.HandleFunc("GET /v1/employee", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("POST /v1/employee", func(w http.ResponseWriter, r *http.Request) {})
+
+	res := serve(mux, http.MethodDelete, "/v1/employee")
+
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "GET, HEAD, POST", res.Header().Get("Allow"))
+	var body utils.ErrorResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, "METHOD_NOT_ALLOWED", body.Code)
+}
+
+func TestGroup_PrefixAndMiddleware(t *testing.T) {
+	var calls []string
+	trace := func(name string) routes.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	mux := routes.NewMux()
+	v1 := routes.NewGroup(mux, "/v1", trace("outer"))
+	v1.Group("/employee", trace("inner")).HandleFunc(http.MethodPatch, "/{identityNumber}", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler "+r.PathValue("identityNumber"))
+	})
+
+	res := serve(mux, http.MethodPatch, "/v1/employee/12345")
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, []string{"outer", "inner", "handler 12345"}, calls)
+}
+
+func TestRegisterRoutes_RequireAuthentication(t *testing.T) {
+	handler := routes.NewRouter(&config.Config{}, nil, health.NewChecker(), routes.RateLimits{}, routes.Caches{}, routes.Idempotency{})
+
+	for _, route := range routes.APIRoutes {
+		if route.Public {
+			continue
+		}
+		res := serve(handler, route.Method, route.Path)
+		assert.Equal(t, http.StatusUnauthorized, res.Code, "%s is served without a bearer token", route.Pattern())
+	}
+}
+
+func TestHealthRouter(t *testing.T) {
+	checker := health.NewChecker()
+	checker.Add("database", func(ctx context.Context) error { return nil })
+	checker.Add("workers", func(ctx context.Context) error { return errors.New("worker stopped") })
+	handler := routes.NewRouter(&config.Config{}, nil, checker, routes.RateLimits{}, routes.Caches{}, routes.Idempotency{})
+
+	res := serve(handler, http.MethodGet, routes.HealthPath)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	res = serve(handler, http.MethodGet, routes.ReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+
+	var report health.Report
+	if assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report)) {
+		assert.Equal(t, health.StatusFailing, report.Status)
+		assert.Equal(t, map[string]string{"database": health.StatusOK, "workers": health.StatusFailing}, report.Checks)
+	}
+}
+
+func TestRegisterRoutes_RateLimitAuthByClientIP(t *testing.T) {
+	handler := routes.NewRouter(&config.Config{}, nil, health.NewChecker(), routes.RateLimits{
+		Store: ratelimit.NewMemoryStore(),
+		Auth:  ratelimit.Limit{Rate: 0.1, Burst: 1},
+	}, routes.Caches{}, routes.Idempotency{})
+
+	res := serve(handler, http.MethodPost, "/v1/auth")
+	assert.NotEqual(t, http.StatusTooManyRequests, res.Code)
+	assert.Equal(t, "1", res.Header().Get("RateLimit-Limit"))
+
+	res = serve(handler, http.MethodPost, "/v1/auth")
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.Equal(t, "10", res.Header().Get("Retry-After"))
+
+	// Protected routes have their own limit, none here
+	res = serve(handler, http.MethodGet, "/v1/employee")
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Empty(t, res.Header().Get("RateLimit-Limit"))
+}
+
+func TestNewHandler(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{MaxBodyBytes: 16},
+		CORS: config.CORSConfig{
+			AllowedOrigins: []string{"https://app.example.com"},
+			AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		},
+	}
+	handler := routes.NewHandler(routes.NewRouter(cfg, nil, health.NewChecker(), routes.RateLimits{}, routes.Caches{}, routes.Idempotency{}), cfg,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Preflight requests are answered before authentication
+	req := httptest.NewRequest(http.MethodOptions, "/v1/employee", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "nosniff", res.Header().Get("X-Content-Type-Options"))
+	assert.NotEmpty(t, res.Header().Get(constants.RequestIDHeader))
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/v1/auth", strings.NewReader(`{"email": "larger than sixteen bytes"}`)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+}
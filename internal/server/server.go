@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+)
+
+// ErrShuttingDown fails the readiness of a server once it starts shutting
+// down
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Server runs the HTTP server and the background workers of the API until
+// its context is cancelled, then drains them within the shutdown timeout
+type Server struct {
+	cfg     config.ServerConfig
+	http    *http.Server
+	workers []worker
+
+	shuttingDown atomic.Bool
+	mu           sync.Mutex
+	// stopped names the workers that returned before the shutdown
+	stopped []string
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+func New(cfg config.ServerConfig, handler http.Handler) *Server {
+	return &Server{
+		cfg: cfg,
+		http: &http.Server{
+			Addr:              cfg.Address,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		},
+	}
+}
+
+// Go adds a background worker started by Run. The context of the worker is
+// cancelled on shutdown and Run waits for the worker to return. A worker
+// returning before the shutdown fails CheckWorkers, name telling which.
+func (s *Server) Go(name string, run func(ctx context.Context)) {
+	s.workers = append(s.workers, worker{name: name, run: run})
+}
+
+// CheckServing is a readiness check failing with ErrShuttingDown once the
+// server starts shutting down
+func (s *Server) CheckServing(ctx context.Context) error {
+	if s.shuttingDown.Load() {
+		return ErrShuttingDown
+	}
+	return nil
+}
+
+// CheckWorkers is a readiness check failing when a background worker has
+// stopped while the server runs
+func (s *Server) CheckWorkers(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.stopped) > 0 {
+		return fmt.Errorf("background workers stopped: %s", strings.Join(s.stopped, ", "))
+	}
+	return nil
+}
+
+// Run listens on the configured address and serves until ctx is done
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", s.cfg.Address, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves on listener until ctx is done or the server fails. On
+// shutdown it fails the readiness checks and keeps serving for
+// ShutdownDelay, so load balancers stop sending requests, then it stops
+// accepting connections, cancels the workers and waits for in-flight
+// requests and workers to finish within ShutdownTimeout.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	for _, worker := range s.workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			worker.run(workerCtx)
+			if workerCtx.Err() == nil {
+				s.mu.Lock()
+				s.stopped = append(s.stopped, worker.name)
+				s.mu.Unlock()
+			}
+		}()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(listener)
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+		// The server failed on its own, still stop the workers below
+	case <-ctx.Done():
+		s.shuttingDown.Store(true)
+		select {
+		case err = <-serveErr:
+		case <-time.After(s.cfg.ShutdownDelay):
+		}
+	}
+	s.shuttingDown.Store(true)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+
+	stopWorkers()
+	if shutdownErr := s.http.Shutdown(shutdownCtx); shutdownErr != nil {
+		err = errors.Join(err, fmt.Errorf("drain requests: %w", shutdownErr))
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-shutdownCtx.Done():
+		err = errors.Join(err, fmt.Errorf("drain workers: %w", shutdownCtx.Err()))
+	}
+
+	return err
+}
@@ -0,0 +1,138 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/server"
+)
+
+func serve(t *testing.T, srv *server.Server) (string, context.CancelFunc, <-chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, listener)
+	}()
+	return "http://" + listener.Addr().String(), cancel, done
+}
+
+func TestServer_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := server.New(config.ServerConfig{ShutdownTimeout: 5 * time.Second}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	url, cancel, done := serve(t, srv)
+
+	response := make(chan int, 1)
+	go func() {
+		res, err := http.Get(url)
+		if err != nil {
+			response <- 0
+			return
+		}
+		res.Body.Close()
+		response <- res.StatusCode
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+		t.Fatal("server stopped before the in-flight request finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-response)
+	assert.NoError(t, <-done)
+}
+
+func TestServer_StopsWorkers(t *testing.T) {
+	srv := server.New(config.ServerConfig{ShutdownTimeout: 5 * time.Second}, http.NotFoundHandler())
+
+	stopped := make(chan struct{})
+	srv.Go("test", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	_, cancel, done := serve(t, srv)
+	cancel()
+
+	assert.NoError(t, <-done)
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("server returned before the worker stopped")
+	}
+}
+
+func TestServer_ShutdownTimeout(t *testing.T) {
+	srv := server.New(config.ServerConfig{ShutdownTimeout: 10 * time.Millisecond}, http.NotFoundHandler())
+
+	block := make(chan struct{})
+	defer close(block)
+	srv.Go("test", func(ctx context.Context) {
+		<-block
+	})
+
+	_, cancel, done := serve(t, srv)
+	cancel()
+
+	err := <-done
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestServer_FailsReadinessDuringShutdown(t *testing.T) {
+	srv := server.New(config.ServerConfig{ShutdownDelay: 200 * time.Millisecond, ShutdownTimeout: time.Second}, http.NotFoundHandler())
+	_, cancel, done := serve(t, srv)
+	assert.NoError(t, srv.CheckServing(context.Background()))
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		return srv.CheckServing(context.Background()) != nil
+	}, time.Second, 5*time.Millisecond)
+	assert.ErrorIs(t, srv.CheckServing(context.Background()), server.ErrShuttingDown)
+
+	select {
+	case <-done:
+		t.Fatal("server stopped before the shutdown delay")
+	default:
+	}
+	assert.NoError(t, <-done)
+}
+
+func TestServer_CheckWorkers(t *testing.T) {
+	srv := server.New(config.ServerConfig{ShutdownTimeout: time.Second}, http.NotFoundHandler())
+
+	srv.Go("running", func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	srv.Go("crashed", func(ctx context.Context) {})
+
+	_, cancel, done := serve(t, srv)
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	assert.Eventually(t, func() bool {
+		return srv.CheckWorkers(context.Background()) != nil
+	}, time.Second, 5*time.Millisecond)
+	assert.ErrorContains(t, srv.CheckWorkers(context.Background()), "crashed")
+	assert.NotContains(t, srv.CheckWorkers(context.Background()).Error(), "running")
+}
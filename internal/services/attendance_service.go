@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// maxClockSkew is how far in the future a check-in or check-out timestamp
+// may be, to tolerate clients with a slightly fast clock
+const maxClockSkew = time.Minute
+
+const reportMonthLayout = "2006-01"
+
+var ErrInvalidAttendance = utils.NewError(utils.InvalidInput, "INVALID_ATTENDANCE", "invalid attendance request")
+
+type AttendanceService interface {
+	CheckIn(ctx context.Context, managerID int, req models.AttendanceRequest) (*models.AttendanceRecord, error)
+	CheckOut(ctx context.Context, managerID int, req models.AttendanceRequest) (*models.AttendanceRecord, error)
+	Daily(ctx context.Context, managerID int, day models.Date, departmentID *int) ([]models.DailyAttendance, error)
+	Report(ctx context.Context, managerID int, month string, departmentID *int) (*models.AttendanceReport, error)
+	Schedule(ctx context.Context, managerID int, departmentID int) (*models.WorkSchedule, error)
+	SaveSchedule(ctx context.Context, managerID int, schedule models.WorkSchedule) (*models.WorkSchedule, error)
+}
+
+type attendanceService struct {
+	repo      repository.AttendanceRepository
+	leaveRepo repository.LeaveRepository
+	now       func() time.Time
+}
+
+func NewAttendanceService(repo repository.AttendanceRepository, leaveRepo repository.LeaveRepository) AttendanceService {
+	return &attendanceService{
+		repo:      repo,
+		leaveRepo: leaveRepo,
+		now:       time.Now,
+	}
+}
+
+func (s *attendanceService) CheckIn(ctx context.Context, managerID int, req models.AttendanceRequest) (*models.AttendanceRecord, error) {
+	employee, at, err := s.prepare(ctx, managerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if employee.Status == models.Terminated {
+		return nil, fmt.Errorf("%w: employee is terminated", ErrInvalidAttendance)
+	}
+
+	return s.repo.CheckIn(ctx, employee.ID, at)
+}
+
+func (s *attendanceService) CheckOut(ctx context.Context, managerID int, req models.AttendanceRequest) (*models.AttendanceRecord, error) {
+	employee, at, err := s.prepare(ctx, managerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CheckOut(ctx, employee.ID, at)
+}
+
+// Daily returns the attendance of every employee in scope on the given day.
+func (s *attendanceService) Daily(ctx context.Context, managerID int, day models.Date, departmentID *int) ([]models.DailyAttendance, error) {
+	days, err := s.summarize(ctx, managerID, day, day, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	daily := make([]models.DailyAttendance, 0, len(days))
+	for _, employeeDays := range days {
+		daily = append(daily, employeeDays.days...)
+	}
+
+	return daily, nil
+}
+
+// Report aggregates the attendance of the given month (YYYY-MM, the current
+// month when empty) per employee. Days after today are not counted.
+func (s *attendanceService) Report(ctx context.Context, managerID int, month string, departmentID *int) (*models.AttendanceReport, error) {
+	start := models.NewDate(s.now())
+	if month != "" {
+		parsed, err := time.Parse(reportMonthLayout, month)
+		if err != nil {
+			return nil, fmt.Errorf("%w: month must be formatted as YYYY-MM", ErrInvalidAttendance)
+		}
+		start = models.NewDate(parsed)
+	}
+	start = models.NewDate(time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC))
+	end := models.NewDate(start.AddDate(0, 1, -1))
+
+	days, err := s.summarize(ctx, managerID, start, end, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.AttendanceReport{
+		Month:     start.Format(reportMonthLayout),
+		From:      start,
+		To:        end,
+		Employees: make([]models.AttendanceSummary, 0, len(days)),
+	}
+	for _, employeeDays := range days {
+		summary := models.AttendanceSummary{
+			IdentityNumber: employeeDays.employee.IdentityNumber,
+			Name:           employeeDays.employee.Name,
+			DepartmentID:   employeeDays.employee.DepartmentID,
+		}
+		for _, day := range employeeDays.days {
+			summary.Add(day)
+		}
+		report.Employees = append(report.Employees, summary)
+	}
+
+	return report, nil
+}
+
+func (s *attendanceService) Schedule(ctx context.Context, managerID int, departmentID int) (*models.WorkSchedule, error) {
+	schedules, err := s.repo.Schedules(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, ok := schedules[departmentID]
+	if !ok {
+		return nil, ErrDepartmentNotFound
+	}
+
+	return &schedule, nil
+}
+
+func (s *attendanceService) SaveSchedule(ctx context.Context, managerID int, schedule models.WorkSchedule) (*models.WorkSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAttendance, err)
+	}
+
+	return s.repo.SaveSchedule(ctx, managerID, schedule)
+}
+
+// prepare looks up the employee of an attendance request and resolves its
+// timestamp.
+func (s *attendanceService) prepare(ctx context.Context, managerID int, req models.AttendanceRequest) (*models.Employee, time.Time, error) {
+	now := s.now()
+	at := now
+	if req.Timestamp != nil {
+		at = *req.Timestamp
+	}
+
+	if at.After(now.Add(maxClockSkew)) {
+		return nil, time.Time{}, fmt.Errorf("%w: timestamp must not be in the future", ErrInvalidAttendance)
+	}
+
+	employees, err := s.repo.Employees(ctx, managerID, nil, &req.IdentityNumber)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if len(employees) == 0 {
+		return nil, time.Time{}, ErrEmployeeNotFound
+	}
+
+	return &employees[0], at, nil
+}
+
+type employeeAttendance struct {
+	employee models.Employee
+	days     []models.DailyAttendance
+}
+
+// summarize computes the daily attendance of the employees in scope for
+// every day of [from, to], skipping days outside their employment and days
+// that haven't started yet in their department's timezone.
+func (s *attendanceService) summarize(ctx context.Context, managerID int, from, to models.Date, departmentID *int) ([]employeeAttendance, error) {
+	schedules, err := s.repo.Schedules(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if departmentID != nil {
+		if _, ok := schedules[*departmentID]; !ok {
+			return nil, ErrDepartmentNotFound
+		}
+	}
+
+	employees, err := s.repo.Employees(ctx, managerID, departmentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Widen the range by a day on both ends, the local dates of the records
+	// depend on the department's timezone
+	records, err := s.repo.Records(ctx, managerID, models.AttendanceFilterOptions{
+		DepartmentID: departmentID,
+		From:         from.AddDate(0, 0, -1),
+		To:           to.AddDate(0, 0, 2),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.leaveRepo.Calendar(ctx, managerID, from, to, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordsByEmployee := make(map[int][]models.AttendanceRecord)
+	for _, record := range records {
+		recordsByEmployee[record.EmployeeID] = append(recordsByEmployee[record.EmployeeID], record)
+	}
+
+	leavesByEmployee := make(map[string][]models.LeaveCalendarEntry)
+	for _, leave := range leaves {
+		leavesByEmployee[leave.IdentityNumber] = append(leavesByEmployee[leave.IdentityNumber], leave)
+	}
+
+	now := s.now()
+	result := make([]employeeAttendance, 0, len(employees))
+	for _, employee := range employees {
+		schedule, ok := schedules[employee.DepartmentID]
+		if !ok {
+			schedule = models.DefaultWorkSchedule(employee.DepartmentID)
+		}
+		today := schedule.LocalDate(now)
+
+		recordsByDay := make(map[models.Date][]models.AttendanceRecord)
+		for _, record := range recordsByEmployee[employee.ID] {
+			day := schedule.LocalDate(record.CheckIn)
+			recordsByDay[day] = append(recordsByDay[day], record)
+		}
+
+		attendance := employeeAttendance{employee: employee}
+		for day := from; !day.After(to.Time) && !day.After(today.Time); day = models.NewDate(day.AddDate(0, 0, 1)) {
+			if employee.HireDate != nil && day.Before(employee.HireDate.Time) {
+				continue
+			}
+			if employee.TerminationDate != nil && day.After(employee.TerminationDate.Time) {
+				continue
+			}
+
+			onLeave := false
+			for _, leave := range leavesByEmployee[employee.IdentityNumber] {
+				if !day.Before(leave.StartDate.Time) && !day.After(leave.EndDate.Time) {
+					onLeave = true
+				}
+			}
+
+			summary := schedule.Summarize(day, recordsByDay[day], onLeave, now)
+			summary.IdentityNumber = employee.IdentityNumber
+			summary.Name = employee.Name
+			attendance.days = append(attendance.days, summary)
+		}
+
+		result = append(result, attendance)
+	}
+
+	return result, nil
+}
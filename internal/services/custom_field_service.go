@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+var (
+	ErrInvalidCustomField  = utils.NewError(utils.InvalidInput, "INVALID_CUSTOM_FIELD", "invalid custom field")
+	ErrCustomFieldNotFound = models.ErrCustomFieldNotFound
+)
+
+var customFieldKeyRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,32}$`)
+
+type CustomFieldService interface {
+	List(ctx context.Context, managerID int) ([]models.CustomField, error)
+	Create(ctx context.Context, managerID int, req models.CreateCustomFieldRequest) (*models.CustomField, error)
+	Delete(ctx context.Context, managerID int, key string) error
+}
+
+type customFieldService struct {
+	repo repository.CustomFieldRepository
+}
+
+func NewCustomFieldService(repo repository.CustomFieldRepository) CustomFieldService {
+	return &customFieldService{
+		repo: repo,
+	}
+}
+
+func (s *customFieldService) List(ctx context.Context, managerID int) ([]models.CustomField, error) {
+	return s.repo.List(ctx, managerID)
+}
+
+func (s *customFieldService) Create(ctx context.Context, managerID int, req models.CreateCustomFieldRequest) (*models.CustomField, error) {
+	if !customFieldKeyRegex.MatchString(req.Key) {
+		return nil, fmt.Errorf("%w: key must start with a letter and contain only letters, digits or underscores", ErrInvalidCustomField)
+	}
+
+	if len(req.Label) == 0 || len(req.Label) > 52 {
+		return nil, fmt.Errorf("%w: label must be between 1 and 52 characters", ErrInvalidCustomField)
+	}
+
+	if !req.Type.Valid() {
+		return nil, fmt.Errorf("%w: unsupported type %q", ErrInvalidCustomField, req.Type)
+	}
+
+	if req.Type == models.CustomFieldEnum && len(req.AllowedValues) == 0 {
+		return nil, fmt.Errorf("%w: enum fields require allowedValues", ErrInvalidCustomField)
+	}
+
+	if req.Type != models.CustomFieldEnum && len(req.AllowedValues) > 0 {
+		return nil, fmt.Errorf("%w: allowedValues is only supported for enum fields", ErrInvalidCustomField)
+	}
+
+	field := &models.CustomField{
+		ManagerID:     managerID,
+		Key:           req.Key,
+		Label:         req.Label,
+		Type:          req.Type,
+		Required:      req.Required,
+		AllowedValues: req.AllowedValues,
+	}
+
+	return s.repo.Create(ctx, field)
+}
+
+func (s *customFieldService) Delete(ctx context.Context, managerID int, key string) error {
+	return s.repo.Delete(ctx, managerID, key)
+}
+
+// ValidateCustomFieldValues checks values against the manager's custom field
+// schema. When partial is true (updates) required fields may be omitted, but
+// they can't be cleared with a null value.
+func ValidateCustomFieldValues(fields []models.CustomField, values models.CustomFieldValues, partial bool) error {
+	byKey := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	for key, value := range values {
+		field, ok := byKey[key]
+		if !ok {
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidCustomField, key)
+		}
+
+		if value == nil {
+			if field.Required {
+				return fmt.Errorf("%w: %q is required", ErrInvalidCustomField, key)
+			}
+			continue
+		}
+
+		if err := validateCustomFieldValue(field, value); err != nil {
+			return err
+		}
+	}
+
+	if partial {
+		return nil
+	}
+
+	for _, field := range fields {
+		if _, ok := values[field.Key]; field.Required && !ok {
+			return fmt.Errorf("%w: %q is required", ErrInvalidCustomField, field.Key)
+		}
+	}
+
+	return nil
+}
+
+func validateCustomFieldValue(field models.CustomField, value interface{}) error {
+	switch field.Type {
+	case models.CustomFieldString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%w: %q must be a string", ErrInvalidCustomField, field.Key)
+		}
+	case models.CustomFieldNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%w: %q must be a number", ErrInvalidCustomField, field.Key)
+		}
+	case models.CustomFieldBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%w: %q must be a boolean", ErrInvalidCustomField, field.Key)
+		}
+	case models.CustomFieldDate:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %q must be a date (YYYY-MM-DD)", ErrInvalidCustomField, field.Key)
+		}
+		if _, err := time.Parse(models.CustomFieldDateLayout, str); err != nil {
+			return fmt.Errorf("%w: %q must be a date (YYYY-MM-DD)", ErrInvalidCustomField, field.Key)
+		}
+	case models.CustomFieldEnum:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %q must be one of %v", ErrInvalidCustomField, field.Key, field.AllowedValues)
+		}
+		for _, allowed := range field.AllowedValues {
+			if str == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %q must be one of %v", ErrInvalidCustomField, field.Key, field.AllowedValues)
+	}
+
+	return nil
+}
+
+// typeCustomFieldFilter types the custom field values of a filter after the
+// fields they filter on, parsing the numbers and booleans sent as strings in
+// query parameters, so they compare equal to the values stored. It returns
+// false when a value can't match, for an unknown field or a value the field
+// can't hold.
+func typeCustomFieldFilter(fields []models.CustomField, values models.CustomFieldValues) (models.CustomFieldValues, bool) {
+	byKey := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	typed := make(models.CustomFieldValues, len(values))
+	for key, value := range values {
+		field, ok := byKey[key]
+		if !ok || value == nil {
+			return nil, false
+		}
+
+		if str, ok := value.(string); ok {
+			switch field.Type {
+			case models.CustomFieldNumber:
+				if number, err := strconv.ParseFloat(str, 64); err == nil {
+					value = number
+				}
+			case models.CustomFieldBoolean:
+				if boolean, err := strconv.ParseBool(str); err == nil {
+					value = boolean
+				}
+			}
+		}
+
+		if validateCustomFieldValue(field, value) != nil {
+			return nil, false
+		}
+		typed[key] = value
+	}
+
+	return typed, true
+}
@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+)
+
+func customFieldSchema() []models.CustomField {
+	return []models.CustomField{
+		{Key: "jobTitle", Type: models.CustomFieldString, Required: true},
+		{Key: "salaryGrade", Type: models.CustomFieldNumber},
+		{Key: "remote", Type: models.CustomFieldBoolean},
+		{Key: "startDate", Type: models.CustomFieldDate},
+		{Key: "costCenter", Type: models.CustomFieldEnum, AllowedValues: models.StringList{"CC-01", "CC-02"}},
+	}
+}
+
+func TestValidateCustomFieldValues_Success(t *testing.T) {
+	values := models.CustomFieldValues{
+		"jobTitle":    "Engineer",
+		"salaryGrade": float64(4),
+		"remote":      true,
+		"startDate":   "2024-01-15",
+		"costCenter":  "CC-02",
+	}
+
+	err := services.ValidateCustomFieldValues(customFieldSchema(), values, false)
+
+	assert.NoError(t, err)
+}
+
+func TestValidateCustomFieldValues_MissingRequired(t *testing.T) {
+	values := models.CustomFieldValues{"remote": false}
+
+	err := services.ValidateCustomFieldValues(customFieldSchema(), values, false)
+
+	assert.ErrorIs(t, err, services.ErrInvalidCustomField)
+}
+
+func TestValidateCustomFieldValues_PartialAllowsMissingRequired(t *testing.T) {
+	values := models.CustomFieldValues{"remote": false}
+
+	err := services.ValidateCustomFieldValues(customFieldSchema(), values, true)
+
+	assert.NoError(t, err)
+}
+
+func TestValidateCustomFieldValues_PartialRejectsClearingRequired(t *testing.T) {
+	values := models.CustomFieldValues{"jobTitle": nil}
+
+	err := services.ValidateCustomFieldValues(customFieldSchema(), values, true)
+
+	assert.ErrorIs(t, err, services.ErrInvalidCustomField)
+}
+
+func TestValidateCustomFieldValues_UnknownField(t *testing.T) {
+	values := models.CustomFieldValues{"jobTitle": "Engineer", "phone": "123"}
+
+	err := services.ValidateCustomFieldValues(customFieldSchema(), values, false)
+
+	assert.ErrorIs(t, err, services.ErrInvalidCustomField)
+}
+
+func TestValidateCustomFieldValues_WrongTypes(t *testing.T) {
+	cases := map[string]models.CustomFieldValues{
+		"string":  {"jobTitle": float64(1)},
+		"number":  {"jobTitle": "Engineer", "salaryGrade": "4"},
+		"boolean": {"jobTitle": "Engineer", "remote": "yes"},
+		"date":    {"jobTitle": "Engineer", "startDate": "15/01/2024"},
+		"enum":    {"jobTitle": "Engineer", "costCenter": "CC-99"},
+	}
+
+	for name, values := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := services.ValidateCustomFieldValues(customFieldSchema(), values, false)
+			assert.ErrorIs(t, err, services.ErrInvalidCustomField)
+		})
+	}
+}
@@ -1,22 +1,27 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
 var (
-	ErrDepartmentNotFound     = errors.New("department not found")
-	ErrDepartmentHasEmployees = errors.New("department has employees")
+	ErrDepartmentNotFound     = models.ErrDepartmentNotFound
+	ErrDepartmentHasEmployees = models.ErrDepartmentHasEmployees
+	ErrInvalidMerge           = utils.NewError(utils.InvalidInput, "INVALID_MERGE", "invalid department merge")
 )
 
 type DepartmentService interface {
-	CreateDepartment(name string, managerID int) (*DepartmentResponse, error)
-	GetDepartments(limit, offset int, name string) ([]DepartmentResponse, error)
-	UpdateDepartment(id int, name string, managerID int) (*DepartmentResponse, error)
-	DeleteDepartment(id int, managerID int) error
+	CreateDepartment(ctx context.Context, name string, managerID int) (*DepartmentResponse, error)
+	GetDepartments(ctx context.Context, limit, offset int, name string, managerID int) ([]DepartmentResponse, error)
+	UpdateDepartment(ctx context.Context, id int, req models.UpdateDepartmentRequest, managerID int) (*DepartmentResponse, error)
+	DeleteDepartment(ctx context.Context, id int, managerID int) error
+	MergeDepartments(ctx context.Context, sourceIDs []int, targetID int, managerID int) (*MergeDepartmentsResponse, error)
 }
 
 type departmentService struct {
@@ -25,8 +30,16 @@ type departmentService struct {
 
 // First, make sure your DepartmentResponse struct is defined correctly
 type DepartmentResponse struct {
-	DepartmentId int    `json:"department_id"` // Change type to int to match Department.ID
+	DepartmentId int    `json:"departmentId"` // Change type to int to match Department.ID
 	Name         string `json:"name"`
+	// UpdatedAt versions the department, see the ETag header
+	UpdatedAt time.Time `json:"-"`
+}
+
+type MergeDepartmentsResponse struct {
+	DepartmentId      int   `json:"departmentId"`
+	MergedDepartments []int `json:"mergedDepartments"`
+	MovedEmployees    int   `json:"movedEmployees"`
 }
 
 // Constructor
@@ -37,8 +50,8 @@ func NewDepartmentService(repo repository.DepartmentRepository) DepartmentServic
 }
 
 // Implement all interface methods
-func (s *departmentService) CreateDepartment(name string, managerID int) (*DepartmentResponse, error) {
-	dept, err := s.repo.Create(name, managerID)
+func (s *departmentService) CreateDepartment(ctx context.Context, name string, managerID int) (*DepartmentResponse, error) {
+	dept, err := s.repo.Create(ctx, name, managerID)
 	if err != nil {
 		return nil, err
 	}
@@ -46,13 +59,14 @@ func (s *departmentService) CreateDepartment(name string, managerID int) (*Depar
 	return &DepartmentResponse{
 		DepartmentId: dept.ID,
 		Name:         dept.Name,
+		UpdatedAt:    dept.UpdatedAt,
 	}, nil
 }
 
-func (s *departmentService) GetDepartments(limit, offset int, name string) ([]DepartmentResponse, error) {
-	departments, err := s.repo.FindAll(limit, offset, name)
+func (s *departmentService) GetDepartments(ctx context.Context, limit, offset int, name string, managerID int) ([]DepartmentResponse, error) {
+	departments, err := s.repo.FindAll(ctx, limit, offset, name, managerID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find departments: %v", err)
+		return nil, err
 	}
 
 	response := make([]DepartmentResponse, len(departments))
@@ -60,53 +74,87 @@ func (s *departmentService) GetDepartments(limit, offset int, name string) ([]De
 		response[i] = DepartmentResponse{
 			DepartmentId: dept.ID,
 			Name:         dept.Name,
+			UpdatedAt:    dept.UpdatedAt,
 		}
 	}
 
 	return response, nil
 }
 
-func (s *departmentService) UpdateDepartment(departmentID int, name string, managerID int) (*DepartmentResponse, error) {
-    // Check if department exists and belongs to the manager
-    existing, err := s.repo.FindByID(departmentID)
+func (s *departmentService) UpdateDepartment(ctx context.Context, departmentID int, req models.UpdateDepartmentRequest, managerID int) (*DepartmentResponse, error) {
+    // Check if department exists and belongs to the manager, the lookup may
+    // be cached so the update checks the owner again
+    existing, err := s.repo.FindByID(ctx, departmentID)
     if err != nil {
-        return nil, fmt.Errorf("failed to find department: %v", err)
+        return nil, err
     }
 
     if existing.ManagerID != managerID {
-        return nil, fmt.Errorf("unauthorized: department does not belong to this manager")
+        return nil, models.ErrDepartmentNotOwned
     }
 
     // Update department
-    dept, err := s.repo.Update(departmentID, name)
+    dept, err := s.repo.Update(ctx, departmentID, req, managerID)
     if err != nil {
-        return nil, fmt.Errorf("failed to update department: %v", err)
+        return nil, err
     }
 
     return &DepartmentResponse{
         DepartmentId: dept.ID,
         Name:        dept.Name,
+        UpdatedAt:   dept.UpdatedAt,
     }, nil
 }
 
 
 
-func (s *departmentService) DeleteDepartment(departmentID int, managerID int) error {
-    // Check if department exists and belongs to the manager
-    existing, err := s.repo.FindByID(departmentID)
+func (s *departmentService) DeleteDepartment(ctx context.Context, departmentID int, managerID int) error {
+    // Check if department exists and belongs to the manager, the lookup may
+    // be cached so the delete checks the owner again
+    existing, err := s.repo.FindByID(ctx, departmentID)
     if err != nil {
-        return fmt.Errorf("failed to find department: %v", err)
+        return err
     }
 
     if existing.ManagerID != managerID {
-        return fmt.Errorf("unauthorized: department does not belong to this manager")
+        return models.ErrDepartmentNotOwned
     }
 
     // Delete department
-    err = s.repo.Delete(departmentID)
+    err = s.repo.Delete(ctx, departmentID, managerID)
     if err != nil {
-        return fmt.Errorf("failed to delete department: %v", err)
+        return err
     }
 
     return nil
-}
\ No newline at end of file
+}
+
+func (s *departmentService) MergeDepartments(ctx context.Context, sourceIDs []int, targetID int, managerID int) (*MergeDepartmentsResponse, error) {
+    if len(sourceIDs) == 0 {
+        return nil, fmt.Errorf("%w: at least one source department is required", ErrInvalidMerge)
+    }
+
+    // Drop duplicate sources, a department can't be merged into itself
+    seen := make(map[int]bool, len(sourceIDs))
+    sources := make([]int, 0, len(sourceIDs))
+    for _, id := range sourceIDs {
+        if id == targetID {
+            return nil, fmt.Errorf("%w: the target department can't be a source", ErrInvalidMerge)
+        }
+        if !seen[id] {
+            seen[id] = true
+            sources = append(sources, id)
+        }
+    }
+
+    moved, err := s.repo.Merge(ctx, sources, targetID, managerID)
+    if err != nil {
+        return nil, err
+    }
+
+    return &MergeDepartmentsResponse{
+        DepartmentId:      targetID,
+        MergedDepartments: sources,
+        MovedEmployees:    moved,
+    }, nil
+}
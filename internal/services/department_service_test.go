@@ -0,0 +1,57 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+)
+
+// mergeDepartmentRepository records the merges reaching the repository
+type mergeDepartmentRepository struct {
+	repository.DepartmentRepository
+	sources [][]int
+	err     error
+}
+
+func (r *mergeDepartmentRepository) Merge(ctx context.Context, sourceIDs []int, targetID int, managerID int) (int, error) {
+	r.sources = append(r.sources, sourceIDs)
+	return 3, r.err
+}
+
+func TestDepartmentService_MergeDepartments(t *testing.T) {
+	repo := &mergeDepartmentRepository{}
+	service := services.NewDepartmentService(repo)
+
+	result, err := service.MergeDepartments(context.Background(), []int{2, 3, 2}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.DepartmentId)
+	assert.Equal(t, []int{2, 3}, result.MergedDepartments)
+	assert.Equal(t, 3, result.MovedEmployees)
+	assert.Equal(t, [][]int{{2, 3}}, repo.sources)
+}
+
+func TestDepartmentService_MergeDepartments_Invalid(t *testing.T) {
+	repo := &mergeDepartmentRepository{}
+	service := services.NewDepartmentService(repo)
+
+	_, err := service.MergeDepartments(context.Background(), nil, 1, 10)
+	assert.ErrorIs(t, err, services.ErrInvalidMerge)
+
+	_, err = service.MergeDepartments(context.Background(), []int{2, 1}, 1, 10)
+	assert.ErrorIs(t, err, services.ErrInvalidMerge)
+
+	assert.Empty(t, repo.sources)
+}
+
+func TestDepartmentService_MergeDepartments_NotFound(t *testing.T) {
+	repo := &mergeDepartmentRepository{err: models.ErrDepartmentNotFound}
+	service := services.NewDepartmentService(repo)
+
+	_, err := service.MergeDepartments(context.Background(), []int{2}, 1, 10)
+	assert.ErrorIs(t, err, services.ErrDepartmentNotFound)
+}
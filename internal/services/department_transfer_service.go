@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+var (
+	ErrInvalidTransfer  = utils.NewError(utils.InvalidInput, "INVALID_TRANSFER", "invalid department transfer")
+	ErrTransferNotFound = models.ErrTransferNotFound
+	ErrManagerNotFound  = models.ErrManagerNotFound
+)
+
+type DepartmentTransferService interface {
+	Create(ctx context.Context, managerID int, req models.CreateDepartmentTransferRequest) (*models.DepartmentTransfer, error)
+	List(ctx context.Context, managerID int) ([]models.DepartmentTransfer, error)
+	Accept(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error)
+	Reject(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error)
+	Cancel(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error)
+}
+
+type departmentTransferService struct {
+	repo repository.DepartmentTransferRepository
+}
+
+func NewDepartmentTransferService(repo repository.DepartmentTransferRepository) DepartmentTransferService {
+	return &departmentTransferService{
+		repo: repo,
+	}
+}
+
+func (s *departmentTransferService) Create(ctx context.Context, managerID int, req models.CreateDepartmentTransferRequest) (*models.DepartmentTransfer, error) {
+	if req.DepartmentID <= 0 {
+		return nil, fmt.Errorf("%w: department_id is required", ErrInvalidTransfer)
+	}
+
+	email := strings.TrimSpace(req.ToManagerEmail)
+	if email == "" {
+		return nil, fmt.Errorf("%w: to_manager_email is required", ErrInvalidTransfer)
+	}
+
+	return s.repo.Create(ctx, managerID, req.DepartmentID, email)
+}
+
+func (s *departmentTransferService) List(ctx context.Context, managerID int) ([]models.DepartmentTransfer, error) {
+	return s.repo.List(ctx, managerID)
+}
+
+func (s *departmentTransferService) Accept(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	return s.repo.Accept(ctx, managerID, id)
+}
+
+func (s *departmentTransferService) Reject(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	return s.repo.Reject(ctx, managerID, id)
+}
+
+func (s *departmentTransferService) Cancel(ctx context.Context, managerID int, id int) (*models.DepartmentTransfer, error) {
+	return s.repo.Cancel(ctx, managerID, id)
+}
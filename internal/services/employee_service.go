@@ -2,9 +2,26 @@ package services
 
 import (
 	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// exportBatchSize is how many employees are fetched per query while exporting
+const exportBatchSize = 100
+
+var (
+	ErrInvalidEmploymentStatus = utils.NewError(utils.InvalidInput, "INVALID_EMPLOYMENT_STATUS", "invalid employment status")
+	ErrEmployeeNotFound        = models.ErrEmployeeNotFound
+	ErrInvalidBulkRequest      = utils.NewError(utils.InvalidInput, "INVALID_BULK_REQUEST", "invalid bulk request")
 )
 
 type EmployeeService interface {
@@ -12,15 +29,27 @@ type EmployeeService interface {
 	Create(ctx context.Context, req models.CreateEmployeeRequest) (*models.Employee, error)
 	Update(ctx context.Context, identityNumber string, req models.UpdateEmployeeRequest) (*models.Employee, error)
 	Delete(ctx context.Context, identityNumber string) error
+	Export(ctx context.Context, filter *models.FilterOptions, w io.Writer) error
+	ChangeStatus(ctx context.Context, identityNumber string, req models.ChangeStatusRequest) (*models.Employee, error)
+	StatusHistory(ctx context.Context, identityNumber string) ([]models.EmploymentEvent, error)
+	BulkMove(ctx context.Context, req models.BulkMoveRequest) (*models.BulkResult, error)
+	BulkDelete(ctx context.Context, req models.BulkEmployeeRequest) (*models.BulkResult, error)
 }
 
 type employeeService struct {
-	repo repository.EmployeeRepository
+	repo      repository.EmployeeRepository
+	fieldRepo repository.CustomFieldRepository
+	tx        database.Transactor
 }
 
-func NewEmployeeService(repo repository.EmployeeRepository) EmployeeService {
+// NewEmployeeService runs the writes that check custom fields in a
+// transaction of tx, holding the fields they were checked against locked so
+// none can be removed between the check and the write.
+func NewEmployeeService(repo repository.EmployeeRepository, fieldRepo repository.CustomFieldRepository, tx database.Transactor) EmployeeService {
 	return &employeeService{
-		repo: repo,
+		repo:      repo,
+		fieldRepo: fieldRepo,
+		tx:        tx,
 	}
 }
 
@@ -32,26 +61,355 @@ func (s *employeeService) List(ctx context.Context, filter models.FilterOptions)
 	if filter.Offset < 0 {
 		filter.Offset = 0
 	}
+	if err := validateStatuses(filter.Statuses); err != nil {
+		return nil, err
+	}
+
+	// Custom field values no field can hold match no employee
+	if len(filter.CustomFields) > 0 {
+		fields, err := s.customFields(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		typed, ok := typeCustomFieldFilter(fields, filter.CustomFields)
+		if !ok {
+			return nil, nil
+		}
+		filter.CustomFields = typed
+	}
 
 	return s.repo.List(ctx, filter)
 }
 
+// validateStatuses rejects unknown statuses, which would fail the enum cast
+// of the query
+func validateStatuses(statuses []models.EmploymentStatus) error {
+	for _, status := range statuses {
+		if !status.Valid() {
+			return fmt.Errorf("%w: unknown status %q", ErrInvalidEmploymentStatus, status)
+		}
+	}
+	return nil
+}
+
 func (s *employeeService) Create(ctx context.Context, req models.CreateEmployeeRequest) (*models.Employee, error) {
+	// New employees start either on probation or as active, active by default
+	status := req.Status
+	if status == "" {
+		status = models.Active
+	}
+	if status != models.Probation && status != models.Active {
+		return nil, fmt.Errorf("%w: new employees must be %s or %s", ErrInvalidEmploymentStatus, models.Probation, models.Active)
+	}
+
+	hireDate := req.HireDate
+	if hireDate == nil {
+		today := models.Today()
+		hireDate = &today
+	}
+
 	employee := &models.Employee{
 		IdentityNumber:   req.IdentityNumber,
 		Name:             req.Name,
 		EmployeeImageURI: req.EmployeeImageURI,
 		Gender:           req.Gender,
 		DepartmentID:     req.DepartmentID,
+		CustomFields:     req.CustomFields,
+		Status:           status,
+		HireDate:         hireDate,
+	}
+
+	var created *models.Employee
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		fields, err := s.lockCustomFields(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := ValidateCustomFieldValues(fields, req.CustomFields, false); err != nil {
+			return err
+		}
+
+		created, err = s.repo.Create(ctx, employee)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.Create(ctx, employee)
+	return created, nil
 }
 
 func (s *employeeService) Update(ctx context.Context, identityNumber string, req models.UpdateEmployeeRequest) (*models.Employee, error) {
-	return s.repo.Update(ctx, identityNumber, req)
+	if len(req.CustomFields) == 0 {
+		return s.repo.Update(ctx, identityNumber, req)
+	}
+
+	var updated *models.Employee
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		fields, err := s.lockCustomFields(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := ValidateCustomFieldValues(fields, req.CustomFields, true); err != nil {
+			return err
+		}
+
+		updated, err = s.repo.Update(ctx, identityNumber, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
 }
 
 func (s *employeeService) Delete(ctx context.Context, identityNumber string) error {
 	return s.repo.Delete(ctx, identityNumber)
 }
+
+// ChangeStatus moves an employee through the employment lifecycle. Only the
+// transitions allowed by models.EmploymentStatus are accepted and a
+// termination must carry a reason.
+func (s *employeeService) ChangeStatus(ctx context.Context, identityNumber string, req models.ChangeStatusRequest) (*models.Employee, error) {
+	if !req.Status.Valid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidEmploymentStatus, req.Status)
+	}
+
+	if req.Status == models.Terminated && (req.Reason == nil || strings.TrimSpace(*req.Reason) == "") {
+		return nil, fmt.Errorf("%w: a termination reason is required", ErrInvalidEmploymentStatus)
+	}
+
+	if req.Reason != nil && len(*req.Reason) > 255 {
+		return nil, fmt.Errorf("%w: reason must be at most 255 characters", ErrInvalidEmploymentStatus)
+	}
+
+	if req.EffectiveDate == nil {
+		today := models.Today()
+		req.EffectiveDate = &today
+	}
+
+	return s.repo.ChangeStatus(ctx, identityNumber, req)
+}
+
+func (s *employeeService) StatusHistory(ctx context.Context, identityNumber string) ([]models.EmploymentEvent, error) {
+	return s.repo.StatusHistory(ctx, identityNumber)
+}
+
+// BulkMove moves the selected employees to another department of the
+// current manager, all of them or none.
+func (s *employeeService) BulkMove(ctx context.Context, req models.BulkMoveRequest) (*models.BulkResult, error) {
+	if req.DepartmentID <= 0 {
+		return nil, fmt.Errorf("%w: departmentId is required", ErrInvalidBulkRequest)
+	}
+
+	selection, err := normalizeBulkRequest(req.BulkEmployeeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.typeBulkFilter(ctx, &selection); err != nil {
+		return nil, err
+	}
+
+	return s.repo.BulkMove(ctx, selection, req.DepartmentID)
+}
+
+// BulkDelete soft deletes the selected employees, all of them or none.
+func (s *employeeService) BulkDelete(ctx context.Context, req models.BulkEmployeeRequest) (*models.BulkResult, error) {
+	selection, err := normalizeBulkRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.typeBulkFilter(ctx, &selection); err != nil {
+		return nil, err
+	}
+
+	return s.repo.BulkDelete(ctx, selection)
+}
+
+// normalizeBulkRequest checks that a bulk request selects employees either by
+// identity number or by a non-empty filter and removes duplicate identity
+// numbers.
+func normalizeBulkRequest(req models.BulkEmployeeRequest) (models.BulkEmployeeRequest, error) {
+	if req.Filter != nil && len(req.IdentityNumbers) > 0 {
+		return req, fmt.Errorf("%w: use either identityNumbers or filter, not both", ErrInvalidBulkRequest)
+	}
+
+	if req.Filter != nil {
+		filter := req.Filter
+		if filter.IdentityNumber == nil && filter.Gender == nil && filter.DepartmentID == nil &&
+			len(filter.Statuses) == 0 && len(filter.CustomFields) == 0 {
+			return req, fmt.Errorf("%w: filter must have at least one condition", ErrInvalidBulkRequest)
+		}
+		for _, status := range filter.Statuses {
+			if !status.Valid() {
+				return req, fmt.Errorf("%w: unknown status %q", ErrInvalidBulkRequest, status)
+			}
+		}
+		return req, nil
+	}
+
+	if len(req.IdentityNumbers) == 0 {
+		return req, fmt.Errorf("%w: identityNumbers or filter is required", ErrInvalidBulkRequest)
+	}
+
+	seen := make(map[string]bool, len(req.IdentityNumbers))
+	identityNumbers := make([]string, 0, len(req.IdentityNumbers))
+	for _, identityNumber := range req.IdentityNumbers {
+		if identityNumber == "" {
+			return req, fmt.Errorf("%w: identityNumbers must not be empty", ErrInvalidBulkRequest)
+		}
+		if !seen[identityNumber] {
+			seen[identityNumber] = true
+			identityNumbers = append(identityNumbers, identityNumber)
+		}
+	}
+
+	if len(identityNumbers) > models.MaxBulkEmployees {
+		return req, fmt.Errorf("%w: at most %d identityNumbers per request", ErrInvalidBulkRequest, models.MaxBulkEmployees)
+	}
+
+	req.IdentityNumbers = identityNumbers
+	return req, nil
+}
+
+// typeBulkFilter types the custom field values of a bulk filter like List,
+// rejecting the values that can't match
+func (s *employeeService) typeBulkFilter(ctx context.Context, req *models.BulkEmployeeRequest) error {
+	if req.Filter == nil || len(req.Filter.CustomFields) == 0 {
+		return nil
+	}
+
+	fields, err := s.customFields(ctx)
+	if err != nil {
+		return err
+	}
+
+	typed, ok := typeCustomFieldFilter(fields, req.Filter.CustomFields)
+	if !ok {
+		return fmt.Errorf("%w: filter.customFields must hold values of the custom fields", ErrInvalidBulkRequest)
+	}
+
+	filter := *req.Filter
+	filter.CustomFields = typed
+	req.Filter = &filter
+	return nil
+}
+
+// Export writes every employee matching the filter as CSV, with one column
+// per custom field of the current manager after the built-in columns. A nil
+// filter matches no employee, only the header is written.
+func (s *employeeService) Export(ctx context.Context, filter *models.FilterOptions, w io.Writer) error {
+	if filter != nil {
+		if err := validateStatuses(filter.Statuses); err != nil {
+			return err
+		}
+	}
+
+	fields, err := s.customFields(ctx)
+	if err != nil {
+		return err
+	}
+
+	if filter != nil && len(filter.CustomFields) > 0 {
+		typed, ok := typeCustomFieldFilter(fields, filter.CustomFields)
+		if ok {
+			typedFilter := *filter
+			typedFilter.CustomFields = typed
+			filter = &typedFilter
+		} else {
+			filter = nil
+		}
+	}
+
+	writer := csv.NewWriter(w)
+
+	header := []string{"identityNumber", "name", "employeeImageUri", "gender", "departmentId", "status"}
+	for _, field := range fields {
+		header = append(header, field.Key)
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing export header: %w", err)
+	}
+	if filter == nil {
+		writer.Flush()
+		return writer.Error()
+	}
+
+	// Batches page by id, an employee added or removed during the export
+	// doesn't make the next batch skip or repeat others
+	batch := *filter
+	batch.Limit = exportBatchSize
+	batch.Offset = 0
+	for {
+		employees, err := s.repo.List(ctx, batch)
+		if err != nil {
+			return err
+		}
+
+		for _, emp := range employees {
+			record := []string{
+				emp.IdentityNumber,
+				emp.Name,
+				emp.EmployeeImageURI,
+				string(emp.Gender),
+				strconv.Itoa(emp.DepartmentID),
+				string(emp.Status),
+			}
+			for _, field := range fields {
+				record = append(record, formatCustomFieldValue(emp.CustomFields[field.Key]))
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("error writing export row: %w", err)
+			}
+		}
+
+		if len(employees) < batch.Limit {
+			break
+		}
+		batch.AfterID = employees[len(employees)-1].ID
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *employeeService) customFields(ctx context.Context) ([]models.CustomField, error) {
+	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		return nil, utils.ErrMissingClaims
+	}
+
+	return s.fieldRepo.List(ctx, claims.ID)
+}
+
+// lockCustomFields returns the custom fields of the current manager, locked
+// against deletion until the transaction of ctx ends
+func (s *employeeService) lockCustomFields(ctx context.Context) ([]models.CustomField, error) {
+	claims, ok := ctx.Value(constants.JWTKey).(*utils.Claims)
+	if !ok {
+		return nil, utils.ErrMissingClaims
+	}
+
+	return s.fieldRepo.ListForShare(ctx, claims.ID)
+}
+
+func formatCustomFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,248 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// bulkEmployeeRepository records the bulk requests reaching the repository
+type bulkEmployeeRepository struct {
+	repository.EmployeeRepository
+	requests []models.BulkEmployeeRequest
+}
+
+func (r *bulkEmployeeRepository) BulkDelete(ctx context.Context, req models.BulkEmployeeRequest) (*models.BulkResult, error) {
+	r.requests = append(r.requests, req)
+	return &models.BulkResult{Applied: true}, nil
+}
+
+func (r *bulkEmployeeRepository) BulkMove(ctx context.Context, req models.BulkEmployeeRequest, departmentID int) (*models.BulkResult, error) {
+	r.requests = append(r.requests, req)
+	return &models.BulkResult{Applied: true}, nil
+}
+
+func TestEmployeeService_BulkDelete_Validation(t *testing.T) {
+	departmentID := 1
+	invalidStatus := []models.EmploymentStatus{"retired"}
+	tooMany := make([]string, models.MaxBulkEmployees+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("ID%05d", i)
+	}
+
+	tests := []struct {
+		name string
+		req  models.BulkEmployeeRequest
+	}{
+		{"empty", models.BulkEmployeeRequest{}},
+		{"both selections", models.BulkEmployeeRequest{
+			IdentityNumbers: []string{"12345"},
+			Filter:          &models.FilterOptions{DepartmentID: &departmentID},
+		}},
+		{"empty filter", models.BulkEmployeeRequest{Filter: &models.FilterOptions{}}},
+		{"invalid status", models.BulkEmployeeRequest{Filter: &models.FilterOptions{Statuses: invalidStatus}}},
+		{"empty identity number", models.BulkEmployeeRequest{IdentityNumbers: []string{"12345", ""}}},
+		{"too many", models.BulkEmployeeRequest{IdentityNumbers: tooMany}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &bulkEmployeeRepository{}
+			service := services.NewEmployeeService(repo, nil, nil)
+
+			_, err := service.BulkDelete(context.Background(), tt.req)
+			assert.ErrorIs(t, err, services.ErrInvalidBulkRequest)
+			assert.Empty(t, repo.requests)
+		})
+	}
+}
+
+func TestEmployeeService_List_RejectsUnknownStatus(t *testing.T) {
+	service := services.NewEmployeeService(&bulkEmployeeRepository{}, nil, nil)
+
+	_, err := service.List(context.Background(), models.FilterOptions{
+		Statuses: []models.EmploymentStatus{models.Active, "bogus"},
+	})
+	assert.ErrorIs(t, err, services.ErrInvalidEmploymentStatus)
+	assert.Equal(t, 400, utils.AsGoGoError(err).Type.Status())
+}
+
+func TestEmployeeService_BulkDelete_DeduplicatesIdentityNumbers(t *testing.T) {
+	repo := &bulkEmployeeRepository{}
+	service := services.NewEmployeeService(repo, nil, nil)
+
+	_, err := service.BulkDelete(context.Background(), models.BulkEmployeeRequest{
+		IdentityNumbers: []string{"12345", "67890", "12345"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"12345", "67890"}, repo.requests[0].IdentityNumbers)
+}
+
+func TestEmployeeService_BulkMove_RequiresDepartment(t *testing.T) {
+	repo := &bulkEmployeeRepository{}
+	service := services.NewEmployeeService(repo, nil, nil)
+
+	_, err := service.BulkMove(context.Background(), models.BulkMoveRequest{
+		BulkEmployeeRequest: models.BulkEmployeeRequest{IdentityNumbers: []string{"12345"}},
+	})
+	assert.ErrorIs(t, err, services.ErrInvalidBulkRequest)
+	assert.Empty(t, repo.requests)
+}
+
+// filterEmployeeRepository records the filters reaching the repository
+type filterEmployeeRepository struct {
+	bulkEmployeeRepository
+	filters []models.FilterOptions
+}
+
+func (r *filterEmployeeRepository) List(ctx context.Context, filter models.FilterOptions) ([]models.Employee, error) {
+	r.filters = append(r.filters, filter)
+	return nil, nil
+}
+
+func TestEmployeeService_List_TypesCustomFieldFilter(t *testing.T) {
+	repo := &filterEmployeeRepository{}
+	service := services.NewEmployeeService(repo, &txFieldRepository{}, nil)
+	ctx := context.WithValue(context.Background(), constants.JWTKey, &utils.Claims{ID: 1})
+
+	_, err := service.List(ctx, models.FilterOptions{CustomFields: models.CustomFieldValues{
+		"jobTitle":    "Engineer",
+		"salaryGrade": "4",
+		"remote":      "true",
+	}})
+	assert.NoError(t, err)
+	if assert.Len(t, repo.filters, 1) {
+		assert.Equal(t, models.CustomFieldValues{"jobTitle": "Engineer", "salaryGrade": float64(4), "remote": true}, repo.filters[0].CustomFields)
+	}
+
+	// Values no field can hold never reach the repository
+	for _, values := range []models.CustomFieldValues{{"salaryGrade": "high"}, {"costCenter": "CC-99"}, {"unknown": "x"}} {
+		employees, err := service.List(ctx, models.FilterOptions{CustomFields: values})
+		assert.NoError(t, err)
+		assert.Empty(t, employees)
+	}
+	assert.Len(t, repo.filters, 1)
+
+	_, err = service.BulkDelete(ctx, models.BulkEmployeeRequest{
+		Filter: &models.FilterOptions{CustomFields: models.CustomFieldValues{"remote": "maybe"}},
+	})
+	assert.ErrorIs(t, err, services.ErrInvalidBulkRequest)
+	assert.Empty(t, repo.requests)
+}
+
+// txKey marks the contexts of fakeTransactor transactions
+type txKey struct{}
+
+// fakeTransactor runs units of work with a marked context, rolling nothing
+// back
+type fakeTransactor struct {
+	calls int
+}
+
+func (tx *fakeTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx.calls++
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+// txFieldRepository records whether custom fields are locked in a transaction
+type txFieldRepository struct {
+	repository.CustomFieldRepository
+	inTx bool
+}
+
+func (r *txFieldRepository) List(ctx context.Context, managerID int) ([]models.CustomField, error) {
+	return customFieldSchema(), nil
+}
+
+func (r *txFieldRepository) ListForShare(ctx context.Context, managerID int) ([]models.CustomField, error) {
+	r.inTx, _ = ctx.Value(txKey{}).(bool)
+	return customFieldSchema(), nil
+}
+
+// txEmployeeRepository records whether updates run in a transaction
+type txEmployeeRepository struct {
+	repository.EmployeeRepository
+	inTx bool
+}
+
+func (r *txEmployeeRepository) Update(ctx context.Context, identityNumber string, req models.UpdateEmployeeRequest) (*models.Employee, error) {
+	r.inTx, _ = ctx.Value(txKey{}).(bool)
+	return &models.Employee{IdentityNumber: identityNumber}, nil
+}
+
+func TestEmployeeService_Update_ChecksCustomFieldsInTransaction(t *testing.T) {
+	repo := &txEmployeeRepository{}
+	fieldRepo := &txFieldRepository{}
+	tx := &fakeTransactor{}
+	service := services.NewEmployeeService(repo, fieldRepo, tx)
+
+	ctx := context.WithValue(context.Background(), constants.JWTKey, &utils.Claims{ID: 1})
+	_, err := service.Update(ctx, "12345", models.UpdateEmployeeRequest{
+		CustomFields: models.CustomFieldValues{"remote": true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tx.calls)
+	assert.True(t, fieldRepo.inTx)
+	assert.True(t, repo.inTx)
+
+	// Without custom fields there is nothing to check
+	_, err = service.Update(ctx, "12345", models.UpdateEmployeeRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tx.calls)
+}
+
+// pagingEmployeeRepository lists count employees with ids from 1, recording
+// the filters of the pages
+type pagingEmployeeRepository struct {
+	bulkEmployeeRepository
+	count   int
+	filters []models.FilterOptions
+}
+
+func (r *pagingEmployeeRepository) List(ctx context.Context, filter models.FilterOptions) ([]models.Employee, error) {
+	r.filters = append(r.filters, filter)
+	var employees []models.Employee
+	for id := filter.AfterID + 1 + filter.Offset; id <= r.count && len(employees) < filter.Limit; id++ {
+		employees = append(employees, models.Employee{ID: id, IdentityNumber: fmt.Sprint(id)})
+	}
+	return employees, nil
+}
+
+func TestEmployeeService_Export_PagesByID(t *testing.T) {
+	repo := &pagingEmployeeRepository{count: 250}
+	service := services.NewEmployeeService(repo, &txFieldRepository{}, &fakeTransactor{})
+
+	var buf bytes.Buffer
+	ctx := context.WithValue(context.Background(), constants.JWTKey, &utils.Claims{ID: 1})
+	assert.NoError(t, service.Export(ctx, &models.FilterOptions{}, &buf))
+	assert.Equal(t, 251, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	var afterIDs []int
+	for _, filter := range repo.filters {
+		assert.Zero(t, filter.Offset)
+		afterIDs = append(afterIDs, filter.AfterID)
+	}
+	assert.Equal(t, []int{0, 100, 200}, afterIDs)
+}
+
+func TestEmployeeService_Export_Unmatchable(t *testing.T) {
+	// The repository isn't implemented, listing employees would panic
+	service := services.NewEmployeeService(&bulkEmployeeRepository{}, &txFieldRepository{}, &fakeTransactor{})
+
+	var buf bytes.Buffer
+	ctx := context.WithValue(context.Background(), constants.JWTKey, &utils.Claims{ID: 1})
+	assert.NoError(t, service.Export(ctx, nil, &buf))
+	assert.Equal(t, "identityNumber,name,employeeImageUri,gender,departmentId,status,jobTitle,salaryGrade,remote,startDate,costCenter\n", buf.String())
+
+	err := service.Export(context.Background(), nil, &buf)
+	assert.ErrorIs(t, err, utils.ErrMissingClaims)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+)
+
+// fileExtensionRegex matches the extensions kept on the stored files, others
+// are dropped
+var fileExtensionRegex = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+type FileService interface {
+	// Upload stores the content of the file the client named filename
+	Upload(ctx context.Context, filename string, content io.Reader) (*models.File, error)
+}
+
+type fileService struct {
+	repo      repository.FileRepository
+	uriPrefix string
+}
+
+// NewFileService stores the files in repo under a random id, keeping their
+// extension, and returns their URI as uriPrefix followed by the stored name.
+func NewFileService(repo repository.FileRepository, uriPrefix string) FileService {
+	return &fileService{
+		repo:      repo,
+		uriPrefix: uriPrefix,
+	}
+}
+
+func (s *fileService) Upload(ctx context.Context, filename string, content io.Reader) (*models.File, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("error generating file id: %w", err)
+	}
+	id := hex.EncodeToString(b)
+
+	name := id
+	if ext := strings.ToLower(filepath.Ext(filename)); fileExtensionRegex.MatchString(ext) {
+		name += ext
+	}
+
+	if err := s.repo.Save(ctx, name, content); err != nil {
+		return nil, err
+	}
+
+	return &models.File{
+		FileID: id,
+		URI:    s.uriPrefix + name,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/repository"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+// maxCalendarDays bounds the date range of a team calendar query
+const maxCalendarDays = 93
+
+var (
+	ErrInvalidLeaveRequest = utils.NewError(utils.InvalidInput, "INVALID_LEAVE_REQUEST", "invalid leave request")
+	ErrLeaveNotFound       = models.ErrLeaveNotFound
+)
+
+type LeaveService interface {
+	Create(ctx context.Context, managerID int, req models.CreateLeaveRequest) (*models.LeaveRequest, error)
+	List(ctx context.Context, managerID int, filter models.LeaveFilterOptions) ([]models.LeaveRequest, error)
+	Approve(ctx context.Context, managerID int, id int, req models.LeaveDecisionRequest) (*models.LeaveRequest, error)
+	Reject(ctx context.Context, managerID int, id int, req models.LeaveDecisionRequest) (*models.LeaveRequest, error)
+	Cancel(ctx context.Context, managerID int, id int, req models.LeaveDecisionRequest) (*models.LeaveRequest, error)
+	Balances(ctx context.Context, managerID int, identityNumber string, year int) ([]models.LeaveBalance, error)
+	Calendar(ctx context.Context, managerID int, from, to models.Date, departmentID *int) ([]models.LeaveCalendarEntry, error)
+}
+
+type leaveService struct {
+	repo repository.LeaveRepository
+}
+
+func NewLeaveService(repo repository.LeaveRepository) LeaveService {
+	return &leaveService{
+		repo: repo,
+	}
+}
+
+func (s *leaveService) Create(ctx context.Context, managerID int, req models.CreateLeaveRequest) (*models.LeaveRequest, error) {
+	if !req.Type.Valid() {
+		return nil, fmt.Errorf("%w: unknown leave type %q", ErrInvalidLeaveRequest, req.Type)
+	}
+
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return nil, fmt.Errorf("%w: startDate and endDate are required", ErrInvalidLeaveRequest)
+	}
+
+	if req.EndDate.Before(req.StartDate.Time) {
+		return nil, fmt.Errorf("%w: endDate must not be before startDate", ErrInvalidLeaveRequest)
+	}
+
+	// Balances accrue per calendar year, a request spanning two years must be split
+	if req.StartDate.Year() != req.EndDate.Year() {
+		return nil, fmt.Errorf("%w: a leave request must not span calendar years", ErrInvalidLeaveRequest)
+	}
+
+	if req.Reason != nil && len(*req.Reason) > 255 {
+		return nil, fmt.Errorf("%w: reason must be at most 255 characters", ErrInvalidLeaveRequest)
+	}
+
+	days := models.LeaveDays(req.StartDate, req.EndDate)
+	if days == 0 {
+		return nil, fmt.Errorf("%w: the requested range has no working days", ErrInvalidLeaveRequest)
+	}
+
+	employee, err := s.repo.FindEmployee(ctx, managerID, req.IdentityNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if employee.Status == models.Terminated {
+		return nil, fmt.Errorf("%w: employee is terminated", ErrInvalidLeaveRequest)
+	}
+
+	// The repository checks the balance once accrued, see Create
+	if _, limited := models.LeaveAllowances[req.Type]; limited {
+		year := req.StartDate.Year()
+		allowance := models.LeaveAllowance(req.Type, year, employee.HireDate)
+		if err := s.repo.EnsureBalance(ctx, employee.ID, year, req.Type, allowance); err != nil {
+			return nil, err
+		}
+	}
+
+	leave := &models.LeaveRequest{
+		EmployeeID:     employee.ID,
+		IdentityNumber: employee.IdentityNumber,
+		Type:           req.Type,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		Days:           days,
+		Reason:         req.Reason,
+	}
+
+	return s.repo.Create(ctx, leave)
+}
+
+func (s *leaveService) List(ctx context.Context, managerID int, filter models.LeaveFilterOptions) ([]models.LeaveRequest, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 5
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.repo.List(ctx, managerID, filter)
+}
+
+func (s *leaveService) Approve(ctx context.Context, managerID int, id int, req models.LeaveDecisionRequest) (*models.LeaveRequest, error) {
+	leave, err := s.repo.FindByID(ctx, managerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the balance of the leave's year has been accrued before approving
+	if _, limited := models.LeaveAllowances[leave.Type]; limited {
+		employee, err := s.repo.FindEmployee(ctx, managerID, leave.IdentityNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		year := leave.StartDate.Year()
+		allowance := models.LeaveAllowance(leave.Type, year, employee.HireDate)
+		if err := s.repo.EnsureBalance(ctx, employee.ID, year, leave.Type, allowance); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.Approve(ctx, managerID, id, req.Note)
+}
+
+func (s *leaveService) Reject(ctx context.Context, managerID int, id int, req models.LeaveDecisionRequest) (*models.LeaveRequest, error) {
+	return s.repo.Decide(ctx, managerID, id, models.LeaveRejected, req.Note)
+}
+
+func (s *leaveService) Cancel(ctx context.Context, managerID int, id int, req models.LeaveDecisionRequest) (*models.LeaveRequest, error) {
+	return s.repo.Decide(ctx, managerID, id, models.LeaveCancelled, req.Note)
+}
+
+// Balances returns the balance of every limited leave type for the year,
+// accruing the yearly allowance on first access.
+func (s *leaveService) Balances(ctx context.Context, managerID int, identityNumber string, year int) ([]models.LeaveBalance, error) {
+	employee, err := s.repo.FindEmployee(ctx, managerID, identityNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]models.LeaveBalance, 0, len(models.LeaveAllowances))
+	for _, leaveType := range []models.LeaveType{models.AnnualLeave, models.SickLeave} {
+		balance, err := s.balance(ctx, employee, year, leaveType)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, *balance)
+	}
+
+	return balances, nil
+}
+
+func (s *leaveService) Calendar(ctx context.Context, managerID int, from, to models.Date, departmentID *int) ([]models.LeaveCalendarEntry, error) {
+	if to.Before(from.Time) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidLeaveRequest)
+	}
+
+	if to.Sub(from.Time).Hours()/24 > maxCalendarDays {
+		return nil, fmt.Errorf("%w: the calendar range must be at most %d days", ErrInvalidLeaveRequest, maxCalendarDays)
+	}
+
+	return s.repo.Calendar(ctx, managerID, from, to, departmentID)
+}
+
+func (s *leaveService) balance(ctx context.Context, employee *models.Employee, year int, leaveType models.LeaveType) (*models.LeaveBalance, error) {
+	allowance := models.LeaveAllowance(leaveType, year, employee.HireDate)
+	if err := s.repo.EnsureBalance(ctx, employee.ID, year, leaveType, allowance); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Balance(ctx, employee.ID, year, leaveType)
+}
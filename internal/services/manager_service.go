@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
@@ -8,12 +9,16 @@ import (
 	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
 )
 
+// ErrInvalidCredentials is returned to logins with an unknown email or a
+// wrong password alike
+var ErrInvalidCredentials = utils.NewError(utils.Unauthenticated, "INVALID_CREDENTIALS", "Invalid credential")
+
 type ManagerService interface {
-	Create(email string, password string) (int, *utils.GoGoError)
-	GetAll() ([]models.Manager, *utils.GoGoError)
-	GetByID(id int) (*models.Manager, *utils.GoGoError)
-	GetByEmail(email string) (*models.Manager, *utils.GoGoError)
-	Update(manager *utils.ManagerRequest) *utils.GoGoError
+	Create(ctx context.Context, email string, password string) (int, *utils.GoGoError)
+	GetAll(ctx context.Context) ([]models.Manager, *utils.GoGoError)
+	GetByID(ctx context.Context, id int) (*models.Manager, *utils.GoGoError)
+	GetByEmail(ctx context.Context, email string) (*models.Manager, *utils.GoGoError)
+	Update(ctx context.Context, manager *utils.ManagerRequest) *utils.GoGoError
 }
 
 type ValidEmailFunc func(email string) error
@@ -34,7 +39,7 @@ func NewManagerService(
 	return &managerService{managerRepo: managerRepo, validateEmail: validateEmail, validatePassword: validatePassword}
 }
 
-func (s *managerService) Create(email string, password string) (int, *utils.GoGoError) {
+func (s *managerService) Create(ctx context.Context, email string, password string) (int, *utils.GoGoError) {
 	emailErr := s.validateEmail(email)
 	if emailErr != nil {
 		return 0, utils.WrapError(emailErr, utils.InvalidEmailFormat, "Invalid email format")
@@ -45,31 +50,31 @@ func (s *managerService) Create(email string, password string) (int, *utils.GoGo
 		return 0, utils.WrapError(pwdErr, utils.InvalidPasswordLength, "Invalid password length")
 	}
 
-	return s.managerRepo.Create(email, password)
+	return s.managerRepo.Create(ctx, email, password)
 }
 
-func (s *managerService) GetAll() ([]models.Manager, *utils.GoGoError) {
-	return s.managerRepo.GetAll()
+func (s *managerService) GetAll(ctx context.Context) ([]models.Manager, *utils.GoGoError) {
+	return s.managerRepo.GetAll(ctx)
 }
 
-func (s *managerService) GetByID(id int) (*models.Manager, *utils.GoGoError) {
+func (s *managerService) GetByID(ctx context.Context, id int) (*models.Manager, *utils.GoGoError) {
 	if id <= 0 {
 		err := errors.New("invalid sql id")
 		return nil, utils.WrapError(err, utils.InvalidUserId, "Invalid manager id")
 	}
 
-	return s.managerRepo.GetByID(id)
+	return s.managerRepo.GetByID(ctx, id)
 }
 
-func (s *managerService) GetByEmail(email string) (*models.Manager, *utils.GoGoError) {
+func (s *managerService) GetByEmail(ctx context.Context, email string) (*models.Manager, *utils.GoGoError) {
 	err := s.validateEmail(email)
 	if err != nil {
 		return nil, utils.WrapError(err, utils.InvalidEmailFormat, "Invalid email format")
 	}
 
-	return s.managerRepo.GetByEmail(email)
+	return s.managerRepo.GetByEmail(ctx, email)
 }
 
-func (s *managerService) Update(manager *utils.ManagerRequest) *utils.GoGoError {
-	return s.managerRepo.Update(manager)
+func (s *managerService) Update(ctx context.Context, manager *utils.ManagerRequest) *utils.GoGoError {
+	return s.managerRepo.Update(ctx, manager)
 }
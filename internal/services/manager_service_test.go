@@ -1,11 +1,13 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	"github.com/ngikut-project-sprint/GoGoManager/internal/services"
@@ -26,9 +28,9 @@ func TestManagerService_CreateManagers_Success(t *testing.T) {
 
 	mockEmailValidator.On("ValidateEmail", email).Return(nil)
 	mockPwdValidator.On("ValidatePassword", password, 8, 52).Return(nil)
-	mockRepo.On("Create", email, password).Return(id, nil)
+	mockRepo.On("Create", mock.Anything, email, password).Return(id, nil)
 
-	res, err := service.Create(email, password)
+	res, err := service.Create(context.Background(), email, password)
 
 	utils.NoError(t, err)
 	assert.Equal(t, id, res)
@@ -55,7 +57,7 @@ func TestManagerService_CreateManagers_InvalidEmail(t *testing.T) {
 
 	mockEmailValidator.On("ValidateEmail", email).Return(e)
 
-	res, err := service.Create(email, password)
+	res, err := service.Create(context.Background(), email, password)
 
 	assert.Equal(t, error, err)
 	assert.Equal(t, 0, res)
@@ -83,7 +85,7 @@ func TestManagerService_CreateManagers_InvalidPassword(t *testing.T) {
 	mockEmailValidator.On("ValidateEmail", email).Return(nil)
 	mockPwdValidator.On("ValidatePassword", password, 8, 52).Return(e)
 
-	res, err := service.Create(email, password)
+	res, err := service.Create(context.Background(), email, password)
 
 	assert.Equal(t, error, err)
 	assert.Equal(t, 0, res)
@@ -126,9 +128,9 @@ func TestManagerService_GetAllManagers_Success(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("GetAll").Return(mockManagers, nil)
+	mockRepo.On("GetAll", mock.Anything).Return(mockManagers, nil)
 
-	res, err := service.GetAll()
+	res, err := service.GetAll(context.Background())
 
 	utils.NoError(t, err)
 	assert.Equal(t, mockManagers, res)
@@ -146,9 +148,9 @@ func TestManagerService_GetAllManagers_Error(t *testing.T) {
 
 	error := &utils.GoGoError{Err: errors.New("Database error")}
 
-	mockRepo.On("GetAll").Return(nil, error)
+	mockRepo.On("GetAll", mock.Anything).Return(nil, error)
 
-	res, err := service.GetAll()
+	res, err := service.GetAll(context.Background())
 
 	assert.Equal(t, error, err)
 	assert.Nil(t, res)
@@ -177,9 +179,9 @@ func TestManagerService_GetByIDManager_Success(t *testing.T) {
 		DeletedAt:       nil,
 	}
 
-	mockRepo.On("GetByID", id).Return(manager, nil)
+	mockRepo.On("GetByID", mock.Anything, id).Return(manager, nil)
 
-	res, err := service.GetByID(id)
+	res, err := service.GetByID(context.Background(), id)
 
 	utils.NoError(t, err)
 	assert.Equal(t, manager, res)
@@ -198,9 +200,9 @@ func TestManagerService_GetByIDManager_Error(t *testing.T) {
 	id := 1
 	error := &utils.GoGoError{Err: errors.New("Database error")}
 
-	mockRepo.On("GetByID", id).Return(nil, error)
+	mockRepo.On("GetByID", mock.Anything, id).Return(nil, error)
 
-	res, err := service.GetByID(id)
+	res, err := service.GetByID(context.Background(), id)
 
 	assert.Equal(t, error, err)
 	assert.Nil(t, res)
@@ -232,9 +234,9 @@ func TestManagerService_GetByEmailManager_Success(t *testing.T) {
 	}
 
 	mockEmailValidator.On("ValidateEmail", email).Return(nil)
-	mockRepo.On("GetByEmail", email).Return(manager, nil)
+	mockRepo.On("GetByEmail", mock.Anything, email).Return(manager, nil)
 
-	res, err := service.GetByEmail(email)
+	res, err := service.GetByEmail(context.Background(), email)
 
 	utils.NoError(t, err)
 	assert.Equal(t, manager, res)
@@ -260,7 +262,7 @@ func TestManagerService_GetByEmailManager_InvalidEmail(t *testing.T) {
 
 	mockEmailValidator.On("ValidateEmail", email).Return(e)
 
-	res, err := service.GetByEmail(email)
+	res, err := service.GetByEmail(context.Background(), email)
 
 	assert.Equal(t, error, err)
 	assert.Nil(t, res)
@@ -280,9 +282,9 @@ func TestManagerService_GetByEmailManager_Error(t *testing.T) {
 	error := &utils.GoGoError{Err: errors.New("Database error")}
 
 	mockEmailValidator.On("ValidateEmail", email).Return(nil)
-	mockRepo.On("GetByEmail", email).Return(nil, error)
+	mockRepo.On("GetByEmail", mock.Anything, email).Return(nil, error)
 
-	res, err := service.GetByEmail(email)
+	res, err := service.GetByEmail(context.Background(), email)
 
 	assert.Equal(t, error, err)
 	assert.Nil(t, res)
@@ -308,9 +310,9 @@ func TestManagerService_UpdateManager_Success(t *testing.T) {
 		CompanyImageUri: ptr("company1.png"),
 	}
 
-	mockRepo.On("Update", manager).Return(nil)
+	mockRepo.On("Update", mock.Anything, manager).Return(nil)
 
-	err := service.Update(manager)
+	err := service.Update(context.Background(), manager)
 
 	utils.NoError(t, err)
 
@@ -337,9 +339,9 @@ func TestManagerService_UpdateManager_Error(t *testing.T) {
 		CompanyImageUri: ptr("company1.png"),
 	}
 
-	mockRepo.On("Update", manager).Return(error)
+	mockRepo.On("Update", mock.Anything, manager).Return(error)
 
-	err := service.Update(manager)
+	err := service.Update(context.Background(), manager)
 
 	utils.Error(t, err)
 
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+
+	"github.com/XSAM/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// internalPackages prefixes the functions of the application, the callers a
+// statement is named after
+const internalPackages = "github.com/ngikut-project-sprint/GoGoManager/internal/"
+
+// skippedPackages are the internal packages sitting between the callers and
+// database/sql, which never name a statement
+var skippedPackages = []string{
+	internalPackages + "tracing.",
+	internalPackages + "database.",
+}
+
+// OpenDB opens a database like sql.Open, with a span for every statement.
+// Spans are named after the function running the statement, like
+// employeeRepository.List, and never record the statement or its arguments.
+func OpenDB(driverName, dataSourceName string) (*sql.DB, error) {
+	return otelsql.Open(driverName, dataSourceName,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanNameFormatter(statementName),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableQuery:         true,
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
+}
+
+// statementName names the span of a statement after its caller, falling back
+// to the method of database/sql, like sql.conn.query
+func statementName(_ context.Context, method otelsql.Method, _ string) string {
+	return callerSpanName(string(method))
+}
+
+// callerSpanName returns the name of the first internal function calling
+// it, or fallback when there is none
+func callerSpanName(fallback string) string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if name, ok := callerName(frame.Function); ok {
+			return name
+		}
+		if !more {
+			return fallback
+		}
+	}
+}
+
+// callerName shortens the function of an internal package to its receiver
+// and name, like employeeRepository.List
+func callerName(function string) (string, bool) {
+	if !strings.HasPrefix(function, internalPackages) {
+		return "", false
+	}
+	for _, skipped := range skippedPackages {
+		if strings.HasPrefix(function, skipped) {
+			return "", false
+		}
+	}
+
+	name := function[strings.LastIndex(function, "/")+1:]
+	name = name[strings.Index(name, ".")+1:]
+	if i := strings.Index(name, ".func"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+	return name, true
+}
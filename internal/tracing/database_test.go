@@ -0,0 +1,105 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/tracing"
+)
+
+// stubDriver accepts every statement and returns no rows
+type stubDriver struct{}
+
+func (stubDriver) Open(string) (driver.Conn, error) { return stubConn{}, nil }
+
+type stubConn struct{}
+
+func (stubConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (stubConn) Close() error                        { return nil }
+func (stubConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (stubConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return stubRows{}, nil
+}
+
+type stubRows struct{}
+
+func (stubRows) Columns() []string         { return nil }
+func (stubRows) Close() error              { return nil }
+func (stubRows) Next([]driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("tracing-stub", stubDriver{})
+}
+
+type employeeRepository struct {
+	db *sql.DB
+}
+
+func (r *employeeRepository) List(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM employees WHERE identity_number = $1", "secret-identity")
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+func TestOpenDB_NamesSpansAfterCaller(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	db, err := tracing.OpenDB("tracing-stub", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "GET /v1/employee")
+	require.NoError(t, (&employeeRepository{db: db}).List(ctx))
+	parent.End()
+
+	var query sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() != "GET /v1/employee" && span.Parent().SpanID() == parent.SpanContext().SpanID() {
+			query = span
+		}
+	}
+	require.NotNil(t, query, "no span for the statement")
+
+	assert.Equal(t, "employeeRepository.List", query.Name())
+	for _, attr := range query.Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "secret-identity")
+		assert.NotContains(t, attr.Value.Emit(), "SELECT")
+	}
+}
+
+type departmentRepository struct{}
+
+// List runs a statement like pgx does, between the tracer hooks
+func (r *departmentRepository) List(ctx context.Context, conn *pgx.Conn) {
+	tracer := tracing.PgxTracer{}
+	ctx = tracer.TraceQueryStart(ctx, conn, pgx.TraceQueryStartData{SQL: "SELECT * FROM departments"})
+	tracer.TraceQueryEnd(ctx, conn, pgx.TraceQueryEndData{Err: errors.New("relation does not exist")})
+}
+
+func TestPgxTracer_NamesSpansAfterCaller(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	(&departmentRepository{}).List(context.Background(), nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "departmentRepository.List", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
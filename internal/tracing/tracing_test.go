@@ -0,0 +1,35 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/tracing"
+)
+
+func TestSetup_Stdout(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{
+		Exporter:    "stdout",
+		ServiceName: "gogomanager-test",
+		SampleRatio: 1,
+	}, &buf)
+	require.NoError(t, err)
+
+	_, span := tracing.Tracer().Start(context.Background(), "GET /v1/employee")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), `"Name":"GET /v1/employee"`)
+	assert.Contains(t, buf.String(), "gogomanager-test")
+}
+
+func TestSetup_InvalidExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: "zipkin"}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
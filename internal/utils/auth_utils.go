@@ -8,9 +8,9 @@ const (
 )
 
 type Credential struct {
-	Email    string     `json:"email"`
-	Password string     `json:"password"`
-	Action   AuthAction `json:"action"`
+	Email    string     `json:"email" validate:"required,email"`
+	Password string     `json:"password" validate:"required,min=8,max=32"`
+	Action   AuthAction `json:"action" validate:"required,oneof=create login"`
 }
 
 type AuthResponse struct {
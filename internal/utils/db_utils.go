@@ -1,15 +1,37 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/lib/pq"
 )
 
 func UniqueConstraintError(err error) error {
-	pqErr, ok := err.(*pq.Error)
-	if ok && pqErr.Code == "23505" {
-		return fmt.Errorf("Unique constraint violated: %s", pqErr.Constraint)
+	if code, constraint := postgresError(err); code == "23505" {
+		return fmt.Errorf("Unique constraint violated: %s", constraint)
 	}
 	return nil
 }
+
+func ForeignKeyError(err error) error {
+	if code, constraint := postgresError(err); code == "23503" {
+		return fmt.Errorf("Foreign key constraint violated: %s", constraint)
+	}
+	return nil
+}
+
+// postgresError returns the SQLSTATE code and constraint of an error of
+// lib/pq or pgx
+func postgresError(err error) (code string, constraint string) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code), pqErr.Constraint
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code, pgErr.ConstraintName
+	}
+	return "", ""
+}
@@ -0,0 +1,30 @@
+package utils_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+func TestConstraintErrors(t *testing.T) {
+	// lib/pq and pgx report the same violations
+	unique := []error{
+		&pq.Error{Code: "23505", Constraint: "managers_email_key"},
+		fmt.Errorf("error creating manager: %w", &pgconn.PgError{Code: "23505", ConstraintName: "managers_email_key"}),
+	}
+	for _, err := range unique {
+		assert.EqualError(t, utils.UniqueConstraintError(err), "Unique constraint violated: managers_email_key")
+		assert.NoError(t, utils.ForeignKeyError(err))
+	}
+
+	foreignKey := &pgconn.PgError{Code: "23503", ConstraintName: "employees_department_id_fkey"}
+	assert.EqualError(t, utils.ForeignKeyError(foreignKey), "Foreign key constraint violated: employees_department_id_fkey")
+
+	assert.NoError(t, utils.UniqueConstraintError(errors.New("connection refused")))
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,18 +19,138 @@ const (
 	SQLError
 	SQLUniqueViolated
 	PasswordHashFailed
+	InvalidInput
+	ResourceNotFound
+	ResourceConflict
+	Unauthenticated
+	PermissionDenied
+	InternalError
+	PreconditionFailed
 )
 
+// Status returns the HTTP status code errors of this type are reported with
+func (t ErrorType) Status() int {
+	switch t {
+	case InvalidEmailFormat, InvalidURIFormat, InvalidPasswordLength, InvalidUserId, InvalidNameLength, InvalidInput:
+		return http.StatusBadRequest
+	case ResourceNotFound:
+		return http.StatusNotFound
+	case SQLUniqueViolated, ResourceConflict:
+		return http.StatusConflict
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	case PermissionDenied:
+		return http.StatusForbidden
+	case PreconditionFailed:
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Code returns the default machine readable code of this type
+func (t ErrorType) Code() string {
+	switch t {
+	case InvalidEmailFormat:
+		return "INVALID_EMAIL"
+	case InvalidURIFormat:
+		return "INVALID_URI"
+	case InvalidPasswordLength:
+		return "INVALID_PASSWORD"
+	case InvalidUserId:
+		return "INVALID_USER_ID"
+	case InvalidNameLength:
+		return "INVALID_NAME"
+	case InvalidInput:
+		return "INVALID_INPUT"
+	case ResourceNotFound:
+		return "NOT_FOUND"
+	case SQLUniqueViolated:
+		return "ALREADY_EXISTS"
+	case ResourceConflict:
+		return "CONFLICT"
+	case Unauthenticated:
+		return "UNAUTHORIZED"
+	case PermissionDenied:
+		return "FORBIDDEN"
+	case PreconditionFailed:
+		return "PRECONDITION_FAILED"
+	default:
+		return "INTERNAL_ERROR"
+	}
+}
+
+// FieldError describes why a single request field was rejected. Reason is
+// the machine readable rule that failed, e.g. required or max.
+type FieldError struct {
+	Field   string `json:"field"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+// GoGoError is the error type shared by repositories, services and handlers.
+// Type decides the HTTP status, Code is what clients branch on and Message
+// is safe to show them; Err keeps the underlying cause for logs.
 type GoGoError struct {
 	Type    ErrorType
+	Code    string
 	Message string
+	Details []FieldError
 	Err     error
 }
 
 func (e *GoGoError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
 	return fmt.Sprintf("[%s]: %s", e.Message, e.Err)
 }
 
+func (e *GoGoError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports errors with the same code as equal, so copies made by
+// WithDetails or Wrap still match their sentinel with errors.Is.
+func (e *GoGoError) Is(target error) bool {
+	t, ok := target.(*GoGoError)
+	if !ok {
+		return false
+	}
+	return e.Type == t.Type && e.ErrorCode() == t.ErrorCode()
+}
+
+// ErrorCode returns Code, or the default code of Type when Code is empty
+func (e *GoGoError) ErrorCode() string {
+	if e.Code != "" {
+		return e.Code
+	}
+	return e.Type.Code()
+}
+
+// WithDetails returns a copy of the error carrying field details
+func (e *GoGoError) WithDetails(details ...FieldError) *GoGoError {
+	clone := *e
+	clone.Details = append(append([]FieldError{}, e.Details...), details...)
+	return &clone
+}
+
+// Wrap returns a copy of the error caused by err
+func (e *GoGoError) Wrap(err error) *GoGoError {
+	clone := *e
+	clone.Err = err
+	return &clone
+}
+
+// NewError creates an error, typically a package level sentinel
+func NewError(errorType ErrorType, code string, message string) *GoGoError {
+	return &GoGoError{
+		Type:    errorType,
+		Code:    code,
+		Message: message,
+	}
+}
+
 func WrapError(err error, errorType ErrorType, message string) *GoGoError {
 	return &GoGoError{
 		Type:    errorType,
@@ -37,6 +159,29 @@ func WrapError(err error, errorType ErrorType, message string) *GoGoError {
 	}
 }
 
+// AsGoGoError finds the GoGoError in err's chain. Errors of any other kind
+// are reported as internal errors.
+func AsGoGoError(err error) *GoGoError {
+	var gogoErr *GoGoError
+	if errors.As(err, &gogoErr) {
+		return gogoErr
+	}
+	return WrapError(err, InternalError, "Internal Server Error")
+}
+
+// ErrValidationFailed is returned with the failing fields as details when a
+// request doesn't satisfy its `validate` tags
+var ErrValidationFailed = NewError(InvalidInput, "VALIDATION_FAILED", "request validation failed")
+
+// ErrMissingClaims is returned when a request reaches code that needs the
+// current manager without going through the auth middleware
+var ErrMissingClaims = NewError(Unauthenticated, "UNAUTHORIZED", "unauthorized: missing or invalid JWT claims")
+
+// ErrPreconditionFailed is returned when an update's If-Match doesn't hold
+// the current version of the resource, another update went through since
+// it was read
+var ErrPreconditionFailed = NewError(PreconditionFailed, "PRECONDITION_FAILED", "the resource was modified since it was read")
+
 func NoError(t assert.TestingT, err *GoGoError, msgAndArgs ...interface{}) bool {
 	var e error
 	if err != nil {
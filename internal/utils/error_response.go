@@ -2,31 +2,97 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strings"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
 )
 
+// ErrorResponse is the body of every error response
 type ErrorResponse struct {
-    Status  int    `json:"status"`
-    Message string `json:"message"`
-    Code    string `json:"code,omitempty"`
-    Details string `json:"details,omitempty"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
 }
 
-type errorResponse struct {
-	ErrorMessage string `json:"error_message"`
+// ErrorRecorder is implemented by response writers that log the error behind
+// a response, see middleware.LoggerMiddleware
+type ErrorRecorder interface {
+	RecordError(err error)
 }
 
+// WriteError maps err to its HTTP status and writes it as an ErrorResponse.
+// Messages of internal errors are not exposed, the cause is logged instead.
+func WriteError(w http.ResponseWriter, err error) {
+	gogoErr := AsGoGoError(err)
+	status := gogoErr.Type.Status()
+
+	response := ErrorResponse{
+		Code:    gogoErr.ErrorCode(),
+		Message: gogoErr.Message,
+		Details: gogoErr.Details,
+	}
+
+	// Keep the detail appended to a sentinel, e.g.
+	// fmt.Errorf("%w: endDate must not be before startDate", ErrInvalidLeaveRequest),
+	// but not the context callers prefixed it with
+	if gogoErr.Err == nil {
+		response.Message = detailedMessage(err, gogoErr)
+	}
+
+	if status >= http.StatusInternalServerError {
+		if recorder, ok := w.(ErrorRecorder); ok {
+			recorder.RecordError(err)
+		} else {
+			slog.Error("internal error", "request_id", w.Header().Get(constants.RequestIDHeader), "error", err)
+		}
+		response.Message = "Internal Server Error"
+	}
+
+	writeErrorResponse(w, status, response)
+}
+
+// detailedMessage returns the message of the outermost error of err's chain
+// appending a detail to gogoErr, or the message of gogoErr without any
+func detailedMessage(err error, gogoErr *GoGoError) string {
+	for ; err != nil && err != error(gogoErr); err = errors.Unwrap(err) {
+		if message := err.Error(); strings.HasPrefix(message, gogoErr.Message+": ") {
+			return message
+		}
+	}
+	return gogoErr.Message
+}
+
+// SendErrorResponse writes an error response with a code derived from the
+// status, for errors detected by the handlers themselves.
 func SendErrorResponse(w http.ResponseWriter, msg string, statusCode int) {
+	writeErrorResponse(w, statusCode, ErrorResponse{
+		Code:    codeForStatus(statusCode),
+		Message: msg,
+	})
+}
+
+func writeErrorResponse(w http.ResponseWriter, statusCode int, response ErrorResponse) {
+	response.RequestID = w.Header().Get(constants.RequestIDHeader)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
-	response := errorResponse{
-		ErrorMessage: msg,
-	}
-
 	err := json.NewEncoder(w).Encode(response)
 	if err != nil {
-		http.Error(w, msg, statusCode)
+		http.Error(w, response.Message, statusCode)
 		return
 	}
 }
+
+// codeForStatus turns a status into a code, e.g. 405 becomes METHOD_NOT_ALLOWED
+func codeForStatus(statusCode int) string {
+	text := http.StatusText(statusCode)
+	if text == "" {
+		return "ERROR"
+	}
+	return strings.ToUpper(strings.ReplaceAll(text, " ", "_"))
+}
@@ -0,0 +1,93 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/constants"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+var errThingNotFound = utils.NewError(utils.ResourceNotFound, "THING_NOT_FOUND", "thing not found")
+
+func decodeErrorResponse(t *testing.T, rec *httptest.ResponseRecorder) utils.ErrorResponse {
+	var response utils.ErrorResponse
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	return response
+}
+
+func TestWriteError_Sentinel(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set(constants.RequestIDHeader, "req-1")
+
+	utils.WriteError(rec, fmt.Errorf("finding thing: %w", errThingNotFound))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	response := decodeErrorResponse(t, rec)
+	assert.Equal(t, "THING_NOT_FOUND", response.Code)
+	assert.Equal(t, "thing not found", response.Message)
+	assert.Equal(t, "req-1", response.RequestID)
+}
+
+func TestWriteError_SentinelDetail(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	detailed := fmt.Errorf("%w: id must be positive", errThingNotFound)
+	utils.WriteError(rec, fmt.Errorf("finding thing: %w", detailed))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	response := decodeErrorResponse(t, rec)
+	assert.Equal(t, "THING_NOT_FOUND", response.Code)
+	assert.Equal(t, "thing not found: id must be positive", response.Message)
+}
+
+func TestWriteError_Details(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	err := utils.NewError(utils.InvalidInput, "", "invalid request").WithDetails(utils.FieldError{Field: "name", Message: "is required"})
+	utils.WriteError(rec, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	response := decodeErrorResponse(t, rec)
+	assert.Equal(t, "INVALID_INPUT", response.Code)
+	assert.Equal(t, []utils.FieldError{{Field: "name", Message: "is required"}}, response.Details)
+}
+
+func TestWriteError_HidesInternalErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	utils.WriteError(rec, errors.New("pq: connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	response := decodeErrorResponse(t, rec)
+	assert.Equal(t, "INTERNAL_ERROR", response.Code)
+	assert.Equal(t, "Internal Server Error", response.Message)
+}
+
+func TestGoGoError_Is(t *testing.T) {
+	cause := errors.New("sql: no rows in result set")
+	wrapped := errThingNotFound.Wrap(cause)
+
+	assert.ErrorIs(t, wrapped, errThingNotFound)
+	assert.ErrorIs(t, wrapped, cause)
+	assert.ErrorIs(t, errThingNotFound.WithDetails(utils.FieldError{Field: "id"}), errThingNotFound)
+	assert.NotErrorIs(t, wrapped, utils.ErrMissingClaims)
+}
+
+func TestSendErrorResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	utils.SendErrorResponse(rec, "Method not allowed", http.StatusMethodNotAllowed)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	response := decodeErrorResponse(t, rec)
+	assert.Equal(t, "METHOD_NOT_ALLOWED", response.Code)
+	assert.Equal(t, "Method not allowed", response.Message)
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/ngikut-project-sprint/GoGoManager/internal/validators"
+import (
+	"time"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/validators"
+)
 
 type ManagerResponse struct {
 	Email           string `json:"email"`
@@ -10,13 +14,16 @@ type ManagerResponse struct {
 	CompanyImageUri string `json:"companyImageUri"`
 }
 type ManagerRequest struct {
-	ID              int
-	Email           *string `json:"email"`
-	Password        *string `json:"password"`
-	Name            *string `json:"name"`
-	UserImageUri    *string `json:"userImageUri"`
-	CompanyName     *string `json:"companyName"`
-	CompanyImageUri *string `json:"companyImageUri"`
+	ID              int     `json:"-"`
+	Email           *string `json:"email" validate:"omitempty,email"`
+	Password        *string `json:"password" validate:"omitempty,min=8,max=32"`
+	Name            *string `json:"name" validate:"omitempty,min=4,max=52"`
+	UserImageUri    *string `json:"userImageUri" validate:"omitempty,httpuri"`
+	CompanyName     *string `json:"companyName" validate:"omitempty,min=4,max=52"`
+	CompanyImageUri *string `json:"companyImageUri" validate:"omitempty,httpuri"`
+	// IfUpdatedAt fails the update with ErrPreconditionFailed unless the
+	// manager was last updated at it, see the If-Match header
+	IfUpdatedAt *time.Time `json:"-"`
 }
 
 func (m ManagerRequest) ValidEmail() bool {
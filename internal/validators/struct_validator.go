@@ -0,0 +1,116 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldViolation is a request field failing one of its `validate` rules
+type FieldViolation struct {
+	// Field is the JSON path of the field, e.g. identityNumbers[2]
+	Field string
+	// Rule is the failing rule, e.g. required or min
+	Rule    string
+	Message string
+}
+
+var structValidator = newStructValidator()
+
+func newStructValidator() *validator.Validate {
+	v := validator.New()
+
+	// Report fields by their JSON name
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// httpuri enforces the same rules as ValidateURI
+	if err := v.RegisterValidation("httpuri", func(fl validator.FieldLevel) bool {
+		return ValidateURI(fl.Field().String()) == nil
+	}); err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// ValidateStruct evaluates the `validate` tags of a request struct and
+// returns every failing field, or nil when the struct is valid.
+func ValidateStruct(s interface{}) []FieldViolation {
+	err := structValidator.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []FieldViolation{{Rule: "invalid", Message: err.Error()}}
+	}
+
+	violations := make([]FieldViolation, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		violations = append(violations, FieldViolation{
+			Field:   fieldPath(fieldErr.Namespace()),
+			Rule:    fieldErr.Tag(),
+			Message: violationMessage(fieldErr),
+		})
+	}
+
+	return violations
+}
+
+// fieldPath drops the struct name and embedded structs from a namespace,
+// e.g. BulkMoveRequest.BulkEmployeeRequest.identityNumbers[0] becomes
+// identityNumbers[0]. JSON names start in lower case, so the segments
+// starting in upper case are Go type names.
+func fieldPath(namespace string) string {
+	segments := strings.Split(namespace, ".")
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment != "" && unicode.IsUpper(rune(segment[0])) {
+			continue
+		}
+		path = append(path, segment)
+	}
+	return strings.Join(path, ".")
+}
+
+func violationMessage(fieldErr validator.FieldError) string {
+	kind := fieldErr.Kind()
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		switch kind {
+		case reflect.String:
+			return fmt.Sprintf("must be at least %s characters", fieldErr.Param())
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("must contain at least %s items", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "max":
+		switch kind {
+		case reflect.String:
+			return fmt.Sprintf("must be at most %s characters", fieldErr.Param())
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("must contain at most %s items", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fieldErr.Param(), " ", ", "))
+	case "email":
+		return "must be a valid email address"
+	case "url", "httpuri":
+		return "must be a valid http or https URL"
+	}
+	return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+}
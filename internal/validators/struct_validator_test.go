@@ -0,0 +1,72 @@
+package validators_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/models"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/validators"
+)
+
+func TestValidateStruct_Valid(t *testing.T) {
+	req := models.CreateEmployeeRequest{
+		IdentityNumber:   "12345",
+		Name:             "Jane Doe",
+		EmployeeImageURI: "https://example.com/jane.png",
+		Gender:           models.Female,
+		DepartmentID:     1,
+	}
+
+	assert.Nil(t, validators.ValidateStruct(req))
+}
+
+func TestValidateStruct_ReportsEveryField(t *testing.T) {
+	req := models.CreateEmployeeRequest{
+		IdentityNumber:   "123",
+		EmployeeImageURI: "not a url",
+		Gender:           "other",
+		DepartmentID:     1,
+	}
+
+	violations := validators.ValidateStruct(req)
+
+	assert.Equal(t, []validators.FieldViolation{
+		{Field: "identityNumber", Rule: "min", Message: "must be at least 5 characters"},
+		{Field: "name", Rule: "required", Message: "is required"},
+		{Field: "employeeImageUri", Rule: "url", Message: "must be a valid http or https URL"},
+		{Field: "gender", Rule: "oneof", Message: "must be one of: male, female"},
+	}, violations)
+}
+
+func TestValidateStruct_EmbeddedAndSlices(t *testing.T) {
+	req := models.BulkMoveRequest{
+		BulkEmployeeRequest: models.BulkEmployeeRequest{
+			IdentityNumbers: []string{"12345", "1"},
+		},
+	}
+
+	violations := validators.ValidateStruct(req)
+
+	assert.Equal(t, []validators.FieldViolation{
+		{Field: "identityNumbers[1]", Rule: "min", Message: "must be at least 5 characters"},
+		{Field: "departmentId", Rule: "required", Message: "is required"},
+	}, violations)
+}
+
+func TestValidateStruct_ManagerRules(t *testing.T) {
+	name := "Bob"
+	uri := "ftp://example.com/logo.png"
+	req := utils.ManagerRequest{
+		Name:            &name,
+		CompanyImageUri: &uri,
+	}
+
+	violations := validators.ValidateStruct(req)
+
+	assert.Equal(t, []validators.FieldViolation{
+		{Field: "name", Rule: "min", Message: "must be at least 4 characters"},
+		{Field: "companyImageUri", Rule: "httpuri", Message: "must be a valid http or https URL"},
+	}, violations)
+}
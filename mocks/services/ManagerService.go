@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	models "github.com/ngikut-project-sprint/GoGoManager/internal/models"
 	mock "github.com/stretchr/testify/mock"
 
@@ -22,9 +24,9 @@ func (_m *ManagerService) EXPECT() *ManagerService_Expecter {
 	return &ManagerService_Expecter{mock: &_m.Mock}
 }
 
-// Create provides a mock function with given fields: email, password
-func (_m *ManagerService) Create(email string, password string) (int, *utils.GoGoError) {
-	ret := _m.Called(email, password)
+// Create provides a mock function with given fields: ctx, email, password
+func (_m *ManagerService) Create(ctx context.Context, email string, password string) (int, *utils.GoGoError) {
+	ret := _m.Called(ctx, email, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
@@ -32,17 +34,17 @@ func (_m *ManagerService) Create(email string, password string) (int, *utils.GoG
 
 	var r0 int
 	var r1 *utils.GoGoError
-	if rf, ok := ret.Get(0).(func(string, string) (int, *utils.GoGoError)); ok {
-		return rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int, *utils.GoGoError)); ok {
+		return rf(ctx, email, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) int); ok {
-		r0 = rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = rf(ctx, email, password)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) *utils.GoGoError); ok {
-		r1 = rf(email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *utils.GoGoError); ok {
+		r1 = rf(ctx, email, password)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.GoGoError)
@@ -58,15 +60,16 @@ type ManagerService_Create_Call struct {
 }
 
 // Create is a helper method to define mock.On call
+//   - ctx context.Context
 //   - email string
 //   - password string
-func (_e *ManagerService_Expecter) Create(email interface{}, password interface{}) *ManagerService_Create_Call {
-	return &ManagerService_Create_Call{Call: _e.mock.On("Create", email, password)}
+func (_e *ManagerService_Expecter) Create(ctx interface{}, email interface{}, password interface{}) *ManagerService_Create_Call {
+	return &ManagerService_Create_Call{Call: _e.mock.On("Create", ctx, email, password)}
 }
 
-func (_c *ManagerService_Create_Call) Run(run func(email string, password string)) *ManagerService_Create_Call {
+func (_c *ManagerService_Create_Call) Run(run func(ctx context.Context, email string, password string)) *ManagerService_Create_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
@@ -76,14 +79,14 @@ func (_c *ManagerService_Create_Call) Return(_a0 int, _a1 *utils.GoGoError) *Man
 	return _c
 }
 
-func (_c *ManagerService_Create_Call) RunAndReturn(run func(string, string) (int, *utils.GoGoError)) *ManagerService_Create_Call {
+func (_c *ManagerService_Create_Call) RunAndReturn(run func(context.Context, string, string) (int, *utils.GoGoError)) *ManagerService_Create_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAll provides a mock function with no fields
-func (_m *ManagerService) GetAll() ([]models.Manager, *utils.GoGoError) {
-	ret := _m.Called()
+// GetAll provides a mock function with given fields: ctx
+func (_m *ManagerService) GetAll(ctx context.Context) ([]models.Manager, *utils.GoGoError) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
@@ -91,19 +94,19 @@ func (_m *ManagerService) GetAll() ([]models.Manager, *utils.GoGoError) {
 
 	var r0 []models.Manager
 	var r1 *utils.GoGoError
-	if rf, ok := ret.Get(0).(func() ([]models.Manager, *utils.GoGoError)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Manager, *utils.GoGoError)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []models.Manager); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Manager); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Manager)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() *utils.GoGoError); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) *utils.GoGoError); ok {
+		r1 = rf(ctx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.GoGoError)
@@ -119,13 +122,14 @@ type ManagerService_GetAll_Call struct {
 }
 
 // GetAll is a helper method to define mock.On call
-func (_e *ManagerService_Expecter) GetAll() *ManagerService_GetAll_Call {
-	return &ManagerService_GetAll_Call{Call: _e.mock.On("GetAll")}
+//   - ctx context.Context
+func (_e *ManagerService_Expecter) GetAll(ctx interface{}) *ManagerService_GetAll_Call {
+	return &ManagerService_GetAll_Call{Call: _e.mock.On("GetAll", ctx)}
 }
 
-func (_c *ManagerService_GetAll_Call) Run(run func()) *ManagerService_GetAll_Call {
+func (_c *ManagerService_GetAll_Call) Run(run func(ctx context.Context)) *ManagerService_GetAll_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context))
 	})
 	return _c
 }
@@ -135,14 +139,14 @@ func (_c *ManagerService_GetAll_Call) Return(_a0 []models.Manager, _a1 *utils.Go
 	return _c
 }
 
-func (_c *ManagerService_GetAll_Call) RunAndReturn(run func() ([]models.Manager, *utils.GoGoError)) *ManagerService_GetAll_Call {
+func (_c *ManagerService_GetAll_Call) RunAndReturn(run func(context.Context) ([]models.Manager, *utils.GoGoError)) *ManagerService_GetAll_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByEmail provides a mock function with given fields: email
-func (_m *ManagerService) GetByEmail(email string) (*models.Manager, *utils.GoGoError) {
-	ret := _m.Called(email)
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *ManagerService) GetByEmail(ctx context.Context, email string) (*models.Manager, *utils.GoGoError) {
+	ret := _m.Called(ctx, email)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByEmail")
@@ -150,19 +154,19 @@ func (_m *ManagerService) GetByEmail(email string) (*models.Manager, *utils.GoGo
 
 	var r0 *models.Manager
 	var r1 *utils.GoGoError
-	if rf, ok := ret.Get(0).(func(string) (*models.Manager, *utils.GoGoError)); ok {
-		return rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Manager, *utils.GoGoError)); ok {
+		return rf(ctx, email)
 	}
-	if rf, ok := ret.Get(0).(func(string) *models.Manager); ok {
-		r0 = rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Manager); ok {
+		r0 = rf(ctx, email)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Manager)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) *utils.GoGoError); ok {
-		r1 = rf(email)
+	if rf, ok := ret.Get(1).(func(context.Context, string) *utils.GoGoError); ok {
+		r1 = rf(ctx, email)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.GoGoError)
@@ -178,14 +182,15 @@ type ManagerService_GetByEmail_Call struct {
 }
 
 // GetByEmail is a helper method to define mock.On call
+//   - ctx context.Context
 //   - email string
-func (_e *ManagerService_Expecter) GetByEmail(email interface{}) *ManagerService_GetByEmail_Call {
-	return &ManagerService_GetByEmail_Call{Call: _e.mock.On("GetByEmail", email)}
+func (_e *ManagerService_Expecter) GetByEmail(ctx interface{}, email interface{}) *ManagerService_GetByEmail_Call {
+	return &ManagerService_GetByEmail_Call{Call: _e.mock.On("GetByEmail", ctx, email)}
 }
 
-func (_c *ManagerService_GetByEmail_Call) Run(run func(email string)) *ManagerService_GetByEmail_Call {
+func (_c *ManagerService_GetByEmail_Call) Run(run func(ctx context.Context, email string)) *ManagerService_GetByEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
@@ -195,14 +200,14 @@ func (_c *ManagerService_GetByEmail_Call) Return(_a0 *models.Manager, _a1 *utils
 	return _c
 }
 
-func (_c *ManagerService_GetByEmail_Call) RunAndReturn(run func(string) (*models.Manager, *utils.GoGoError)) *ManagerService_GetByEmail_Call {
+func (_c *ManagerService_GetByEmail_Call) RunAndReturn(run func(context.Context, string) (*models.Manager, *utils.GoGoError)) *ManagerService_GetByEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *ManagerService) GetByID(id int) (*models.Manager, *utils.GoGoError) {
-	ret := _m.Called(id)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *ManagerService) GetByID(ctx context.Context, id int) (*models.Manager, *utils.GoGoError) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -210,19 +215,19 @@ func (_m *ManagerService) GetByID(id int) (*models.Manager, *utils.GoGoError) {
 
 	var r0 *models.Manager
 	var r1 *utils.GoGoError
-	if rf, ok := ret.Get(0).(func(int) (*models.Manager, *utils.GoGoError)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Manager, *utils.GoGoError)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *models.Manager); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Manager); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Manager)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) *utils.GoGoError); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) *utils.GoGoError); ok {
+		r1 = rf(ctx, id)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*utils.GoGoError)
@@ -238,14 +243,15 @@ type ManagerService_GetByID_Call struct {
 }
 
 // GetByID is a helper method to define mock.On call
+//   - ctx context.Context
 //   - id int
-func (_e *ManagerService_Expecter) GetByID(id interface{}) *ManagerService_GetByID_Call {
-	return &ManagerService_GetByID_Call{Call: _e.mock.On("GetByID", id)}
+func (_e *ManagerService_Expecter) GetByID(ctx interface{}, id interface{}) *ManagerService_GetByID_Call {
+	return &ManagerService_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
 }
 
-func (_c *ManagerService_GetByID_Call) Run(run func(id int)) *ManagerService_GetByID_Call {
+func (_c *ManagerService_GetByID_Call) Run(run func(ctx context.Context, id int)) *ManagerService_GetByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
@@ -255,22 +261,22 @@ func (_c *ManagerService_GetByID_Call) Return(_a0 *models.Manager, _a1 *utils.Go
 	return _c
 }
 
-func (_c *ManagerService_GetByID_Call) RunAndReturn(run func(int) (*models.Manager, *utils.GoGoError)) *ManagerService_GetByID_Call {
+func (_c *ManagerService_GetByID_Call) RunAndReturn(run func(context.Context, int) (*models.Manager, *utils.GoGoError)) *ManagerService_GetByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Update provides a mock function with given fields: manager
-func (_m *ManagerService) Update(manager *utils.ManagerRequest) *utils.GoGoError {
-	ret := _m.Called(manager)
+// Update provides a mock function with given fields: ctx, manager
+func (_m *ManagerService) Update(ctx context.Context, manager *utils.ManagerRequest) *utils.GoGoError {
+	ret := _m.Called(ctx, manager)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 *utils.GoGoError
-	if rf, ok := ret.Get(0).(func(*utils.ManagerRequest) *utils.GoGoError); ok {
-		r0 = rf(manager)
+	if rf, ok := ret.Get(0).(func(context.Context, *utils.ManagerRequest) *utils.GoGoError); ok {
+		r0 = rf(ctx, manager)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*utils.GoGoError)
@@ -286,14 +292,15 @@ type ManagerService_Update_Call struct {
 }
 
 // Update is a helper method to define mock.On call
+//   - ctx context.Context
 //   - manager *utils.ManagerRequest
-func (_e *ManagerService_Expecter) Update(manager interface{}) *ManagerService_Update_Call {
-	return &ManagerService_Update_Call{Call: _e.mock.On("Update", manager)}
+func (_e *ManagerService_Expecter) Update(ctx interface{}, manager interface{}) *ManagerService_Update_Call {
+	return &ManagerService_Update_Call{Call: _e.mock.On("Update", ctx, manager)}
 }
 
-func (_c *ManagerService_Update_Call) Run(run func(manager *utils.ManagerRequest)) *ManagerService_Update_Call {
+func (_c *ManagerService_Update_Call) Run(run func(ctx context.Context, manager *utils.ManagerRequest)) *ManagerService_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*utils.ManagerRequest))
+		run(args[0].(context.Context), args[1].(*utils.ManagerRequest))
 	})
 	return _c
 }
@@ -303,7 +310,7 @@ func (_c *ManagerService_Update_Call) Return(_a0 *utils.GoGoError) *ManagerServi
 	return _c
 }
 
-func (_c *ManagerService_Update_Call) RunAndReturn(run func(*utils.ManagerRequest) *utils.GoGoError) *ManagerService_Update_Call {
+func (_c *ManagerService_Update_Call) RunAndReturn(run func(context.Context, *utils.ManagerRequest) *utils.GoGoError) *ManagerService_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,42 @@
+//go:build contract
+
+package contract
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const attendancePath = "/v1/attendance"
+
+// Attendance has no contract document, this checks the schedules, stored as
+// an int2[], read back on both drivers
+func TestAttendanceSchedules(t *testing.T) {
+	_, token := register(t)
+	id := departmentID(t, createDepartment(t, token, "Operations"))
+
+	schedule := map[string]interface{}{
+		"startTime":    "08:00",
+		"endTime":      "16:00",
+		"graceMinutes": 10,
+		"workDays":     []int{1, 2, 3},
+		"timezone":     "UTC",
+	}
+	expectStatus(t, do(t, http.MethodPut, attendancePath+"/schedule/"+id, token, schedule), http.StatusOK)
+
+	res := do(t, http.MethodGet, attendancePath+"/schedule/"+id, token, nil)
+	expectStatus(t, res, http.StatusOK)
+	var saved struct {
+		StartTime string `json:"startTime"`
+		WorkDays  []int  `json:"workDays"`
+	}
+	res.JSON(t, &saved)
+	if saved.StartTime != "08:00" || !reflect.DeepEqual(saved.WorkDays, []int{1, 2, 3}) {
+		t.Errorf("expected the saved schedule, got %s", res.Body)
+	}
+
+	// The daily attendance and report read every schedule of the manager
+	expectStatus(t, do(t, http.MethodGet, attendancePath+"?departmentId="+id, token, nil), http.StatusOK)
+	expectStatus(t, do(t, http.MethodGet, attendancePath+"/report?departmentId="+id, token, nil), http.StatusOK)
+}
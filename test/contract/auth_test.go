@@ -0,0 +1,66 @@
+//go:build contract
+
+package contract
+
+import (
+	"net/http"
+	"testing"
+)
+
+// docs/requirements/auth_contract.md
+func TestAuthContract(t *testing.T) {
+	credential := func(email, password, action string) map[string]string {
+		return map[string]string{"email": email, "password": password, "action": action}
+	}
+
+	t.Run("201 for a new user", func(t *testing.T) {
+		email := uniqueEmail()
+		res := do(t, http.MethodPost, "/v1/auth", "", credential(email, "password123", "create"))
+		expectStatus(t, res, http.StatusCreated)
+
+		var body map[string]interface{}
+		res.JSON(t, &body)
+		expectKeys(t, body, "email", "token")
+		if body["email"] != email {
+			t.Errorf("expected email %s, got %v", email, body["email"])
+		}
+	})
+
+	t.Run("200 for an existing user", func(t *testing.T) {
+		email, _ := register(t)
+		res := do(t, http.MethodPost, "/v1/auth", "", credential(email, "password123", "login"))
+		expectStatus(t, res, http.StatusOK)
+
+		var body map[string]interface{}
+		res.JSON(t, &body)
+		expectKeys(t, body, "email", "token")
+	})
+
+	t.Run("400 for validation errors", func(t *testing.T) {
+		for name, body := range map[string]interface{}{
+			"invalid email":      credential("not-an-email", "password123", "create"),
+			"short password":     credential(uniqueEmail(), "short", "create"),
+			"long password":      credential(uniqueEmail(), "password-longer-than-thirty-two-characters", "create"),
+			"unknown action":     credential(uniqueEmail(), "password123", "delete"),
+			"missing fields":     map[string]string{},
+			"malformed json":     `{"email": `,
+			"wrong field type":   map[string]interface{}{"email": 1, "password": "password123", "action": "create"},
+			"login invalid body": credential("not-an-email", "password123", "login"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				expectStatus(t, do(t, http.MethodPost, "/v1/auth", "", body), http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("401 for an unknown email on login", func(t *testing.T) {
+		res := do(t, http.MethodPost, "/v1/auth", "", credential(uniqueEmail(), "password123", "login"))
+		expectStatus(t, res, http.StatusUnauthorized)
+	})
+
+	t.Run("409 for an existing email on create", func(t *testing.T) {
+		email, _ := register(t)
+		res := do(t, http.MethodPost, "/v1/auth", "", credential(email, "password123", "create"))
+		expectStatus(t, res, http.StatusConflict)
+	})
+}
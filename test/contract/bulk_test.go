@@ -0,0 +1,129 @@
+//go:build contract
+
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// bulkResult is the part of a bulk response the tests check
+type bulkResult struct {
+	Applied   bool `json:"applied"`
+	Succeeded int  `json:"succeeded"`
+	Failed    int  `json:"failed"`
+}
+
+// listEmployees returns the employees of a department
+func listEmployees(t *testing.T, token string, departmentID interface{}) []map[string]interface{} {
+	t.Helper()
+
+	query := url.Values{"departmentId": {fmt.Sprint(departmentID)}, "limit": {"100"}}
+	res := do(t, http.MethodGet, employeePath+"?"+query.Encode(), token, nil)
+	expectStatus(t, res, http.StatusOK)
+
+	var employees []map[string]interface{}
+	res.JSON(t, &employees)
+	return employees
+}
+
+// Bulk operations and merges have no contract document, this checks the
+// arrays they query with are passed on both drivers
+func TestBulkEmployees(t *testing.T) {
+	_, token := register(t)
+	sales := createDepartment(t, token, "Sales")["departmentId"]
+	marketing := createDepartment(t, token, "Marketing")["departmentId"]
+
+	identityNumbers := []string{uniqueIdentityNumber(""), uniqueIdentityNumber("")}
+	for _, identityNumber := range identityNumbers {
+		createEmployee(t, token, employeeBody(identityNumber, "male", sales))
+	}
+
+	t.Run("move by identity numbers", func(t *testing.T) {
+		res := do(t, http.MethodPatch, employeePath+"/bulk", token, map[string]interface{}{
+			"identityNumbers": identityNumbers,
+			"departmentId":    marketing,
+		})
+		expectStatus(t, res, http.StatusOK)
+		var result bulkResult
+		res.JSON(t, &result)
+		if !result.Applied || result.Succeeded != 2 {
+			t.Errorf("expected 2 employees moved, got %s", res.Body)
+		}
+		if got := len(listEmployees(t, token, marketing)); got != 2 {
+			t.Errorf("expected 2 employees in the new department, got %d", got)
+		}
+	})
+
+	t.Run("unknown identity numbers roll back", func(t *testing.T) {
+		res := do(t, http.MethodPatch, employeePath+"/bulk", token, map[string]interface{}{
+			"identityNumbers": append([]string{uniqueIdentityNumber("")}, identityNumbers...),
+			"departmentId":    sales,
+		})
+		expectStatus(t, res, http.StatusUnprocessableEntity)
+		if got := len(listEmployees(t, token, marketing)); got != 2 {
+			t.Errorf("expected no employee moved, got %d left in the department", got)
+		}
+	})
+
+	t.Run("delete by filter", func(t *testing.T) {
+		res := do(t, http.MethodDelete, employeePath+"/bulk", token, map[string]interface{}{
+			"filter": map[string]interface{}{"departmentId": marketing},
+		})
+		expectStatus(t, res, http.StatusOK)
+		var result bulkResult
+		res.JSON(t, &result)
+		if !result.Applied || result.Succeeded != 2 {
+			t.Errorf("expected 2 employees deleted, got %s", res.Body)
+		}
+		if got := len(listEmployees(t, token, marketing)); got != 0 {
+			t.Errorf("expected no employee left, got %d", got)
+		}
+	})
+}
+
+func TestMergeDepartments(t *testing.T) {
+	_, token := register(t)
+	target := createDepartment(t, token, "Operations")["departmentId"]
+	sources := []interface{}{
+		createDepartment(t, token, "Logistics")["departmentId"],
+		createDepartment(t, token, "Warehouse")["departmentId"],
+	}
+	for _, source := range sources {
+		createEmployee(t, token, employeeBody(uniqueIdentityNumber(""), "female", source))
+	}
+
+	t.Run("another manager's department", func(t *testing.T) {
+		_, other := register(t)
+		res := do(t, http.MethodPost, departmentPath+"/merge", other, map[string]interface{}{
+			"sourceDepartmentIds": sources,
+			"targetDepartmentId":  createDepartment(t, other, "Takeover")["departmentId"],
+		})
+		expectStatus(t, res, http.StatusNotFound)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		res := do(t, http.MethodPost, departmentPath+"/merge", token, map[string]interface{}{
+			"sourceDepartmentIds": sources,
+			"targetDepartmentId":  target,
+		})
+		expectStatus(t, res, http.StatusOK)
+		var result struct {
+			MovedEmployees int `json:"movedEmployees"`
+		}
+		res.JSON(t, &result)
+		if result.MovedEmployees != 2 {
+			t.Errorf("expected 2 employees moved, got %s", res.Body)
+		}
+		if got := len(listEmployees(t, token, target)); got != 2 {
+			t.Errorf("expected 2 employees in the target department, got %d", got)
+		}
+
+		// The sources are gone
+		for _, source := range sources {
+			expectStatus(t, do(t, http.MethodDelete, fmt.Sprintf("%s/%v", departmentPath, source), token, nil), http.StatusNotFound)
+		}
+	})
+}
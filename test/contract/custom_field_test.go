@@ -0,0 +1,59 @@
+//go:build contract
+
+package contract
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+const customFieldPath = "/v1/custom-field"
+
+// Custom fields have no contract document, this checks the filters match
+// the values as stored, numbers and booleans included
+func TestCustomFieldFilter(t *testing.T) {
+	_, token := register(t)
+	for _, field := range []map[string]interface{}{
+		{"key": "salaryGrade", "label": "Salary grade", "type": "number"},
+		{"key": "remote", "label": "Remote", "type": "boolean"},
+		{"key": "jobTitle", "label": "Job title", "type": "string"},
+	} {
+		expectStatus(t, do(t, http.MethodPost, customFieldPath, token, field), http.StatusCreated)
+	}
+
+	department := createDepartment(t, token, "Engineering")["departmentId"]
+	matching := employeeBody(uniqueIdentityNumber(""), "female", department)
+	matching["customFields"] = map[string]interface{}{"salaryGrade": 4, "remote": true, "jobTitle": "Engineer"}
+	createEmployee(t, token, matching)
+	other := employeeBody(uniqueIdentityNumber(""), "male", department)
+	other["customFields"] = map[string]interface{}{"salaryGrade": 5, "remote": false, "jobTitle": "Engineer"}
+	createEmployee(t, token, other)
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  int
+	}{
+		{"string", url.Values{"field.jobTitle": {"Engineer"}}, 2},
+		{"number", url.Values{"field.salaryGrade": {"4"}}, 1},
+		{"boolean", url.Values{"field.remote": {"true"}}, 1},
+		{"all", url.Values{"field.salaryGrade": {"4.0"}, "field.remote": {"true"}, "field.jobTitle": {"Engineer"}}, 1},
+		{"not a number", url.Values{"field.salaryGrade": {"high"}}, 0},
+		{"unknown field", url.Values{"field.unknown": {"x"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Set("limit", "100")
+			res := do(t, http.MethodGet, employeePath+"?"+tt.query.Encode(), token, nil)
+			expectStatus(t, res, http.StatusOK)
+
+			var employees []map[string]interface{}
+			res.JSON(t, &employees)
+			if len(employees) != tt.want {
+				t.Errorf("expected %d employees, got %s", tt.want, res.Body)
+			}
+		})
+	}
+}
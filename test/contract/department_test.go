@@ -0,0 +1,170 @@
+//go:build contract
+
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const departmentPath = "/v1/department"
+
+// departmentID returns the id of a department payload as a path segment
+func departmentID(t *testing.T, department map[string]interface{}) string {
+	t.Helper()
+	id, ok := department["departmentId"]
+	if !ok {
+		t.Fatalf("expected key departmentId in %v", department)
+	}
+	return strings.TrimSuffix(fmt.Sprint(id), ".0")
+}
+
+// createDepartment creates a department and returns its payload
+func createDepartment(t *testing.T, token, name string) map[string]interface{} {
+	t.Helper()
+
+	res := do(t, http.MethodPost, departmentPath, token, map[string]string{"name": name})
+	expectStatus(t, res, http.StatusCreated)
+
+	var department map[string]interface{}
+	res.JSON(t, &department)
+	return department
+}
+
+// docs/requirements/departments_contract.md
+func TestDepartmentContract(t *testing.T) {
+	t.Run("POST 201", func(t *testing.T) {
+		_, token := register(t)
+		department := createDepartment(t, token, "Engineering")
+		expectKeys(t, department, "departmentId", "name")
+		if department["name"] != "Engineering" {
+			t.Errorf("expected name Engineering, got %v", department["name"])
+		}
+	})
+
+	t.Run("POST 400", func(t *testing.T) {
+		_, token := register(t)
+		for name, body := range map[string]interface{}{
+			"missing name":   map[string]string{},
+			"short name":     map[string]string{"name": "abc"},
+			"long name":      map[string]string{"name": strings.Repeat("a", 34)},
+			"malformed json": `{"name": `,
+		} {
+			t.Run(name, func(t *testing.T) {
+				expectStatus(t, do(t, http.MethodPost, departmentPath, token, body), http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("POST 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodPost, departmentPath, map[string]string{"name": "Engineering"})
+	})
+
+	t.Run("GET 200", func(t *testing.T) {
+		_, token := register(t)
+
+		// Departments of other tests share the database, tag the names to
+		// list only the ones created here
+		tag := fmt.Sprintf("T%d", nextID())
+		for i := 0; i < 6; i++ {
+			createDepartment(t, token, fmt.Sprintf("Dept %s %d", tag, i))
+		}
+
+		list := func(query string) []map[string]interface{} {
+			t.Helper()
+			res := do(t, http.MethodGet, departmentPath+"?name="+tag+query, token, nil)
+			expectStatus(t, res, http.StatusOK)
+
+			var departments []map[string]interface{}
+			res.JSON(t, &departments)
+			for _, department := range departments {
+				expectKeys(t, department, "departmentId", "name")
+				if name, _ := department["name"].(string); !strings.Contains(name, tag) {
+					t.Errorf("expected the name filter to match a substring, got %q", name)
+				}
+			}
+			return departments
+		}
+
+		if got := len(list("")); got != 5 {
+			t.Errorf("expected the default limit of 5, got %d departments", got)
+		}
+		if got := len(list("&limit=2&offset=5")); got != 1 {
+			t.Errorf("expected 1 department after offset 5, got %d", got)
+		}
+		if got := len(list("&limit=abc&offset=-1")); got != 5 {
+			t.Errorf("expected invalid limit and offset to use the defaults, got %d departments", got)
+		}
+
+		// Managers only list their own departments
+		_, other := register(t)
+		res := do(t, http.MethodGet, departmentPath+"?name="+tag, other, nil)
+		expectStatus(t, res, http.StatusOK)
+		var departments []map[string]interface{}
+		res.JSON(t, &departments)
+		if len(departments) != 0 {
+			t.Errorf("expected no department of another manager, got %d", len(departments))
+		}
+	})
+
+	t.Run("GET 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodGet, departmentPath, nil)
+	})
+
+	t.Run("PATCH 200", func(t *testing.T) {
+		_, token := register(t)
+		id := departmentID(t, createDepartment(t, token, "Engineering"))
+
+		res := do(t, http.MethodPatch, departmentPath+"/"+id, token, map[string]string{"name": "Platform"})
+		expectStatus(t, res, http.StatusOK)
+
+		var department map[string]interface{}
+		res.JSON(t, &department)
+		expectKeys(t, department, "departmentId", "name")
+		if department["name"] != "Platform" {
+			t.Errorf("expected name Platform, got %v", department["name"])
+		}
+	})
+
+	t.Run("PATCH 400", func(t *testing.T) {
+		_, token := register(t)
+		id := departmentID(t, createDepartment(t, token, "Engineering"))
+		for name, body := range map[string]interface{}{
+			"short name":     map[string]string{"name": "abc"},
+			"long name":      map[string]string{"name": strings.Repeat("a", 34)},
+			"malformed json": `{"name": `,
+		} {
+			t.Run(name, func(t *testing.T) {
+				expectStatus(t, do(t, http.MethodPatch, departmentPath+"/"+id, token, body), http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("PATCH 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodPatch, departmentPath+"/1", map[string]string{"name": "Platform"})
+	})
+
+	t.Run("PATCH 404", func(t *testing.T) {
+		_, token := register(t)
+		res := do(t, http.MethodPatch, departmentPath+"/999999", token, map[string]string{"name": "Platform"})
+		expectStatus(t, res, http.StatusNotFound)
+	})
+
+	t.Run("DELETE 200", func(t *testing.T) {
+		_, token := register(t)
+		id := departmentID(t, createDepartment(t, token, "Engineering"))
+		expectStatus(t, do(t, http.MethodDelete, departmentPath+"/"+id, token, nil), http.StatusOK)
+		expectStatus(t, do(t, http.MethodDelete, departmentPath+"/"+id, token, nil), http.StatusNotFound)
+	})
+
+	t.Run("DELETE 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodDelete, departmentPath+"/1", nil)
+	})
+
+	t.Run("DELETE 404", func(t *testing.T) {
+		_, token := register(t)
+		expectStatus(t, do(t, http.MethodDelete, departmentPath+"/999999", token, nil), http.StatusNotFound)
+	})
+}
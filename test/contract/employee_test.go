@@ -0,0 +1,243 @@
+//go:build contract
+
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const employeePath = "/v1/employee"
+
+var employeeKeys = []string{"identityNumber", "name", "employeeImageUri", "gender", "departmentId"}
+
+// uniqueIdentityNumber returns an unused identity number containing infix
+func uniqueIdentityNumber(infix string) string {
+	return fmt.Sprintf("ID%s%d", infix, nextID())
+}
+
+func employeeBody(identityNumber, gender string, departmentID interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"identityNumber":   identityNumber,
+		"name":             "Contract Employee",
+		"employeeImageUri": "https://example.com/employee.png",
+		"gender":           gender,
+		"departmentId":     departmentID,
+	}
+}
+
+// createEmployee creates an employee and returns its payload
+func createEmployee(t *testing.T, token string, body map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	res := do(t, http.MethodPost, employeePath, token, body)
+	expectStatus(t, res, http.StatusCreated)
+
+	var employee map[string]interface{}
+	res.JSON(t, &employee)
+	return employee
+}
+
+// docs/requirements/employee_contract.md
+func TestEmployeeContract(t *testing.T) {
+	t.Run("POST 201", func(t *testing.T) {
+		_, token := register(t)
+		department := createDepartment(t, token, "Engineering")
+		body := employeeBody(uniqueIdentityNumber(""), "female", department["departmentId"])
+
+		employee := createEmployee(t, token, body)
+		expectKeys(t, employee, employeeKeys...)
+		for _, key := range employeeKeys {
+			if fmt.Sprint(employee[key]) != fmt.Sprint(body[key]) {
+				t.Errorf("expected %s %v, got %v", key, body[key], employee[key])
+			}
+		}
+	})
+
+	t.Run("POST 400", func(t *testing.T) {
+		_, token := register(t)
+		departmentID := createDepartment(t, token, "Engineering")["departmentId"]
+
+		with := func(key string, value interface{}) map[string]interface{} {
+			body := employeeBody(uniqueIdentityNumber(""), "male", departmentID)
+			if value == nil {
+				delete(body, key)
+			} else {
+				body[key] = value
+			}
+			return body
+		}
+
+		for name, body := range map[string]interface{}{
+			"missing identity number": with("identityNumber", nil),
+			"short identity number":   with("identityNumber", "1234"),
+			"long identity number":    with("identityNumber", strings.Repeat("1", 34)),
+			"short name":              with("name", "abc"),
+			"long name":               with("name", strings.Repeat("a", 34)),
+			"invalid image uri":       with("employeeImageUri", "not a uri"),
+			"invalid gender":          with("gender", "other"),
+			"missing department":      with("departmentId", nil),
+			"unknown department":      with("departmentId", 999999),
+			"malformed json":          `{"name": `,
+		} {
+			t.Run(name, func(t *testing.T) {
+				expectStatus(t, do(t, http.MethodPost, employeePath, token, body), http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("POST 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodPost, employeePath, employeeBody(uniqueIdentityNumber(""), "male", 1))
+	})
+
+	t.Run("POST 409 for a used identity number", func(t *testing.T) {
+		_, token := register(t)
+		departmentID := createDepartment(t, token, "Engineering")["departmentId"]
+		body := employeeBody(uniqueIdentityNumber(""), "male", departmentID)
+
+		createEmployee(t, token, body)
+		expectStatus(t, do(t, http.MethodPost, employeePath, token, body), http.StatusConflict)
+	})
+
+	t.Run("GET 200", func(t *testing.T) {
+		_, token := register(t)
+		engineering := createDepartment(t, token, "Engineering")["departmentId"]
+		finance := createDepartment(t, token, "Finance")["departmentId"]
+
+		wildcard := uniqueIdentityNumber("11123333")
+		createEmployee(t, token, employeeBody(wildcard, "female", finance))
+		for i := 0; i < 5; i++ {
+			createEmployee(t, token, employeeBody(uniqueIdentityNumber(""), "male", engineering))
+		}
+
+		list := func(query url.Values) []map[string]interface{} {
+			t.Helper()
+			res := do(t, http.MethodGet, employeePath+"?"+query.Encode(), token, nil)
+			expectStatus(t, res, http.StatusOK)
+
+			var employees []map[string]interface{}
+			res.JSON(t, &employees)
+			for _, employee := range employees {
+				expectKeys(t, employee, employeeKeys...)
+			}
+			return employees
+		}
+
+		if got := len(list(url.Values{})); got != 5 {
+			t.Errorf("expected the default limit of 5, got %d employees", got)
+		}
+		if got := len(list(url.Values{"limit": {"4"}, "offset": {"4"}})); got != 2 {
+			t.Errorf("expected 2 employees after offset 4, got %d", got)
+		}
+		if got := len(list(url.Values{"limit": {"abc"}, "offset": {"-1"}})); got != 5 {
+			t.Errorf("expected invalid limit and offset to use the defaults, got %d employees", got)
+		}
+
+		byIdentity := list(url.Values{"identityNumber": {"1123333"}})
+		if len(byIdentity) != 1 || byIdentity[0]["identityNumber"] != wildcard {
+			t.Errorf("expected the identity number filter to match a substring, got %v", byIdentity)
+		}
+
+		if got := len(list(url.Values{"gender": {"female"}})); got != 1 {
+			t.Errorf("expected 1 female employee, got %d", got)
+		}
+		if got := len(list(url.Values{"departmentId": {fmt.Sprint(finance)}})); got != 1 {
+			t.Errorf("expected 1 employee in the finance department, got %d", got)
+		}
+		if got := list(url.Values{"gender": {"other"}}); len(got) != 0 {
+			t.Errorf("expected an invalid gender to match nothing, got %v", got)
+		}
+		if got := list(url.Values{"departmentId": {"abc"}}); len(got) != 0 {
+			t.Errorf("expected an invalid department id to match nothing, got %v", got)
+		}
+	})
+
+	t.Run("GET 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodGet, employeePath, nil)
+	})
+
+	t.Run("PATCH 200", func(t *testing.T) {
+		_, token := register(t)
+		departmentID := createDepartment(t, token, "Engineering")["departmentId"]
+		identityNumber := uniqueIdentityNumber("")
+		createEmployee(t, token, employeeBody(identityNumber, "male", departmentID))
+
+		renamed := uniqueIdentityNumber("")
+		res := do(t, http.MethodPatch, employeePath+"/"+identityNumber, token, map[string]string{
+			"identityNumber": renamed,
+			"name":           "Renamed Employee",
+		})
+		expectStatus(t, res, http.StatusOK)
+
+		var employee map[string]interface{}
+		res.JSON(t, &employee)
+		expectKeys(t, employee, employeeKeys...)
+		if employee["identityNumber"] != renamed || employee["name"] != "Renamed Employee" {
+			t.Errorf("expected the employee to be updated, got %v", employee)
+		}
+	})
+
+	t.Run("PATCH 400", func(t *testing.T) {
+		_, token := register(t)
+		departmentID := createDepartment(t, token, "Engineering")["departmentId"]
+		identityNumber := uniqueIdentityNumber("")
+		createEmployee(t, token, employeeBody(identityNumber, "male", departmentID))
+
+		for name, body := range map[string]interface{}{
+			"short identity number": map[string]string{"identityNumber": "1234"},
+			"short name":            map[string]string{"name": "abc"},
+			"invalid image uri":     map[string]string{"employeeImageUri": "not a uri"},
+			"invalid gender":        map[string]string{"gender": "other"},
+			"unknown department":    map[string]int{"departmentId": 999999},
+			"malformed json":        `{"name": `,
+		} {
+			t.Run(name, func(t *testing.T) {
+				expectStatus(t, do(t, http.MethodPatch, employeePath+"/"+identityNumber, token, body), http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("PATCH 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodPatch, employeePath+"/12345", map[string]string{"name": "Renamed Employee"})
+	})
+
+	t.Run("PATCH 404", func(t *testing.T) {
+		_, token := register(t)
+		res := do(t, http.MethodPatch, employeePath+"/"+uniqueIdentityNumber(""), token, map[string]string{"name": "Renamed Employee"})
+		expectStatus(t, res, http.StatusNotFound)
+	})
+
+	t.Run("PATCH 409 for a used identity number", func(t *testing.T) {
+		_, token := register(t)
+		departmentID := createDepartment(t, token, "Engineering")["departmentId"]
+		taken := uniqueIdentityNumber("")
+		identityNumber := uniqueIdentityNumber("")
+		createEmployee(t, token, employeeBody(taken, "male", departmentID))
+		createEmployee(t, token, employeeBody(identityNumber, "male", departmentID))
+
+		res := do(t, http.MethodPatch, employeePath+"/"+identityNumber, token, map[string]string{"identityNumber": taken})
+		expectStatus(t, res, http.StatusConflict)
+	})
+
+	t.Run("DELETE 200", func(t *testing.T) {
+		_, token := register(t)
+		departmentID := createDepartment(t, token, "Engineering")["departmentId"]
+		identityNumber := uniqueIdentityNumber("")
+		createEmployee(t, token, employeeBody(identityNumber, "male", departmentID))
+
+		expectStatus(t, do(t, http.MethodDelete, employeePath+"/"+identityNumber, token, nil), http.StatusOK)
+		expectStatus(t, do(t, http.MethodDelete, employeePath+"/"+identityNumber, token, nil), http.StatusNotFound)
+	})
+
+	t.Run("DELETE 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodDelete, employeePath+"/12345", nil)
+	})
+
+	t.Run("DELETE 404", func(t *testing.T) {
+		_, token := register(t)
+		expectStatus(t, do(t, http.MethodDelete, employeePath+"/"+uniqueIdentityNumber(""), token, nil), http.StatusNotFound)
+	})
+}
@@ -0,0 +1,89 @@
+//go:build contract
+
+package contract
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const filePath = "/v1/file"
+
+// upload sends content as the file field of a multipart form
+func upload(t *testing.T, token string, filename string, content []byte) response {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("build form: %v", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatalf("build form: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("build form: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+filePath, &body)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", filePath, err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	return response{Status: res.StatusCode, Header: res.Header, Body: raw}
+}
+
+// docs/requirements/file_upload_contract.md
+func TestFileContract(t *testing.T) {
+	_, token := register(t)
+
+	t.Run("200", func(t *testing.T) {
+		content := []byte("not really a jpeg")
+		res := upload(t, token, "photo.jpg", content)
+		expectStatus(t, res, http.StatusOK)
+
+		var file map[string]interface{}
+		res.JSON(t, &file)
+		expectKeys(t, file, "fileId", "uri")
+
+		// The URI downloads the file back
+		uri, _ := file["uri"].(string)
+		if !strings.HasSuffix(uri, ".jpg") {
+			t.Errorf("expected the URI to keep the extension, got %q", uri)
+		}
+		download := do(t, http.MethodGet, uri, "", nil)
+		expectStatus(t, download, http.StatusOK)
+		if !bytes.Equal(download.Body, content) {
+			t.Errorf("expected the uploaded content, got %q", download.Body)
+		}
+	})
+
+	t.Run("400 without a file", func(t *testing.T) {
+		expectStatus(t, do(t, http.MethodPost, filePath, token, nil), http.StatusBadRequest)
+	})
+
+	t.Run("400 over 100KiB", func(t *testing.T) {
+		expectStatus(t, upload(t, token, "large.jpg", make([]byte, 100<<10+1)), http.StatusBadRequest)
+	})
+
+	t.Run("401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodPost, filePath, nil)
+	})
+}
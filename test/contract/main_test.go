@@ -0,0 +1,280 @@
+//go:build contract
+
+// Package contract checks the HTTP API against the contracts documented in
+// docs/requirements. The suite boots the real router against a throwaway
+// Postgres database and is run with
+//
+//	CONTRACT_DATABASE_URL=postgres://... go test -tags contract ./test/contract/...
+//
+// CONTRACT_DATABASE_DRIVER=pgx runs the repositories on a pgx pool instead
+// of lib/pq.
+//
+// The database schema is dropped and recreated, never point it at real data.
+package contract
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/lib/pq"
+
+	"github.com/ngikut-project-sprint/GoGoManager/internal/config"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/database"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/health"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/routes"
+	"github.com/ngikut-project-sprint/GoGoManager/internal/utils"
+)
+
+const jwtSecret = "contract-test-secret"
+
+var (
+	server *httptest.Server
+	unique atomic.Int64
+)
+
+func TestMain(m *testing.M) {
+	dsn := os.Getenv("CONTRACT_DATABASE_URL")
+	if dsn == "" {
+		fmt.Println("CONTRACT_DATABASE_URL is not set, skipping contract tests")
+		os.Exit(0)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		fmt.Println("Failed to open database:", err)
+		os.Exit(1)
+	}
+
+	if err := resetSchema(db); err != nil {
+		fmt.Println("Failed to prepare database:", err)
+		os.Exit(1)
+	}
+
+	files, err := os.MkdirTemp("", "contract-files")
+	if err != nil {
+		fmt.Println("Failed to create file directory:", err)
+		os.Exit(1)
+	}
+
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: jwtSecret}, Files: config.FileConfig{Dir: files}}
+	checker := health.NewChecker()
+	checker.Add("database", health.Database(db))
+	checker.Add("migrations", database.CheckMigrations(db))
+	var queries database.DB = &database.SqlDBAdapter{DB: db}
+	var pool *pgxpool.Pool
+	if os.Getenv("CONTRACT_DATABASE_DRIVER") == "pgx" {
+		pool, err = pgxpool.New(context.Background(), dsn)
+		if err != nil {
+			fmt.Println("Failed to open database:", err)
+			os.Exit(1)
+		}
+		queries = &database.PgxAdapter{Pool: pool}
+	}
+	router := routes.NewRouter(cfg, queries, checker, routes.RateLimits{}, routes.Caches{}, routes.Idempotency{})
+	server = httptest.NewServer(routes.NewHandler(router, cfg, slog.New(slog.NewTextHandler(io.Discard, nil))))
+	unique.Store(time.Now().UnixNano() % 1_000_000)
+
+	code := m.Run()
+
+	server.Close()
+	if pool != nil {
+		pool.Close()
+	}
+	db.Close()
+	os.RemoveAll(files)
+	os.Exit(code)
+}
+
+// resetSchema recreates the public schema from init_schema.sql and the
+// migrations, applied in file name order
+func resetSchema(db *sql.DB) error {
+	if _, err := db.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public"); err != nil {
+		return err
+	}
+
+	dir := filepath.Join("..", "..", "internal", "database")
+	migrations, err := filepath.Glob(filepath.Join(dir, "migrations", "*.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(migrations)
+
+	for _, file := range append([]string{filepath.Join(dir, "init_schema.sql")}, migrations...) {
+		script, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(string(script)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// nextID returns a number no other caller of the run gets, for building
+// unique emails and identity numbers
+func nextID() int64 {
+	return unique.Add(1)
+}
+
+// uniqueEmail returns an unused email on a domain with MX records, which
+// registration requires
+func uniqueEmail() string {
+	return fmt.Sprintf("contract%d@gmail.com", nextID())
+}
+
+type response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the body into v, unwrapping the {data, message} envelope some
+// endpoints respond with
+func (r response) JSON(t *testing.T, v interface{}) {
+	t.Helper()
+
+	var envelope map[string]json.RawMessage
+	if json.Unmarshal(r.Body, &envelope) == nil {
+		if data, ok := envelope["data"]; ok {
+			if err := json.Unmarshal(data, v); err != nil {
+				t.Fatalf("decode response data %s: %v", data, err)
+			}
+			return
+		}
+	}
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("decode response %s: %v", r.Body, err)
+	}
+}
+
+// do sends a request with an optional JSON body and bearer token
+func do(t *testing.T, method, path, token string, body interface{}) response {
+	t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("encode request: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, reader)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	return response{Status: res.StatusCode, Header: res.Header, Body: raw}
+}
+
+// expectStatus fails the test when the response doesn't have the status
+func expectStatus(t *testing.T, res response, status int) {
+	t.Helper()
+	if res.Status != status {
+		t.Fatalf("expected status %d, got %d: %s", status, res.Status, res.Body)
+	}
+}
+
+// expectKeys fails the test when the JSON object lacks one of the keys
+func expectKeys(t *testing.T, object map[string]interface{}, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if _, ok := object[key]; !ok {
+			t.Errorf("expected key %q in %v", key, object)
+		}
+	}
+}
+
+// unauthorizedTokens are the expired, invalid and missing tokens every
+// protected endpoint answers with 401
+var unauthorizedTokens = map[string]string{
+	"missing": "",
+	"invalid": "not-a-jwt",
+	"expired": expiredToken(),
+}
+
+// expectUnauthorized checks the endpoint rejects every unauthorized token
+func expectUnauthorized(t *testing.T, method, path string, body interface{}) {
+	t.Helper()
+	for name, token := range unauthorizedTokens {
+		t.Run(name+" token", func(t *testing.T) {
+			expectStatus(t, do(t, method, path, token, body), http.StatusUnauthorized)
+		})
+	}
+}
+
+// expiredToken returns a correctly signed token that expired an hour ago
+func expiredToken() string {
+	claims := &utils.Claims{
+		ID:    1,
+		Email: "expired@gmail.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwtSecret))
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// register creates a manager and returns its email and token
+func register(t *testing.T) (string, string) {
+	t.Helper()
+
+	email := uniqueEmail()
+	res := do(t, http.MethodPost, "/v1/auth", "", map[string]string{
+		"email":    email,
+		"password": "password123",
+		"action":   "create",
+	})
+	expectStatus(t, res, http.StatusCreated)
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	res.JSON(t, &body)
+	return email, body.Token
+}
@@ -0,0 +1,83 @@
+//go:build contract
+
+package contract
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var profileKeys = []string{"email", "name", "userImageUri", "companyName", "companyImageUri"}
+
+// docs/requirements/profile_contract.md
+func TestProfileContract(t *testing.T) {
+	t.Run("GET 200", func(t *testing.T) {
+		email, token := register(t)
+		res := do(t, http.MethodGet, "/v1/user", token, nil)
+		expectStatus(t, res, http.StatusOK)
+
+		var body map[string]interface{}
+		res.JSON(t, &body)
+		expectKeys(t, body, profileKeys...)
+		if body["email"] != email {
+			t.Errorf("expected email %s, got %v", email, body["email"])
+		}
+	})
+
+	t.Run("GET 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodGet, "/v1/user", nil)
+	})
+
+	t.Run("PATCH 200", func(t *testing.T) {
+		_, token := register(t)
+		email := uniqueEmail()
+		update := map[string]string{
+			"email":           email,
+			"name":            "Contract Manager",
+			"userImageUri":    "https://example.com/user.png",
+			"companyName":     "Contract Company",
+			"companyImageUri": "https://example.com/company.png",
+		}
+
+		res := do(t, http.MethodPatch, "/v1/user", token, update)
+		expectStatus(t, res, http.StatusOK)
+
+		var body map[string]interface{}
+		res.JSON(t, &body)
+		expectKeys(t, body, profileKeys...)
+		for key, value := range update {
+			if body[key] != value {
+				t.Errorf("expected %s %q, got %v", key, value, body[key])
+			}
+		}
+	})
+
+	t.Run("PATCH 400", func(t *testing.T) {
+		_, token := register(t)
+		for name, body := range map[string]interface{}{
+			"invalid email":             map[string]string{"email": "not-an-email"},
+			"short name":                map[string]string{"name": "abc"},
+			"long name":                 map[string]string{"name": strings.Repeat("a", 53)},
+			"invalid user image uri":    map[string]string{"userImageUri": "not a uri"},
+			"short company name":        map[string]string{"companyName": "abc"},
+			"invalid company image uri": map[string]string{"companyImageUri": "not a uri"},
+			"malformed json":            `{"name": `,
+		} {
+			t.Run(name, func(t *testing.T) {
+				expectStatus(t, do(t, http.MethodPatch, "/v1/user", token, body), http.StatusBadRequest)
+			})
+		}
+	})
+
+	t.Run("PATCH 401", func(t *testing.T) {
+		expectUnauthorized(t, http.MethodPatch, "/v1/user", map[string]string{"name": "Contract Manager"})
+	})
+
+	t.Run("PATCH 409 for an email used by another manager", func(t *testing.T) {
+		taken, _ := register(t)
+		_, token := register(t)
+		res := do(t, http.MethodPatch, "/v1/user", token, map[string]string{"email": taken})
+		expectStatus(t, res, http.StatusConflict)
+	})
+}